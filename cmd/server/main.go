@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -13,6 +14,7 @@ import (
 	_ "github.com/lib/pq"
 
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
 )
 
 	func main() {
@@ -20,6 +22,16 @@ import (
 		log.Printf("Warning: failed to load .env file: %v", err)
 	}
 
+	printConfig := flag.Bool("print-config", false, "print the effective config with secrets redacted and exit")
+	flag.Parse()
+
+	if *printConfig {
+		if err := config.PrintConfig(bootstrap.ConfigPath(), os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	app, err := bootstrap.NewApp()
 	if err != nil {
 		log.Fatal(err)
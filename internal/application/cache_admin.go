@@ -0,0 +1,34 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/cache_admin.go
+package application
+
+import (
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// Операции администратора затрагивают только кэш этого экземпляра: БД не меняется,
+// поэтому событий инвалидации для других экземпляров нет
+
+func (s *orderService) CacheStats() entities.CacheStats {
+	return s.cache.Stats()
+}
+
+// ClearCache очищает кэш и список отсутствующих ID, не трогая БД
+func (s *orderService) ClearCache() {
+	s.fills.writeAll(func() {
+		s.cache.Clear()
+		s.notFound.reset()
+	})
+	s.logger.Info("cache cleared by admin")
+}
+
+// EvictCachedOrder убирает заказ из кэша; следующее чтение возьмет его из БД
+func (s *orderService) EvictCachedOrder(id string) bool {
+	var evicted bool
+	s.fills.write(id, func() {
+		s.inflight.Forget(id)
+		s.notFound.remove(id)
+		evicted = s.cache.Delete(id)
+	})
+	s.logger.Info("order evicted from cache by admin", "order_id", id, "evicted", evicted)
+	return evicted
+}
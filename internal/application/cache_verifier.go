@@ -0,0 +1,118 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/cache_verifier.go
+package application
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+const (
+	defaultVerifySample = 100
+	// verifyPoolLimit ограничивает число записей кэша, из которых берется выборка
+	verifyPoolLimit = 10000
+	// maxReportedDivergence ограничивает список ID в отчете сверки
+	maxReportedDivergence = 20
+)
+
+// WithCacheVerification задает выборку сверки кэша с БД. С repair расхождения чинятся:
+// устаревший заказ заменяется версией из БД, удаленный - убирается из кэша;
+// без repair о них только сообщается
+func WithCacheVerification(sample int, repair bool) Option {
+	return func(s *orderService) {
+		s.verifySample = sample
+		s.verifyRepair = repair
+	}
+}
+
+// VerifyCache сверяет случайную выборку закэшированных заказов с БД.
+// При отмене ctx возвращает частичный отчет вместе с ошибкой
+func (s *orderService) VerifyCache(ctx context.Context) (entities.CacheVerification, error) {
+	if !s.verifyMu.TryLock() {
+		return entities.CacheVerification{}, ErrCacheVerificationInProgress
+	}
+	defer s.verifyMu.Unlock()
+
+	report := entities.CacheVerification{StartedAt: time.Now().UTC()}
+	sample := s.cache.GetAll(verifyPoolLimit)
+	if len(sample) > s.sampleSize() {
+		rand.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+		sample = sample[:s.sampleSize()]
+	}
+
+	var err error
+	for _, cached := range sample {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		s.verifyOrder(ctx, cached, &report)
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	fields := []interface{}{
+		"checked", report.Checked,
+		"stale", report.Stale,
+		"missing", report.Missing,
+		"failed", report.Failed,
+		"repaired", report.Repaired,
+		"duration", report.FinishedAt.Sub(report.StartedAt),
+	}
+	if report.Consistent() {
+		s.logger.Info("cache verification completed", fields...)
+	} else {
+		s.logger.Warn("cache diverged from database", append(fields, "diverged", report.Diverged)...)
+	}
+	return report, err
+}
+
+func (s *orderService) sampleSize() int {
+	if s.verifySample <= 0 {
+		return defaultVerifySample
+	}
+	return s.verifySample
+}
+
+func (s *orderService) verifyOrder(ctx context.Context, cached entities.Order, report *entities.CacheVerification) {
+	id := cached.OrderUID
+	ticket := s.fills.begin(id)
+	repair := func() {}
+
+	dbOrder, err := s.repo.GetOrder(ctx, id)
+	report.Checked++
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		report.Missing++
+		s.reportDivergence(report, id, "missing")
+		repair = func() {
+			s.cache.Delete(id)
+			report.Repaired++
+		}
+	case err != nil:
+		report.Failed++
+		s.logger.Warn("failed to verify cached order", "order_id", id, "error", err)
+	case !dbOrder.EqualContent(cached):
+		report.Stale++
+		s.reportDivergence(report, id, "stale")
+		repair = func() {
+			s.cache.Set(id, dbOrder)
+			report.Repaired++
+		}
+	}
+
+	if !s.verifyRepair {
+		repair = func() {}
+	}
+	// заказ записали во время сверки - кэш уже обновлен записью, чинить нечего
+	s.fills.fill(ticket, repair)
+}
+
+func (s *orderService) reportDivergence(report *entities.CacheVerification, id, kind string) {
+	if len(report.Diverged) < maxReportedDivergence {
+		report.Diverged = append(report.Diverged, id)
+	}
+	s.logger.Warn("cached order diverged from database", "order_id", id, "kind", kind)
+}
@@ -0,0 +1,104 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/coalescing.go
+package application
+
+import (
+	"sync"
+	"time"
+)
+
+// notFoundCacheLimit ограничивает число запомненных несуществующих ID,
+// чтобы перебор случайных ID не раздувал память
+const notFoundCacheLimit = 10000
+
+// WithNegativeCaching запоминает ID, которых нет в БД, на ttl;
+// повторные запросы таких ID не доходят до репозитория
+func WithNegativeCaching(ttl time.Duration) Option {
+	return func(s *orderService) {
+		if ttl > 0 {
+			s.notFound = newNotFoundCache(ttl, notFoundCacheLimit, time.Now)
+		}
+	}
+}
+
+// WithReplicaReads сообщает, что чтения по ID могут идти в реплики: перед записью
+// в негативный кэш промах перепроверяется в primary
+func WithReplicaReads() Option {
+	return func(s *orderService) {
+		s.replicaReads = true
+	}
+}
+
+type notFoundCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	limit   int
+	entries map[string]time.Time
+	now     func() time.Time
+}
+
+func newNotFoundCache(ttl time.Duration, limit int, now func() time.Time) *notFoundCache {
+	return &notFoundCache{
+		ttl:     ttl,
+		limit:   limit,
+		entries: make(map[string]time.Time),
+		now:     now,
+	}
+}
+
+func (c *notFoundCache) contains(id string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt, ok := c.entries[id]
+	if !ok {
+		return false
+	}
+	if !c.now().Before(expiresAt) {
+		delete(c.entries, id)
+		return false
+	}
+	return true
+}
+
+func (c *notFoundCache) add(id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= c.limit {
+		for key, expiresAt := range c.entries {
+			if !now.Before(expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= c.limit {
+			// все записи свежие - проще начать заново, чем вести порядок вытеснения
+			clear(c.entries)
+		}
+	}
+	c.entries[id] = now.Add(c.ttl)
+}
+
+func (c *notFoundCache) remove(id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
+
+func (c *notFoundCache) reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
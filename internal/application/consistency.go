@@ -0,0 +1,137 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/consistency.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+type ConsistencyAction string
+
+const (
+	ConsistencyOff    ConsistencyAction = "off"
+	ConsistencyReject ConsistencyAction = "reject"
+	ConsistencyWarn   ConsistencyAction = "warn"
+	ConsistencyFlag   ConsistencyAction = "flag"
+)
+
+func ParseConsistencyAction(s string) (ConsistencyAction, error) {
+	switch a := ConsistencyAction(s); a {
+	case ConsistencyOff, ConsistencyReject, ConsistencyWarn, ConsistencyFlag:
+		return a, nil
+	case "":
+		return ConsistencyOff, nil
+	default:
+		return "", fmt.Errorf("unknown consistency action %q", s)
+	}
+}
+
+// ConsistencyPolicy задает действие для каждого правила; правила без записи выключены
+type ConsistencyPolicy map[entities.ConsistencyRule]ConsistencyAction
+
+func (p ConsistencyPolicy) action(rule entities.ConsistencyRule) ConsistencyAction {
+	if a, ok := p[rule]; ok {
+		return a
+	}
+	return ConsistencyOff
+}
+
+func (p ConsistencyPolicy) flags() bool {
+	for _, a := range p {
+		if a == ConsistencyFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConsistency возвращает ошибку, если сработало хотя бы одно reject-правило,
+// и список флагов для сохранения после успешной записи заказа
+func (s *orderService) checkConsistency(order entities.Order) ([]entities.OrderFlag, error) {
+	if len(s.consistency) == 0 {
+		return nil, nil
+	}
+
+	var rejected entities.ValidationErrors
+	var flags []entities.OrderFlag
+	now := time.Now().UTC()
+
+	for _, inc := range order.CheckConsistency() {
+		action := s.consistency.action(inc.Rule)
+		if action == ConsistencyFlag && s.flagRepo == nil {
+			action = ConsistencyWarn
+		}
+
+		switch action {
+		case ConsistencyReject:
+			rejected = append(rejected, inc.FieldError)
+		case ConsistencyWarn:
+			s.logger.Warn("order consistency check failed",
+				"order_id", order.OrderUID,
+				"rule", string(inc.Rule),
+				"field", inc.Field,
+				"error", inc.Message,
+			)
+		case ConsistencyFlag:
+			flags = append(flags, entities.OrderFlag{
+				OrderUID:  order.OrderUID,
+				Rule:      inc.Rule,
+				Field:     inc.Field,
+				Message:   inc.Message,
+				CreatedAt: now,
+			})
+		}
+	}
+
+	if len(rejected) > 0 {
+		s.logger.Warn("order rejected by consistency checks",
+			"order_id", order.OrderUID,
+			"error", rejected,
+		)
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidOrder, rejected)
+	}
+
+	return flags, nil
+}
+
+func (s *orderService) saveFlags(ctx context.Context, orderID string, flags []entities.OrderFlag) error {
+	if s.flagRepo == nil || !s.consistency.flags() {
+		return nil
+	}
+
+	if err := s.flagRepo.ReplaceFlags(ctx, orderID, flags); err != nil {
+		s.logger.Error("failed to save order flags",
+			"order_id", orderID,
+			"flags", len(flags),
+			"error", err,
+		)
+		return err
+	}
+
+	if len(flags) > 0 {
+		s.logger.Info("order flagged for review",
+			"order_id", orderID,
+			"flags", len(flags),
+		)
+	}
+	return nil
+}
+
+func (s *orderService) GetFlaggedOrders(ctx context.Context, limit, offset int) ([]entities.OrderFlag, error) {
+	const op = "OrderService.GetFlaggedOrders"
+
+	if s.flagRepo == nil {
+		return []entities.OrderFlag{}, nil
+	}
+
+	flags, err := s.flagRepo.GetFlags(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to retrieve order flags", "error", err)
+		return nil, NewAppError(ErrCodeFlagsReadFailed, "failed to retrieve order flags", op, err)
+	}
+	return flags, nil
+}
@@ -0,0 +1,100 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/customer_data.go
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// WithCustomerData включает выгрузку и удаление данных клиента по customer_id
+func WithCustomerData(repo domainrepo.CustomerDataRepository) Option {
+	return func(s *orderService) {
+		s.customers = repo
+	}
+}
+
+// ExportCustomerData собирает все заказы клиента (в расшифрованном виде)
+// и журнал ранее выполненных удалений
+func (s *orderService) ExportCustomerData(ctx context.Context, customerID string) (entities.CustomerExport, error) {
+	const op = "OrderService.ExportCustomerData"
+
+	if s.customers == nil {
+		return entities.CustomerExport{}, NewAppError(ErrCodeCustomerExportFailed, "customer data export is disabled", op, ErrCustomerDataDisabled)
+	}
+
+	ids, err := s.customers.GetCustomerOrderIDs(ctx, customerID)
+	if err != nil {
+		return entities.CustomerExport{}, NewAppError(ErrCodeCustomerExportFailed, "failed to find customer orders", op, err)
+	}
+
+	orders := make([]entities.Order, 0, len(ids))
+	for _, id := range ids {
+		order, err := s.repo.GetOrder(ctx, id)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			// заказ удален между запросами
+			continue
+		}
+		if err != nil {
+			return entities.CustomerExport{}, NewAppError(ErrCodeCustomerExportFailed, "failed to read customer order", op, err)
+		}
+		orders = append(orders, order)
+	}
+
+	erasures, err := s.customers.GetErasures(ctx, customerID)
+	if err != nil {
+		return entities.CustomerExport{}, NewAppError(ErrCodeCustomerExportFailed, "failed to read erasure log", op, err)
+	}
+
+	s.logger.Info("customer data exported",
+		"customer_id", customerID,
+		"orders", len(orders),
+	)
+
+	return entities.CustomerExport{
+		CustomerID: customerID,
+		ExportedAt: time.Now().UTC(),
+		Orders:     orders,
+		Erasures:   erasures,
+	}, nil
+}
+
+// EraseCustomerData анонимизирует доставку во всех заказах клиента; финансовые поля,
+// товары и customer_id сохраняются. Закэшированные копии удаляются, чтобы
+// следующее чтение взяло анонимизированную версию из БД
+func (s *orderService) EraseCustomerData(ctx context.Context, customerID, requestedBy string) (entities.Erasure, error) {
+	const op = "OrderService.EraseCustomerData"
+
+	if s.customers == nil {
+		return entities.Erasure{}, NewAppError(ErrCodeCustomerEraseFailed, "customer data erasure is disabled", op, ErrCustomerDataDisabled)
+	}
+
+	erasure, err := s.customers.EraseCustomer(ctx, entities.Erasure{
+		CustomerID:  customerID,
+		RequestedBy: requestedBy,
+	})
+	if err != nil {
+		s.logger.Error("failed to erase customer data",
+			"customer_id", customerID,
+			"error", err,
+		)
+		return entities.Erasure{}, NewAppError(ErrCodeCustomerEraseFailed, "failed to erase customer data", op, err)
+	}
+
+	for _, id := range erasure.OrderUIDs {
+		s.fills.write(id, func() { s.cache.Delete(id) })
+		s.publishCacheEvent(ctx, entities.CacheEventDelete, id)
+	}
+
+	s.logger.Info("customer data erased",
+		"customer_id", customerID,
+		"orders", len(erasure.OrderUIDs),
+		"erasure_id", erasure.ID,
+		"requested_by", requestedBy,
+	)
+	return erasure, nil
+}
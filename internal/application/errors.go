@@ -9,11 +9,15 @@ import (
 type ErrorCode string
 
 const (
-	ErrCodeOrderSaveFailed   ErrorCode = "order_save_failed"
-	ErrCodeOrderDeleteFailed ErrorCode = "order_delete_failed"
-	ErrCodeOrderReadFailed   ErrorCode = "order_read_failed"
-	ErrCodeOrdersReadFailed  ErrorCode = "orders_read_failed"
-	ErrCodeValidation        ErrorCode = "validation_error"
+	ErrCodeOrderSaveFailed      ErrorCode = "order_save_failed"
+	ErrCodeOrderDeleteFailed    ErrorCode = "order_delete_failed"
+	ErrCodeOrderReadFailed      ErrorCode = "order_read_failed"
+	ErrCodeOrdersReadFailed     ErrorCode = "orders_read_failed"
+	ErrCodeValidation           ErrorCode = "validation_error"
+	ErrCodeFlagsReadFailed      ErrorCode = "flags_read_failed"
+	ErrCodeFlagsSaveFailed      ErrorCode = "flags_save_failed"
+	ErrCodeCustomerExportFailed ErrorCode = "customer_export_failed"
+	ErrCodeCustomerEraseFailed  ErrorCode = "customer_erase_failed"
 )
 
 type AppError struct {
@@ -44,7 +48,10 @@ func NewAppError(code ErrorCode, message string, op string, err error) *AppError
 }
 
 var (
-	ErrOrderSaveFailed   = errors.New("failed to save order")
-	ErrOrderDeleteFailed = errors.New("failed to delete order")
-	ErrOrderReadFailed   = errors.New("failed to read order")
+	ErrOrderSaveFailed      = errors.New("failed to save order")
+	ErrOrderDeleteFailed    = errors.New("failed to delete order")
+	ErrOrderReadFailed      = errors.New("failed to read order")
+	ErrCustomerDataDisabled = errors.New("customer data repository is not configured")
+
+	ErrCacheVerificationInProgress = errors.New("cache verification already in progress")
 )
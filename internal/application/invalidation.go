@@ -0,0 +1,82 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/invalidation.go
+package application
+
+import (
+	"context"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// WithCacheInvalidation рассылает изменения заказов другим экземплярам через bus;
+// instanceID отличает собственные события от чужих
+func WithCacheInvalidation(bus domainrepo.CacheInvalidationBus, instanceID string) Option {
+	return func(s *orderService) {
+		s.bus = bus
+		s.instanceID = instanceID
+	}
+}
+
+func (s *orderService) publishCacheEvent(ctx context.Context, eventType entities.CacheEventType, orderUID string) {
+	if s.bus == nil {
+		return
+	}
+
+	event := entities.CacheEvent{Type: eventType, OrderUID: orderUID, Origin: s.instanceID}
+	if err := s.bus.Publish(context.WithoutCancel(ctx), event); err != nil {
+		// запись уже выполнена; другие экземпляры догонят по TTL кэша
+		s.logger.Error("failed to publish cache invalidation",
+			"type", string(eventType),
+			"order_id", orderUID,
+			"error", err,
+		)
+	}
+}
+
+// HandleCacheEvent применяет событие другого экземпляра: локальная копия заказа
+// сбрасывается и при следующем чтении берется из общего кэша или БД. Общий уровень (Redis)
+// не трогается: источник события уже записал в него новую версию
+func (s *orderService) HandleCacheEvent(event entities.CacheEvent) {
+	if event.Origin != "" && event.Origin == s.instanceID {
+		return
+	}
+
+	switch event.Type {
+	case entities.CacheEventSet, entities.CacheEventDelete:
+		s.fills.write(event.OrderUID, func() {
+			s.inflight.Forget(event.OrderUID)
+			s.notFound.remove(event.OrderUID)
+			s.evictLocal(event.OrderUID)
+		})
+	case entities.CacheEventClear:
+		s.fills.writeAll(func() {
+			s.clearLocal()
+			s.notFound.reset()
+		})
+	default:
+		s.logger.Warn("unknown cache invalidation event", "type", string(event.Type), "origin", event.Origin)
+		return
+	}
+
+	s.logger.Debug("cache invalidated by remote event",
+		"type", string(event.Type),
+		"order_id", event.OrderUID,
+		"origin", event.Origin,
+	)
+}
+
+func (s *orderService) evictLocal(orderID string) {
+	if local, ok := s.cache.(domainrepo.LocalCache); ok {
+		local.EvictLocal(orderID)
+		return
+	}
+	s.cache.Delete(orderID)
+}
+
+func (s *orderService) clearLocal() {
+	if local, ok := s.cache.(domainrepo.LocalCache); ok {
+		local.ClearLocal()
+		return
+	}
+	s.cache.Clear()
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
@@ -17,15 +20,52 @@ type orderService struct {
 	logger      domainrepo.Logger
 	repo        domainrepo.OrderRepository
 	getAllLimit int
+	consistency ConsistencyPolicy
+	flagRepo    domainrepo.OrderFlagRepository
+
+	converter         domainrepo.CurrencyConverter
+	reportingCurrency entities.Currency
+
+	customers domainrepo.CustomerDataRepository
+
+	// конкурентные промахи по одному ID разделяют один запрос к БД
+	inflight     singleflight.Group
+	notFound     *notFoundCache
+	replicaReads bool
+
+	bus        domainrepo.CacheInvalidationBus
+	instanceID string
+
+	readMode ReadConsistency
+	fills    *cacheFillGuard
+
+	verifySample int
+	verifyRepair bool
+	verifyMu     sync.Mutex
 }
 
-func NewOrderService(c domainrepo.Cache, l domainrepo.Logger, r domainrepo.OrderRepository, limit int) OrderServiceInterface {
-	return &orderService{
+type Option func(*orderService)
+
+// WithConsistencyChecks включает проверки согласованности платежа и товаров;
+// без flagRepo действие flag понижается до warn
+func WithConsistencyChecks(policy ConsistencyPolicy, flagRepo domainrepo.OrderFlagRepository) Option {
+	return func(s *orderService) {
+		s.consistency = policy
+		s.flagRepo = flagRepo
+	}
+}
+
+func NewOrderService(c domainrepo.Cache, l domainrepo.Logger, r domainrepo.OrderRepository, limit int, opts ...Option) OrderServiceInterface {
+	s := &orderService{
 		cache:       c,
 		logger:      l,
 		repo:        r,
 		getAllLimit: limit,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type OrderResult string
@@ -45,13 +85,33 @@ func (s *orderService) SaveOrder(ctx context.Context, order entities.Order) (Ord
 		return "", NewAppError(ErrCodeValidation, "order validation failed", op, err)
 	}
 
+	flags, err := s.checkConsistency(order)
+	if err != nil {
+		return "", NewAppError(ErrCodeValidation, "order consistency check failed", op, err)
+	}
+
 	result := s.determineOrderResult(order)
 
 	if err := s.saveToRepo(ctx, order); err != nil {
 		return "", NewAppError(ErrCodeOrderSaveFailed, "failed to save order", op, err)
 	}
 
-	s.updateCache(order, result)
+	flagsErr := s.saveFlags(ctx, order.OrderUID, flags)
+
+	s.fills.write(order.OrderUID, func() {
+		s.notFound.remove(order.OrderUID)
+		s.inflight.Forget(order.OrderUID)
+		s.updateCache(order, result)
+	})
+	if result != OrderExists {
+		s.publishCacheEvent(ctx, entities.CacheEventSet, order.OrderUID)
+	}
+
+	// заказ уже сохранён, но без флагов его нельзя считать обработанным:
+	// возвращаем ошибку, чтобы повторная доставка перезаписала флаги
+	if flagsErr != nil {
+		return "", NewAppError(ErrCodeFlagsSaveFailed, "failed to save order flags", op, flagsErr)
+	}
 
 	return result, nil
 }
@@ -62,7 +122,7 @@ func (s *orderService) validateOrder(order entities.Order) error {
 			"order_id", order.OrderUID,
 			"error", err,
 		)
-		return fmt.Errorf("%w: %v", domain.ErrInvalidOrder, err)
+		return fmt.Errorf("%w: %w", domain.ErrInvalidOrder, err)
 	}
 	return nil
 }
@@ -118,7 +178,12 @@ func (s *orderService) GetOrder(ctx context.Context, id string) (entities.Order,
 		return order, nil
 	}
 
-	dbOrder, err := s.fetchFromRepo(ctx, id)
+	if s.notFound.contains(id) {
+		s.logger.Debug("order not found (negative cache)", "order_id", id)
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+
+	dbOrder, err := s.fetchCoalesced(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrOrderNotFound) {
 			s.logger.Warn("order not found in db",
@@ -130,11 +195,65 @@ func (s *orderService) GetOrder(ctx context.Context, id string) (entities.Order,
 		return entities.Order{}, NewAppError(ErrCodeOrderReadFailed, "failed to retrieve order", op, err)
 	}
 
-	s.cache.Set(dbOrder.OrderUID, dbOrder)
 	s.logger.Info("order retrieved from db and cached", "order_id", id)
 	return dbOrder, nil
 }
 
+// fetchCoalesced выполняет один запрос к БД на все конкурентные промахи по id.
+// Запрос не отменяется, если ушел ждавший его клиент: результат нужен остальным
+// и попадет в кэш; каждый вызывающий при этом ждет не дольше своего ctx.
+// Дедлайн первого вызывающего запрос сохраняет, см. detachedContext
+func (s *orderService) fetchCoalesced(ctx context.Context, id string) (entities.Order, error) {
+	ch := s.inflight.DoChan(id, func() (interface{}, error) {
+		fetchCtx, cancel := detachedContext(ctx)
+		defer cancel()
+
+		ticket := s.fills.begin(id)
+		order, err := s.fetchFromRepo(fetchCtx, id)
+		if errors.Is(err, domain.ErrOrderNotFound) && s.notFound != nil && s.replicaReads {
+			// реплика могла не догнать запись другого экземпляра: промах запоминается
+			// на negative_ttl, только если заказа нет и в primary
+			order, err = s.fetchFromRepo(domainrepo.WithPrimaryRead(fetchCtx), id)
+		}
+		fresh := s.fills.fill(ticket, func() {
+			switch {
+			case errors.Is(err, domain.ErrOrderNotFound):
+				s.notFound.add(id)
+			case err == nil:
+				s.cache.Set(order.OrderUID, order)
+			}
+		})
+		if !fresh {
+			s.logger.Debug("order changed during fetch, cache not filled", "order_id", id)
+		}
+		return order, err
+	})
+
+	select {
+	case res := <-ch:
+		if res.Shared {
+			s.logger.Debug("order fetch shared between concurrent requests", "order_id", id)
+		}
+		if res.Err != nil {
+			return entities.Order{}, res.Err
+		}
+		return res.Val.(entities.Order), nil
+	case <-ctx.Done():
+		return entities.Order{}, ctx.Err()
+	}
+}
+
+// detachedContext отвязывает запрос от отмены вызывающего, но не от его дедлайна:
+// брошенная выборка укладывается в бюджет запроса (server.request_timeout),
+// а не в полный бюджет повторов database.retry.reads
+func detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return detached, func() {}
+}
+
 func (s *orderService) fetchFromRepo(ctx context.Context, id string) (entities.Order, error) {
 	const op = "OrderService.fetchFromRepo"
 	
@@ -162,11 +281,17 @@ func (s *orderService) DeleteOrder(ctx context.Context, id string) error {
 		return NewAppError(ErrCodeOrderDeleteFailed, "failed to delete order", op, err)
 	}
 
-	deleted := s.cache.Delete(id)
+	var deleted bool
+	s.fills.write(id, func() {
+		s.inflight.Forget(id)
+		deleted = s.cache.Delete(id)
+	})
 	if !deleted {
 		s.logger.Warn("order not found in cache during deletion", "order_id", id)
 	}
 
+	s.publishCacheEvent(ctx, entities.CacheEventDelete, id)
+
 	s.logger.Info("order deleted", "order_id", id)
 	return nil
 }
@@ -174,13 +299,16 @@ func (s *orderService) DeleteOrder(ctx context.Context, id string) error {
 func (s *orderService) ClearOrders(ctx context.Context) error {
 	const op = "OrderService.ClearOrders"
 	
-	s.cache.Clear()
-
 	if err := s.repo.ClearOrders(ctx); err != nil {
 		s.logger.Error("failed to clear orders from db", "error", err)
 		return NewAppError(ErrCodeOrderDeleteFailed, "failed to clear orders", op, err)
 	}
 
+	// кэш очищается после коммита: иначе чтения во время удаления вернули бы в него заказы
+	s.fills.writeAll(s.cache.Clear)
+
+	s.publishCacheEvent(ctx, entities.CacheEventClear, "")
+
 	s.logger.Info("all orders cleared")
 	return nil
 }
@@ -188,6 +316,10 @@ func (s *orderService) ClearOrders(ctx context.Context) error {
 func (s *orderService) GetAllOrders(ctx context.Context) ([]entities.Order, error) {
 	const op = "OrderService.GetAllOrders"
 	
+	if s.readMode == ReadConsistencyStrict {
+		return s.listFromRepo(ctx)
+	}
+
 	ordersFromCache := s.cache.GetAll(s.getAllLimit)
 
 	var orders []entities.Order
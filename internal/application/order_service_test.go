@@ -4,10 +4,16 @@ package application_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/application"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache"
+	repository "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -24,6 +30,7 @@ func (m *mockCache) GetAll(limit int) []entities.Order {
 	args := m.Called(limit)
 	return args.Get(0).([]entities.Order)
 }
+func (m *mockCache) Stats() entities.CacheStats { return m.Called().Get(0).(entities.CacheStats) }
 
 func (m *mockCache) Shutdown(ctx context.Context) error { return m.Called(ctx).Error(0) }
 
@@ -44,6 +51,14 @@ func (m *mockRepo) GetOrdersCount(ctx context.Context) (int, error) {
 	args := m.Called(ctx)
 	return args.Int(0), args.Error(1)
 }
+func (m *mockRepo) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(entities.OrderCursor), args.Error(1)
+}
+func (m *mockRepo) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]entities.Order), args.Error(1)
+}
 func (m *mockRepo) DeleteOrder(ctx context.Context, id string) error {
 	return m.Called(ctx, id).Error(0)
 }
@@ -61,13 +76,21 @@ func (m *mockLogger) Shutdown(ctx context.Context) error      { return m.Called(
 
 func sampleOrder() entities.Order {
 	return entities.Order{
-		OrderUID:    "123",
-		TrackNumber: "TRACK123",
+		OrderUID:        "123",
+		TrackNumber:     "TRACK123",
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		DateCreated:     entities.NewTimestamp(time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)),
 		Items: []entities.Item{
-			{ChrtID: 1, TrackNumber: "TRACK123", Price: 100, RID: "RID1", Name: "item1", TotalPrice: 100, NmID: 1},
+			{ChrtID: 1, TrackNumber: "TRACK123", Price: 100, RID: "RID1", Name: "item1", TotalPrice: 100, NmID: 1, Brand: "brand"},
 		},
-		Delivery: entities.Delivery{Name: "John Doe", Email: "test@example.com", Phone: "+123456"},
-		Payment:  entities.Payment{Amount: 100},
+		Delivery: entities.Delivery{
+			Name: "John Doe", Email: "test@example.com", Phone: "+123456",
+			Zip: "2639809", City: "Kiryat Mozkin", Address: "Ploshad Mira 15", Region: "Kraiot",
+		},
+		Payment: entities.Payment{Transaction: "123", Currency: "USD", Provider: "wbpay", Amount: 100, GoodsTotal: 100},
 	}
 }
 
@@ -199,3 +222,646 @@ func TestGetAllOrders_FromRepo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, orders, got)
 }
+
+func TestSaveOrder_ValidationError(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	order := sampleOrder()
+	order.OrderUID = ""
+	order.Items[0].Price = -1
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10)
+	_, err := s.SaveOrder(context.Background(), order)
+
+	var verrs entities.ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Len(t, verrs, 2)
+	assert.ErrorIs(t, err, domain.ErrInvalidOrder)
+	repo.AssertNotCalled(t, "SaveOrder", mock.Anything, mock.Anything)
+}
+
+type mockFlagRepo struct{ mock.Mock }
+
+func (m *mockFlagRepo) ReplaceFlags(ctx context.Context, orderUID string, flags []entities.OrderFlag) error {
+	return m.Called(ctx, orderUID, flags).Error(0)
+}
+func (m *mockFlagRepo) GetFlags(ctx context.Context, limit, offset int) ([]entities.OrderFlag, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]entities.OrderFlag), args.Error(1)
+}
+
+func TestSaveOrder_ConsistencyReject(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	order := sampleOrder()
+	order.Payment.GoodsTotal = 50
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+
+	policy := application.ConsistencyPolicy{entities.RuleGoodsTotal: application.ConsistencyReject}
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithConsistencyChecks(policy, nil))
+	_, err := s.SaveOrder(context.Background(), order)
+
+	var verrs entities.ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.ErrorIs(t, err, entities.ErrGoodsTotalMismatch)
+	repo.AssertNotCalled(t, "SaveOrder", mock.Anything, mock.Anything)
+}
+
+func TestSaveOrder_ConsistencyFlag(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+	flagRepo := new(mockFlagRepo)
+
+	order := sampleOrder()
+	order.Payment.Amount = 150
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	repo.On("SaveOrder", mock.Anything, order).Return(nil)
+	flagRepo.On("ReplaceFlags", mock.Anything, order.OrderUID, mock.MatchedBy(func(flags []entities.OrderFlag) bool {
+		return len(flags) == 1 && flags[0].Rule == entities.RulePaymentAmount
+	})).Return(nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	policy := application.ConsistencyPolicy{
+		entities.RulePaymentAmount: application.ConsistencyFlag,
+		entities.RuleGoodsTotal:    application.ConsistencyReject,
+	}
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithConsistencyChecks(policy, flagRepo))
+	res, err := s.SaveOrder(context.Background(), order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, application.OrderCreated, res)
+	flagRepo.AssertExpectations(t)
+}
+
+func TestSaveOrder_FlagsSaveErrorReturned(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+	flagRepo := new(mockFlagRepo)
+
+	order := sampleOrder()
+	order.Payment.Amount = 150
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	repo.On("SaveOrder", mock.Anything, order).Return(nil)
+	flagRepo.On("ReplaceFlags", mock.Anything, order.OrderUID, mock.Anything).Return(errors.New("db down"))
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+
+	policy := application.ConsistencyPolicy{entities.RulePaymentAmount: application.ConsistencyFlag}
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithConsistencyChecks(policy, flagRepo))
+	_, err := s.SaveOrder(context.Background(), order)
+
+	var appErr *application.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, application.ErrCodeFlagsSaveFailed, appErr.Code)
+	assert.NotErrorIs(t, err, domain.ErrInvalidOrder)
+	flagRepo.AssertExpectations(t)
+}
+
+type mockCustomerRepo struct{ mock.Mock }
+
+func (m *mockCustomerRepo) GetCustomerOrderIDs(ctx context.Context, customerID string) ([]string, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]string), args.Error(1)
+}
+func (m *mockCustomerRepo) EraseCustomer(ctx context.Context, erasure entities.Erasure) (entities.Erasure, error) {
+	args := m.Called(ctx, erasure)
+	return args.Get(0).(entities.Erasure), args.Error(1)
+}
+func (m *mockCustomerRepo) GetErasures(ctx context.Context, customerID string) ([]entities.Erasure, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]entities.Erasure), args.Error(1)
+}
+
+func TestEraseCustomerData(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+	customers := new(mockCustomerRepo)
+
+	customers.On("EraseCustomer", mock.Anything, entities.Erasure{CustomerID: "test", RequestedBy: "admin"}).
+		Return(entities.Erasure{ID: 1, CustomerID: "test", OrderUIDs: []string{"a", "b"}, RequestedBy: "admin"}, nil)
+	cache.On("Delete", "a").Return(true)
+	cache.On("Delete", "b").Return(false)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithCustomerData(customers))
+	erasure, err := s.EraseCustomerData(context.Background(), "test", "admin")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, erasure.OrderUIDs)
+	cache.AssertExpectations(t)
+}
+
+func TestExportCustomerData(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+	customers := new(mockCustomerRepo)
+
+	order := sampleOrder()
+	customers.On("GetCustomerOrderIDs", mock.Anything, order.CustomerID).Return([]string{order.OrderUID, "gone"}, nil)
+	customers.On("GetErasures", mock.Anything, order.CustomerID).Return([]entities.Erasure{}, nil)
+	repo.On("GetOrder", mock.Anything, order.OrderUID).Return(order, nil)
+	repo.On("GetOrder", mock.Anything, "gone").Return(entities.Order{}, domain.ErrOrderNotFound)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithCustomerData(customers))
+	export, err := s.ExportCustomerData(context.Background(), order.CustomerID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, order.CustomerID, export.CustomerID)
+	assert.Equal(t, []entities.Order{order}, export.Orders)
+	cache.AssertNotCalled(t, "Get", mock.Anything)
+}
+
+func TestCustomerData_Disabled(t *testing.T) {
+	s := application.NewOrderService(new(mockCache), new(mockLogger), new(mockRepo), 10)
+
+	_, err := s.EraseCustomerData(context.Background(), "test", "admin")
+	assert.ErrorIs(t, err, application.ErrCustomerDataDisabled)
+}
+
+func TestGetOrder_CoalescesConcurrentMisses(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	order := sampleOrder()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	repo.On("GetOrder", mock.Anything, order.OrderUID).
+		Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		}).
+		Return(order, nil).Once()
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := s.GetOrder(context.Background(), order.OrderUID)
+			if err == nil && got.OrderUID != order.OrderUID {
+				err = errors.New("unexpected order")
+			}
+			errs <- err
+		}()
+	}
+
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	repo.AssertNumberOfCalls(t, "GetOrder", 1)
+}
+
+func TestGetOrder_NegativeCaching(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	order := sampleOrder()
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	repo.On("GetOrder", mock.Anything, order.OrderUID).Return(entities.Order{}, domain.ErrOrderNotFound).Once()
+	repo.On("SaveOrder", mock.Anything, order).Return(nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithNegativeCaching(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		_, err := s.GetOrder(context.Background(), order.OrderUID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	}
+	repo.AssertNumberOfCalls(t, "GetOrder", 1)
+
+	// сохранение заказа снимает отметку "не найден"
+	_, err := s.SaveOrder(context.Background(), order)
+	assert.NoError(t, err)
+
+	repo.On("GetOrder", mock.Anything, order.OrderUID).Return(order, nil).Once()
+	got, err := s.GetOrder(context.Background(), order.OrderUID)
+	assert.NoError(t, err)
+	assert.Equal(t, order, got)
+}
+
+func TestGetOrder_LaggingReplicaNotNegativeCached(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	order := sampleOrder()
+	onReplica := mock.MatchedBy(func(ctx context.Context) bool { return !domainrepo.PrimaryReadRequested(ctx) })
+	onPrimary := mock.MatchedBy(func(ctx context.Context) bool { return domainrepo.PrimaryReadRequested(ctx) })
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	// реплика еще не получила заказ, записанный другим экземпляром
+	repo.On("GetOrder", onReplica, order.OrderUID).Return(entities.Order{}, domain.ErrOrderNotFound)
+	repo.On("GetOrder", onPrimary, order.OrderUID).Return(order, nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithNegativeCaching(time.Minute),
+		application.WithReplicaReads())
+
+	for i := 0; i < 2; i++ {
+		got, err := s.GetOrder(context.Background(), order.OrderUID)
+		assert.NoError(t, err)
+		assert.Equal(t, order, got)
+	}
+	// промах реплики не запомнен: второе чтение снова дошло до БД
+	repo.AssertNumberOfCalls(t, "GetOrder", 4)
+	cache.AssertNumberOfCalls(t, "Set", 2)
+}
+
+func TestGetOrder_ReplicaMissConfirmedOnPrimary(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	cache.On("Get", "missing").Return(entities.Order{}, false)
+	repo.On("GetOrder", mock.Anything, "missing").Return(entities.Order{}, domain.ErrOrderNotFound)
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithNegativeCaching(time.Minute),
+		application.WithReplicaReads())
+
+	for i := 0; i < 3; i++ {
+		_, err := s.GetOrder(context.Background(), "missing")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	}
+	// реплика и primary один раз, дальше - негативный кэш
+	repo.AssertNumberOfCalls(t, "GetOrder", 2)
+}
+
+func TestGetOrder_AbandonedFetchKeepsRequestDeadline(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	order := sampleOrder()
+	deadline := time.Now().Add(time.Minute)
+	type fetchState struct {
+		err         error
+		deadline    time.Time
+		hasDeadline bool
+	}
+	fetched := make(chan fetchState, 1)
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	repo.On("GetOrder", mock.Anything, order.OrderUID).
+		Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			d, ok := ctx.Deadline()
+			fetched <- fetchState{err: ctx.Err(), deadline: d, hasDeadline: ok}
+		}).
+		Return(order, nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	cancel()
+	_, err := s.GetOrder(ctx, order.OrderUID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// отмена клиента не обрывает выборку, но дедлайн запроса у нее остается
+	state := <-fetched
+	assert.NoError(t, state.err)
+	assert.True(t, state.hasDeadline)
+	assert.True(t, state.deadline.Equal(deadline))
+}
+
+type mockBus struct{ mock.Mock }
+
+func (m *mockBus) Publish(ctx context.Context, event entities.CacheEvent) error {
+	return m.Called(ctx, event).Error(0)
+}
+func (m *mockBus) Start(handler func(entities.CacheEvent)) error { return m.Called().Error(0) }
+func (m *mockBus) Shutdown(ctx context.Context) error            { return m.Called(ctx).Error(0) }
+
+func TestCacheInvalidation_PublishOnChange(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+	bus := new(mockBus)
+
+	order := sampleOrder()
+	cache.On("Get", order.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", order.OrderUID, order).Return()
+	cache.On("Delete", order.OrderUID).Return(true)
+	repo.On("SaveOrder", mock.Anything, order).Return(nil)
+	repo.On("DeleteOrder", mock.Anything, order.OrderUID).Return(nil)
+	bus.On("Publish", mock.Anything, entities.CacheEvent{Type: entities.CacheEventSet, OrderUID: order.OrderUID, Origin: "node-a"}).Return(nil).Once()
+	bus.On("Publish", mock.Anything, entities.CacheEvent{Type: entities.CacheEventDelete, OrderUID: order.OrderUID, Origin: "node-a"}).Return(nil).Once()
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithCacheInvalidation(bus, "node-a"))
+
+	_, err := s.SaveOrder(context.Background(), order)
+	assert.NoError(t, err)
+	assert.NoError(t, s.DeleteOrder(context.Background(), order.OrderUID))
+	bus.AssertExpectations(t)
+}
+
+func TestCacheInvalidation_HandleEvent(t *testing.T) {
+	cache := new(mockCache)
+	logger := new(mockLogger)
+	cache.On("Delete", "remote").Return(true).Once()
+	cache.On("Clear").Return().Once()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, new(mockRepo), 10, application.WithCacheInvalidation(new(mockBus), "node-a"))
+
+	s.HandleCacheEvent(entities.CacheEvent{Type: entities.CacheEventSet, OrderUID: "own", Origin: "node-a"})
+	s.HandleCacheEvent(entities.CacheEvent{Type: entities.CacheEventSet, OrderUID: "remote", Origin: "node-b"})
+	s.HandleCacheEvent(entities.CacheEvent{Type: entities.CacheEventClear, Origin: "node-b"})
+
+	cache.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Delete", "own")
+}
+
+// mockSharedCache - кэш с общим уровнем, как tiered и redis
+type mockSharedCache struct{ mockCache }
+
+func (m *mockSharedCache) EvictLocal(key string) bool { return m.Called(key).Bool(0) }
+func (m *mockSharedCache) ClearLocal()                { m.Called() }
+
+func TestCacheInvalidation_HandleEventKeepsSharedTier(t *testing.T) {
+	cache := new(mockSharedCache)
+	logger := new(mockLogger)
+	cache.On("EvictLocal", "remote").Return(true).Once()
+	cache.On("ClearLocal").Return().Once()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, new(mockRepo), 10, application.WithCacheInvalidation(new(mockBus), "node-a"))
+
+	s.HandleCacheEvent(entities.CacheEvent{Type: entities.CacheEventSet, OrderUID: "remote", Origin: "node-b"})
+	// переподключение слушателя Postgres приходит событием очистки без источника
+	s.HandleCacheEvent(entities.CacheEvent{Type: entities.CacheEventClear})
+
+	cache.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Delete", mock.Anything)
+	cache.AssertNotCalled(t, "Clear")
+}
+
+func TestCacheAdmin_DoesNotTouchRepository(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+	bus := new(mockBus)
+
+	stats := entities.CacheStats{Policy: "lru", Size: 1, Hits: 3, Misses: 1, HitRatio: 0.75}
+	cache.On("Stats").Return(stats)
+	cache.On("Clear").Return().Once()
+	cache.On("Delete", "cached").Return(true).Once()
+	cache.On("Delete", "missing").Return(false).Once()
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10, application.WithCacheInvalidation(bus, "node-a"))
+
+	assert.Equal(t, stats, s.CacheStats())
+	s.ClearCache()
+	assert.True(t, s.EvictCachedOrder("cached"))
+	assert.False(t, s.EvictCachedOrder("missing"))
+
+	cache.AssertExpectations(t)
+	repo.AssertNotCalled(t, "ClearOrders", mock.Anything)
+	repo.AssertNotCalled(t, "DeleteOrder", mock.Anything, mock.Anything)
+	bus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestGetAllOrders_StrictReadsFromRepo(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	orders := []entities.Order{sampleOrder()}
+	repo.On("GetAllOrders", mock.Anything, 10, 0).Return(orders, nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithReadConsistency(application.ReadConsistencyStrict))
+	got, err := s.GetAllOrders(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, orders, got)
+	cache.AssertNotCalled(t, "GetAll", mock.Anything)
+	cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+}
+
+func TestClearOrders_KeepsCacheWhenRepoFails(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	repo.On("ClearOrders", mock.Anything).Return(errors.New("db down"))
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10)
+	err := s.ClearOrders(context.Background())
+
+	assert.Error(t, err)
+	cache.AssertNotCalled(t, "Clear")
+}
+
+func TestGetOrder_StrictSkipsFillOvertakenByWrite(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	stale := sampleOrder()
+	fresh := sampleOrder()
+	fresh.TrackNumber = "TRACK456"
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	cache.On("Get", stale.OrderUID).Return(entities.Order{}, false)
+	cache.On("Set", fresh.OrderUID, fresh).Return().Once()
+	repo.On("GetOrder", mock.Anything, stale.OrderUID).
+		Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		}).
+		Return(stale, nil).Once()
+	repo.On("SaveOrder", mock.Anything, fresh).Return(nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithReadConsistency(application.ReadConsistencyStrict))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		got, err := s.GetOrder(context.Background(), stale.OrderUID)
+		assert.NoError(t, err)
+		assert.Equal(t, stale.TrackNumber, got.TrackNumber)
+	}()
+
+	<-started
+	_, err := s.SaveOrder(context.Background(), fresh)
+	assert.NoError(t, err)
+	close(release)
+	<-done
+
+	cache.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Set", stale.OrderUID, stale)
+}
+
+func verifyFixture() (*mockCache, *mockRepo, *mockLogger, entities.Order, entities.Order) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	ok := sampleOrder()
+	stale := sampleOrder()
+	stale.OrderUID = "stale"
+	fresh := stale
+	fresh.TrackNumber = "TRACK456"
+	gone := sampleOrder()
+	gone.OrderUID = "gone"
+
+	cache.On("GetAll", mock.Anything).Return([]entities.Order{ok, stale, gone})
+	repo.On("GetOrder", mock.Anything, ok.OrderUID).Return(ok, nil)
+	repo.On("GetOrder", mock.Anything, stale.OrderUID).Return(fresh, nil)
+	repo.On("GetOrder", mock.Anything, gone.OrderUID).Return(entities.Order{}, domain.ErrOrderNotFound)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+
+	return cache, repo, logger, fresh, gone
+}
+
+func TestVerifyCache_RepairsDivergence(t *testing.T) {
+	cache, repo, logger, fresh, gone := verifyFixture()
+	cache.On("Set", fresh.OrderUID, fresh).Return().Once()
+	cache.On("Delete", gone.OrderUID).Return(true).Once()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithReadConsistency(application.ReadConsistencyStrict),
+		application.WithCacheVerification(10, true))
+	report, err := s.VerifyCache(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, 1, report.Stale)
+	assert.Equal(t, 1, report.Missing)
+	assert.Equal(t, 2, report.Repaired)
+	assert.ElementsMatch(t, []string{fresh.OrderUID, gone.OrderUID}, report.Diverged)
+	assert.False(t, report.Consistent())
+	cache.AssertExpectations(t)
+}
+
+func TestVerifyCache_ReportOnly(t *testing.T) {
+	cache, repo, logger, _, _ := verifyFixture()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithCacheVerification(10, false))
+	report, err := s.VerifyCache(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Stale)
+	assert.Equal(t, 1, report.Missing)
+	assert.Zero(t, report.Repaired)
+	cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestVerifyCache_IgnoresItemOrder(t *testing.T) {
+	cache := new(mockCache)
+	repo := new(mockRepo)
+	logger := new(mockLogger)
+
+	cached := sampleOrder()
+	cached.Items = append(cached.Items,
+		entities.Item{ChrtID: 2, TrackNumber: "TRACK123", Price: 50, RID: "RID2", Name: "item2", TotalPrice: 50, NmID: 2, Brand: "brand"},
+		entities.Item{ChrtID: 3, TrackNumber: "TRACK123", Price: 70, RID: "RID3", Name: "item3", TotalPrice: 70, NmID: 3, Brand: "brand"},
+	)
+	// БД отдает те же товары в другом порядке
+	dbOrder := cached
+	dbOrder.Items = []entities.Item{cached.Items[2], cached.Items[0], cached.Items[1]}
+
+	cache.On("GetAll", mock.Anything).Return([]entities.Order{cached})
+	repo.On("GetOrder", mock.Anything, cached.OrderUID).Return(dbOrder, nil)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	s := application.NewOrderService(cache, logger, repo, 10,
+		application.WithCacheVerification(10, true))
+	report, err := s.VerifyCache(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.Zero(t, report.Stale)
+	assert.Zero(t, report.Repaired)
+	assert.True(t, report.Consistent())
+	cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+}
+
+func TestOrderService_MemoryRepository(t *testing.T) {
+	logger := new(mockLogger)
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+
+	ctx := context.Background()
+	repo := repository.NewMemoryOrderRepository(logger)
+	s := application.NewOrderService(cache.NewOrderLRUCache(logger, 10), logger, repo, 10,
+		application.WithReadConsistency(application.ReadConsistencyStrict))
+
+	order := sampleOrder()
+	res, err := s.SaveOrder(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, application.OrderCreated, res)
+
+	order.TrackNumber = "TRACK456"
+	order.Items[0].TrackNumber = "TRACK456"
+	res, err = s.SaveOrder(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, application.OrderUpdated, res)
+
+	s.ClearCache()
+	got, err := s.GetOrder(ctx, order.OrderUID)
+	assert.NoError(t, err)
+	assert.True(t, got.Equal(order))
+
+	all, err := s.GetAllOrders(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	assert.NoError(t, s.DeleteOrder(ctx, order.OrderUID))
+	_, err = s.GetOrder(ctx, order.OrderUID)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.ErrorIs(t, s.DeleteOrder(ctx, order.OrderUID), domain.ErrOrderNotFound)
+}
@@ -13,4 +13,13 @@ type OrderServiceInterface interface {
 	GetAllOrders(ctx context.Context) ([]entities.Order, error)
 	DeleteOrder(ctx context.Context, id string) error
 	ClearOrders(ctx context.Context) error
+	GetFlaggedOrders(ctx context.Context, limit, offset int) ([]entities.OrderFlag, error)
+	ReportingValue(order entities.Order) (entities.Money, bool)
+	ExportCustomerData(ctx context.Context, customerID string) (entities.CustomerExport, error)
+	EraseCustomerData(ctx context.Context, customerID, requestedBy string) (entities.Erasure, error)
+	HandleCacheEvent(event entities.CacheEvent)
+	CacheStats() entities.CacheStats
+	ClearCache()
+	EvictCachedOrder(id string) bool
+	VerifyCache(ctx context.Context) (entities.CacheVerification, error)
 }
@@ -0,0 +1,148 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/read_consistency.go
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+type ReadConsistency string
+
+const (
+	// ReadConsistencyCache - список заказов отдается из кэша, если тот не пуст
+	ReadConsistencyCache ReadConsistency = "cache"
+	// ReadConsistencyStrict - список всегда читается из БД, а чтение по ID, которое
+	// обогнала запись того же заказа, в кэш не попадает
+	ReadConsistencyStrict ReadConsistency = "strict"
+)
+
+func ParseReadConsistency(s string) (ReadConsistency, error) {
+	switch m := ReadConsistency(s); m {
+	case ReadConsistencyCache, ReadConsistencyStrict:
+		return m, nil
+	case "":
+		return ReadConsistencyCache, nil
+	default:
+		return "", fmt.Errorf("unknown read consistency mode %q", s)
+	}
+}
+
+func WithReadConsistency(mode ReadConsistency) Option {
+	return func(s *orderService) {
+		s.readMode = mode
+		if mode == ReadConsistencyStrict {
+			s.fills = newCacheFillGuard()
+		}
+	}
+}
+
+// listFromRepo читает список из БД и не кладет его в кэш,
+// чтобы просмотр списка не вытеснял заказы, которые читают по ID
+func (s *orderService) listFromRepo(ctx context.Context) ([]entities.Order, error) {
+	const op = "OrderService.GetAllOrders"
+
+	orders, err := s.repo.GetAllOrders(ctx, s.getAllLimit, 0)
+	if err != nil {
+		s.logger.Error("failed to retrieve orders from database", "error", err)
+		return nil, NewAppError(ErrCodeOrdersReadFailed, "failed to retrieve orders from database", op, err)
+	}
+
+	s.logger.Info("retrieved orders",
+		"source", "database",
+		"count", len(orders),
+	)
+	return orders, nil
+}
+
+// cacheFillGuard не дает чтению из БД положить в кэш заказ, который изменили или удалили,
+// пока чтение шло. Запись сначала коммитится в БД, затем под мьютексом сдвигает эпоху
+// заказа и меняет кэш; заполнение из чтения проверяет эпоху под тем же мьютексом.
+// nil guard (режим cache) выполняет все изменения кэша без проверок
+type cacheFillGuard struct {
+	mu      sync.Mutex
+	clears  uint64
+	pending map[string]*fillEpoch
+}
+
+type fillEpoch struct {
+	epoch   uint64
+	readers int
+}
+
+type fillTicket struct {
+	id     string
+	epoch  uint64
+	clears uint64
+}
+
+func newCacheFillGuard() *cacheFillGuard {
+	return &cacheFillGuard{pending: make(map[string]*fillEpoch)}
+}
+
+// begin вызывается до чтения из БД; каждый begin должен завершаться fill
+func (g *cacheFillGuard) begin(id string) fillTicket {
+	if g == nil {
+		return fillTicket{id: id}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	e, ok := g.pending[id]
+	if !ok {
+		e = &fillEpoch{}
+		g.pending[id] = e
+	}
+	e.readers++
+	return fillTicket{id: id, epoch: e.epoch, clears: g.clears}
+}
+
+// fill выполняет fn, только если после begin заказ не записывали и кэш не очищали
+func (g *cacheFillGuard) fill(t fillTicket, fn func()) bool {
+	if g == nil {
+		fn()
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	e := g.pending[t.id]
+	fresh := e.epoch == t.epoch && g.clears == t.clears
+	if fresh {
+		fn()
+	}
+	if e.readers--; e.readers == 0 {
+		delete(g.pending, t.id)
+	}
+	return fresh
+}
+
+// write применяет изменение кэша после коммита записи заказа id
+func (g *cacheFillGuard) write(id string, fn func()) {
+	if g == nil {
+		fn()
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if e, ok := g.pending[id]; ok {
+		e.epoch++
+	}
+	fn()
+}
+
+// writeAll применяет изменение, затрагивающее весь кэш
+func (g *cacheFillGuard) writeAll(fn func()) {
+	if g == nil {
+		fn()
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.clears++
+	fn()
+}
@@ -0,0 +1,40 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/application/reporting.go
+package application
+
+import (
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// WithReportingCurrency включает пересчет стоимости заказа в отчетную валюту
+func WithReportingCurrency(converter domainrepo.CurrencyConverter, currency entities.Currency) Option {
+	return func(s *orderService) {
+		s.converter = converter
+		s.reportingCurrency = currency
+	}
+}
+
+// ReportingValue возвращает payment.amount в отчетной валюте;
+// false - пересчет выключен или для валюты заказа нет курса
+func (s *orderService) ReportingValue(order entities.Order) (entities.Money, bool) {
+	if s.converter == nil || s.reportingCurrency.IsZero() {
+		return entities.Money{}, false
+	}
+
+	amount, err := entities.ParseMoney(int64(order.Payment.Amount), order.Payment.Currency)
+	if err != nil {
+		return entities.Money{}, false
+	}
+
+	converted, err := s.converter.Convert(amount, s.reportingCurrency)
+	if err != nil {
+		s.logger.Warn("failed to convert order value to reporting currency",
+			"order_id", order.OrderUID,
+			"currency", order.Payment.Currency,
+			"reporting_currency", s.reportingCurrency.Code(),
+			"error", err,
+		)
+		return entities.Money{}, false
+	}
+	return converted, true
+}
@@ -4,6 +4,8 @@ package bootstrap
 import (
 	"context"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -12,6 +14,7 @@ import (
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+	infrarepo "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/handler"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/router"
 )
@@ -33,15 +36,39 @@ type App struct {
 	Logger        domainrepo.Logger
 	Cache         domainrepo.Cache
 	CacheRestorer *cache.CacheRestorer
+	CacheSnapshot *cache.CacheSnapshot
 	Repo          domainrepo.OrderRepository
 	Service       application.OrderServiceInterface
 	Handler       *handler.OrderHandler
 	KafkaConsumer domainrepo.EventConsumer
 	DB            Shutdownable
+	KeyRotator    *infrarepo.DeliveryKeyRotator
+	CacheBus      domainrepo.CacheInvalidationBus
+	// Replicas - реплики для чтения; nil, если database.replicas не заданы
+	Replicas              *infrarepo.ReplicaRouter
+	ReplicaHealthInterval time.Duration
+	// Archive создает секции заказов наперед и, если ArchiveMaxAge > 0, уносит старые в архив
+	Archive           *infrarepo.PostgresOrderArchive
+	ArchiveMaxAge     time.Duration
+	PartitionInterval time.Duration
+
+	// CacheVerifyInterval - период фоновой сверки кэша с БД, 0 - сверка только по запросу
+	CacheVerifyInterval time.Duration
+	stopBackground      context.CancelFunc
+}
+
+const defaultConfigPath = "/app/config.yml"
+
+// ConfigPath - путь к конфигу из CONFIG_PATH или путь в контейнере по умолчанию
+func ConfigPath() string {
+	if configPath := os.Getenv("CONFIG_PATH"); configPath != "" {
+		return configPath
+	}
+	return defaultConfigPath
 }
 
 func NewApp() (*App, error) {
-	cfg, err := config.LoadConfig("/app/config.yml")
+	cfg, err := config.LoadConfig(ConfigPath())
 	if err != nil {
 		return nil, err
 	}
@@ -50,40 +77,141 @@ func NewApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	c := factory.NewCache(l, cfg.Cache.Capacity)
-	db, err := factory.NewDatabase(cfg, l)
+	cipher, err := factory.NewFieldCipher(cfg, l)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := factory.NewCache(cfg, cipher, l)
+	if err != nil {
+		return nil, err
+	}
+
+	var db *sqlx.DB
+	if factory.UsesDatabase(cfg) {
+		db, err = factory.NewDatabase(cfg, l)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := RunMigrations(context.Background(), db, cfg.Migrations.MigrationsPath, l, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	replicas, err := factory.NewReplicaRouter(cfg, l)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := factory.NewOrderArchive(cfg, db, l)
+	if err != nil {
+		return nil, err
+	}
+
+	storageBreaker := factory.NewStorageBreaker(cfg, l)
+	dlqBreaker := factory.NewDLQBreaker(cfg.Kafka, l)
+
+	rp, err := factory.NewOrderRepository(cfg, db, replicas, archive, storageBreaker, l, cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	flagRepo, err := factory.NewOrderFlagRepository(cfg, db, l)
+	if err != nil {
+		return nil, err
+	}
+
+	customerRepo, err := factory.NewCustomerDataRepository(cfg, db, l)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := RunMigrations(context.Background(), db, cfg.Migrations.MigrationsPath, l, cfg); err != nil {
+	policy, err := factory.NewConsistencyPolicy(cfg)
+	if err != nil {
 		return nil, err
 	}
 
-	rp, err := factory.NewOrderRepository(cfg, db, l)
+	opts := []application.Option{
+		application.WithConsistencyChecks(policy, flagRepo),
+		application.WithCustomerData(customerRepo),
+		application.WithNegativeCaching(cfg.Cache.NegativeTTL),
+	}
+	if replicas != nil {
+		opts = append(opts, application.WithReplicaReads())
+	}
+
+	readOpts, err := factory.NewReadConsistencyOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, readOpts...)
+
+	cacheBus, err := factory.NewCacheInvalidationBus(cfg, db, l)
+	if err != nil {
+		return nil, err
+	}
+	if cacheBus != nil {
+		opts = append(opts, application.WithCacheInvalidation(cacheBus, factory.NewInstanceID(cfg)))
+	}
+
+	reportingOpt, err := factory.NewReportingCurrencyOption(cfg, l)
 	if err != nil {
 		return nil, err
 	}
+	if reportingOpt != nil {
+		opts = append(opts, reportingOpt)
+	}
 
 	cacheRestorer := factory.NewCacheRestorer(cfg, c, rp, l)
 
-	svc := application.NewOrderService(c, l, rp, cfg.Cache.GetAllLimit)
+	versionRepo, err := factory.NewOrderVersionRepository(cfg, db, l)
+	if err != nil {
+		return nil, err
+	}
+	cacheSnapshot := factory.NewCacheSnapshot(cfg, c, rp, versionRepo, cipher, l)
+
+	svc := application.NewOrderService(c, l, rp, cfg.Cache.GetAllLimit, opts...)
 
-	h := handler.NewOrderHandler(svc, l)
-	r := router.New(h)
+	authenticator, err := factory.NewAuthenticator(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	h := handler.NewOrderHandler(svc, cacheRestorer, l, factory.CircuitBreakers(storageBreaker, dlqBreaker)...)
+	middlewares := []func(http.Handler) http.Handler{authenticator.Middleware}
+	if cfg.Server.RequestTimeout > 0 {
+		middlewares = append(middlewares, router.RequestTimeout(cfg.Server.RequestTimeout))
+	}
+	r := router.New(h, middlewares...)
 	srv := factory.NewHTTPServer(cfg.Server.Port, r)
 
-	kc := factory.NewKafkaConsumer(cfg.Kafka, svc, l)
+	kc := factory.NewKafkaConsumer(cfg.Kafka, svc, storageBreaker, dlqBreaker, l)
 
-	return &App{
+	app := &App{
 		Server:        srv,
 		Logger:        l,
 		Cache:         c,
 		CacheRestorer: cacheRestorer,
+		CacheSnapshot: cacheSnapshot,
 		Repo:          rp,
 		Service:       svc,
 		Handler:       h,
 		KafkaConsumer: kc,
-		DB:            &DBWrapper{DB: db},
-	}, nil
+		KeyRotator:    factory.NewDeliveryKeyRotator(cfg, db, cipher, l),
+		CacheBus:      cacheBus,
+
+		Replicas:              replicas,
+		ReplicaHealthInterval: factory.ReplicaHealthInterval(cfg),
+
+		Archive:           archive,
+		ArchiveMaxAge:     factory.ArchiveMaxAge(cfg),
+		PartitionInterval: factory.PartitionInterval(cfg),
+
+		CacheVerifyInterval: cfg.Cache.Consistency.VerifyInterval,
+	}
+	if db != nil {
+		app.DB = &DBWrapper{DB: db}
+	}
+	return app, nil
 }
@@ -2,21 +2,110 @@
 package factory
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 	infracache "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
 )
 
-func NewCache(l domainrepo.Logger, capacity int) domainrepo.Cache {
-	return infracache.NewOrderLRUCache(l, capacity)
+const redisPingTimeout = 5 * time.Second
+
+// NewCache: cache.backend memory (по умолчанию) | redis | tiered;
+// в Redis PII доставки пишутся зашифрованными, если задан cipher
+func NewCache(cfg *config.Config, cipher domainrepo.FieldCipher, l domainrepo.Logger) (domainrepo.Cache, error) {
+	switch cfg.Cache.Backend {
+	case "", "memory":
+		return newLocalCache(cfg, l)
+	case "redis":
+		return newRedisCache(cfg, cipher, l)
+	case "tiered":
+		local, err := newLocalCache(cfg, l)
+		if err != nil {
+			return nil, err
+		}
+		remote, err := newRedisCache(cfg, cipher, l)
+		if err != nil {
+			return nil, err
+		}
+		return infracache.NewTieredOrderCache(local, remote, l), nil
+	default:
+		return nil, fmt.Errorf("cache.backend: unknown backend %q", cfg.Cache.Backend)
+	}
+}
+
+func newLocalCache(cfg *config.Config, l domainrepo.Logger) (domainrepo.Cache, error) {
+	policy, err := infracache.ParsePolicy(cfg.Cache.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("cache.policy: %w", err)
+	}
+
+	opts := []infracache.Option{
+		infracache.WithTTL(cfg.Cache.TTL),
+		infracache.WithSweepInterval(cfg.Cache.SweepInterval),
+		infracache.WithMaxBytes(cfg.Cache.MaxBytes),
+	}
+
+	if cfg.Cache.Shards > 1 {
+		return infracache.NewShardedOrderCache(l, cfg.Cache.Capacity, cfg.Cache.Shards, policy, opts...), nil
+	}
+	return infracache.NewPolicyOrderCache(l, cfg.Cache.Capacity, policy, opts...), nil
+}
+
+// newRedisCache проверяет соединение сразу, чтобы неверный адрес не превратился в тихие промахи
+func newRedisCache(cfg *config.Config, cipher domainrepo.FieldCipher, l domainrepo.Logger) (domainrepo.Cache, error) {
+	rc := cfg.Cache.Redis
+	client := redis.NewClient(&redis.Options{
+		Addr:     rc.Addr,
+		Password: rc.Password,
+		DB:       rc.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisPingTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("cache.redis: failed to connect to %s: %w", rc.Addr, err)
+	}
+
+	return infracache.NewRedisOrderCache(client, l, infracache.RedisOptions{
+		KeyPrefix: rc.KeyPrefix,
+		Capacity:  rc.Capacity,
+		TTL:       rc.TTL,
+		Timeout:   rc.Timeout,
+		Cipher:    cipher,
+	}), nil
 }
 
 func NewCacheRestorer(cfg *config.Config, c domainrepo.Cache, r domainrepo.OrderRepository, l domainrepo.Logger) *infracache.CacheRestorer {
-	return infracache.NewCacheRestorer(
+	return infracache.NewCacheRestorer(c, r, l, infracache.RestorerConfig{
+		Timeout:       cfg.Cache.Restoration.Timeout,
+		BatchSize:     cfg.Cache.Restoration.BatchSize,
+		Capacity:      cfg.Cache.Capacity,
+		MaxRetries:    cfg.Cache.Restoration.MaxRetries,
+		RetryInterval: cfg.Cache.Restoration.RetryInterval,
+	})
+}
+
+// NewCacheSnapshot возвращает nil, если cache.snapshot.path не задан или нет версий заказов
+// (storage memory: после рестарта сверять снимок не с чем)
+func NewCacheSnapshot(cfg *config.Config, c domainrepo.Cache, r domainrepo.OrderRepository, v domainrepo.OrderVersionRepository, cipher domainrepo.FieldCipher, l domainrepo.Logger) *infracache.CacheSnapshot {
+	if cfg.Cache.Snapshot.Path == "" || v == nil {
+		return nil
+	}
+	return infracache.NewCacheSnapshot(
 		c,
 		r,
+		v,
+		cipher,
 		l,
-		cfg.Cache.Restoration.Timeout,
+		cfg.Cache.Snapshot.Path,
+		cfg.Cache.Capacity,
 		cfg.Cache.Restoration.BatchSize,
+		cfg.Cache.Restoration.Timeout,
 	)
 }
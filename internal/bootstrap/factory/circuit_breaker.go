@@ -0,0 +1,50 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap/factory/circuit_breaker.go
+package factory
+
+import (
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+)
+
+const (
+	storageBreakerName = "storage"
+	dlqBreakerName     = "kafka_dlq"
+)
+
+// NewStorageBreaker возвращает nil, если предохранитель выключен или заказы хранятся в памяти;
+// отсутствие заказа и невалидный заказ отказом хранилища не считаются
+func NewStorageBreaker(cfg *config.Config, l domainrepo.Logger) *breaker.Breaker {
+	if !cfg.Database.CircuitBreaker.Enabled || cfg.Storage == storageMemory {
+		return nil
+	}
+	return newBreaker(storageBreakerName, cfg.Database.CircuitBreaker, l, domain.ErrOrderNotFound, domain.ErrInvalidOrder)
+}
+
+// NewDLQBreaker возвращает nil, если предохранитель выключен или Kafka не настроена
+func NewDLQBreaker(cfg config.KafkaConfig, l domainrepo.Logger) *breaker.Breaker {
+	if !cfg.DLQCircuitBreaker.Enabled || len(cfg.Brokers) == 0 {
+		return nil
+	}
+	return newBreaker(dlqBreakerName, cfg.DLQCircuitBreaker, l)
+}
+
+// CircuitBreakers отбрасывает выключенные предохранители
+func CircuitBreakers(breakers ...*breaker.Breaker) []domainrepo.CircuitBreaker {
+	var enabled []domainrepo.CircuitBreaker
+	for _, b := range breakers {
+		if b != nil {
+			enabled = append(enabled, b)
+		}
+	}
+	return enabled
+}
+
+func newBreaker(name string, cfg config.CircuitBreakerConfig, l domainrepo.Logger, ignore ...error) *breaker.Breaker {
+	return breaker.New(name, breaker.Config{
+		FailureThreshold: cfg.FailureThreshold,
+		OpenTimeout:      cfg.OpenTimeout,
+		HalfOpenRequests: cfg.HalfOpenRequests,
+	}, l, ignore...)
+}
@@ -0,0 +1,42 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap/factory/consistency.go
+package factory
+
+import (
+	"fmt"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/application"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+)
+
+func NewConsistencyPolicy(cfg *config.Config) (application.ConsistencyPolicy, error) {
+	known := make(map[entities.ConsistencyRule]bool)
+	for _, rule := range entities.ConsistencyRules() {
+		known[rule] = true
+	}
+
+	policy := make(application.ConsistencyPolicy, len(cfg.Consistency.Rules))
+	for name, value := range cfg.Consistency.Rules {
+		rule := entities.ConsistencyRule(name)
+		if !known[rule] {
+			return nil, fmt.Errorf("unknown consistency rule %q", name)
+		}
+		action, err := application.ParseConsistencyAction(value)
+		if err != nil {
+			return nil, fmt.Errorf("consistency rule %q: %w", name, err)
+		}
+		policy[rule] = action
+	}
+	return policy, nil
+}
+
+func NewReadConsistencyOptions(cfg *config.Config) ([]application.Option, error) {
+	mode, err := application.ParseReadConsistency(cfg.Cache.Consistency.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return []application.Option{
+		application.WithReadConsistency(mode),
+		application.WithCacheVerification(cfg.Cache.Consistency.VerifySample, cfg.Cache.Consistency.Repair),
+	}, nil
+}
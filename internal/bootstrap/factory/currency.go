@@ -0,0 +1,37 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap/factory/currency.go
+package factory
+
+import (
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/application"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/currency"
+)
+
+// NewReportingCurrencyOption возвращает nil, если пересчет не настроен
+func NewReportingCurrencyOption(cfg *config.Config, l domainrepo.Logger) (application.Option, error) {
+	if cfg.Currency.RatesFile == "" {
+		return nil, nil
+	}
+
+	table, err := currency.LoadRateTable(cfg.Currency.RatesFile)
+	if err != nil {
+		l.Error("failed to load currency rates", "path", cfg.Currency.RatesFile, "error", err)
+		return nil, err
+	}
+
+	reporting := table.Base()
+	if cfg.Currency.ReportingCurrency != "" {
+		reporting, err = entities.ParseCurrency(cfg.Currency.ReportingCurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l.Info("reporting currency enabled",
+		"currency", reporting.Code(),
+		"rates_file", cfg.Currency.RatesFile,
+	)
+	return application.WithReportingCurrency(table, reporting), nil
+}
@@ -4,14 +4,40 @@ package factory
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
 	infrarepo "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/migrations"
 )
 
+const (
+	storagePostgres = "postgres"
+	storageSQLite   = "sqlite"
+	storageMemory   = "memory"
+
+	layoutNormalized = "normalized"
+	layoutDocument   = "document"
+
+	defaultSQLiteBusyTimeout     = 5 * time.Second
+	defaultReplicaHealthInterval = 5 * time.Second
+	defaultPartitionInterval     = 24 * time.Hour
+)
+
+// UsesDatabase: со storage sqlite и memory приложение работает без Postgres
+func UsesDatabase(cfg *config.Config) bool {
+	return cfg.Storage == "" || cfg.Storage == storagePostgres
+}
+
+// usesDocuments: заказы лежат в order_documents, а не в нормализованных таблицах
+func usesDocuments(cfg *config.Config) bool {
+	return UsesDatabase(cfg) && cfg.Database.Layout == layoutDocument
+}
+
 func NewDatabase(cfg *config.Config, l domainrepo.Logger) (*sqlx.DB, error) {
 	db, err := sqlx.Connect("postgres", cfg.Database.DSN)
 	if err != nil {
@@ -35,19 +61,220 @@ func NewDatabase(cfg *config.Config, l domainrepo.Logger) (*sqlx.DB, error) {
 	return db, nil
 }
 
-func NewOrderRepository(cfg *config.Config, db *sqlx.DB, l domainrepo.Logger) (domainrepo.OrderRepository, error) {
-	baseRepo, err := infrarepo.NewPostgresOrderRepository(db, l, cfg.Database.StatementTimeout)
+// NewReplicaRouter подключает реплики из database.replicas.dsns; без них возвращает nil.
+// Соединения открываются лениво: недоступная при старте реплика просто не попадает в ротацию
+func NewReplicaRouter(cfg *config.Config, l domainrepo.Logger) (*infrarepo.ReplicaRouter, error) {
+	if !UsesDatabase(cfg) || len(cfg.Database.Replicas.DSNs) == 0 {
+		return nil, nil
+	}
+
+	replicas := make([]*sqlx.DB, 0, len(cfg.Database.Replicas.DSNs))
+	for i, dsn := range cfg.Database.Replicas.DSNs {
+		db, err := sqlx.Open("postgres", dsn)
+		if err != nil {
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			l.Error("failed to open read replica", "replica", i, "error", err)
+			return nil, fmt.Errorf("%w: replica %d: %v", infrarepo.ErrDatabaseConnectionFailed, i, err)
+		}
+		db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+		replicas = append(replicas, db)
+	}
+
+	router := infrarepo.NewReplicaRouter(replicas, l, cfg.Database.Replicas.ReadYourWrites)
+	router.CheckHealth(context.Background())
+	l.Info("read replicas configured",
+		"replicas", len(replicas),
+		"healthy", router.Healthy(),
+		"read_your_writes", cfg.Database.Replicas.ReadYourWrites,
+	)
+	return router, nil
+}
+
+// ReplicaHealthInterval - период проверки реплик, по умолчанию 5s
+func ReplicaHealthInterval(cfg *config.Config) time.Duration {
+	if cfg.Database.Replicas.HealthCheckInterval <= 0 {
+		return defaultReplicaHealthInterval
+	}
+	return cfg.Database.Replicas.HealthCheckInterval
+}
+
+// NewOrderArchive обслуживает помесячные секции заказов; nil без Postgres и в layout document.
+// Секции создаются и при выключенном архиве, поэтому архив есть всегда, а режим важен только с enabled
+func NewOrderArchive(cfg *config.Config, db *sqlx.DB, l domainrepo.Logger) (*infrarepo.PostgresOrderArchive, error) {
+	if db == nil || !UsesDatabase(cfg) || usesDocuments(cfg) {
+		return nil, nil
+	}
+
+	mode := cfg.Database.Archive.Mode
+	if mode == "" || !cfg.Database.Archive.Enabled {
+		mode = infrarepo.ArchiveModeTable
+	}
+	return infrarepo.NewPostgresOrderArchive(db, l, mode, cfg.Database.Archive.Dir)
+}
+
+// ArchiveMaxAge - возраст, после которого секции уходят в архив; 0 - архив выключен
+func ArchiveMaxAge(cfg *config.Config) time.Duration {
+	if !cfg.Database.Archive.Enabled {
+		return 0
+	}
+	return cfg.Database.Archive.MaxAge
+}
+
+// PartitionInterval - период обслуживания секций, по умолчанию раз в сутки
+func PartitionInterval(cfg *config.Config) time.Duration {
+	if cfg.Database.Archive.Interval <= 0 {
+		return defaultPartitionInterval
+	}
+	return cfg.Database.Archive.Interval
+}
+
+func NewOrderRepository(cfg *config.Config, db *sqlx.DB, replicas *infrarepo.ReplicaRouter, archive *infrarepo.PostgresOrderArchive, storageBreaker *breaker.Breaker, l domainrepo.Logger, cipher domainrepo.FieldCipher) (domainrepo.OrderRepository, error) {
+	var baseRepo domainrepo.OrderRepository
+	switch cfg.Storage {
+	case "", storagePostgres:
+		pgRepo, err := newPostgresOrderRepository(cfg, db, replicas, l)
+		if err != nil {
+			return nil, err
+		}
+		baseRepo = pgRepo
+	case storageSQLite:
+		sqliteRepo, err := newSQLiteOrderRepository(cfg, l)
+		if err != nil {
+			return nil, err
+		}
+		baseRepo = sqliteRepo
+	case storageMemory:
+		l.Warn("orders are stored in memory and will be lost on restart")
+		baseRepo = infrarepo.NewMemoryOrderRepository(l)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Storage)
+	}
+
+	// архив хранит доставку зашифрованной, как и рабочие таблицы, поэтому поиск в нем - под шифрованием
+	if archive != nil && cfg.Database.Archive.Enabled {
+		baseRepo = infrarepo.NewArchiveLookupOrderRepository(baseRepo, archive, l)
+	}
+
+	// предохранитель внутри повторов: разомкнувшись, он обрывает и текущую серию попыток
+	if storageBreaker != nil {
+		baseRepo = infrarepo.NewCircuitBreakerOrderRepository(baseRepo, storageBreaker)
+	}
+
+	if cipher != nil {
+		baseRepo = infrarepo.NewEncryptingOrderRepository(baseRepo, cipher, l)
+	}
+
+	// повторять нечего: хранилище в памяти не дает временных ошибок
+	if cfg.Storage == storageMemory {
+		return baseRepo, nil
+	}
+
+	retry := cfg.Database.Retry
+	return infrarepo.NewRetryingOrderRepository(baseRepo, l, RetryPolicy(retry.RetryConfig),
+		infrarepo.WithReadRetry(RetryPolicy(overrideRetry(retry.RetryConfig, retry.Reads))),
+		infrarepo.WithWriteRetry(RetryPolicy(overrideRetry(retry.RetryConfig, retry.Writes))),
+	), nil
+}
+
+// RetryPolicy переводит политику повторов из конфига в политику декоратора
+func RetryPolicy(c config.RetryConfig) *infrarepo.RetryConfig {
+	return &infrarepo.RetryConfig{
+		MaxElapsedTime:      c.MaxElapsedTime,
+		InitialInterval:     c.InitialInterval,
+		RandomizationFactor: c.RandomizationFactor,
+		Multiplier:          c.Multiplier,
+		MaxInterval:         c.MaxInterval,
+	}
+}
+
+// overrideRetry накладывает ненулевые поля override на base
+func overrideRetry(base, override config.RetryConfig) config.RetryConfig {
+	if override.MaxElapsedTime > 0 {
+		base.MaxElapsedTime = override.MaxElapsedTime
+	}
+	if override.InitialInterval > 0 {
+		base.InitialInterval = override.InitialInterval
+	}
+	if override.RandomizationFactor > 0 {
+		base.RandomizationFactor = override.RandomizationFactor
+	}
+	if override.Multiplier > 0 {
+		base.Multiplier = override.Multiplier
+	}
+	if override.MaxInterval > 0 {
+		base.MaxInterval = override.MaxInterval
+	}
+	return base
+}
+
+func newPostgresOrderRepository(cfg *config.Config, db *sqlx.DB, replicas *infrarepo.ReplicaRouter, l domainrepo.Logger) (domainrepo.OrderRepository, error) {
+	switch cfg.Database.Layout {
+	case "", layoutNormalized:
+		return infrarepo.NewPostgresOrderRepository(db, l, cfg.Database.StatementTimeout, infrarepo.WithReplicas(replicas))
+	case layoutDocument:
+		return infrarepo.NewPostgresDocumentOrderRepository(db, l, cfg.Database.StatementTimeout, infrarepo.WithReplicas(replicas))
+	default:
+		return nil, fmt.Errorf("database.layout: unknown layout %q", cfg.Database.Layout)
+	}
+}
+
+// newSQLiteOrderRepository открывает файл БД и применяет встроенные миграции SQLite;
+// соединение закрывает Shutdown репозитория
+func newSQLiteOrderRepository(cfg *config.Config, l domainrepo.Logger) (*infrarepo.SQLiteOrderRepository, error) {
+	if cfg.SQLite.Path == "" {
+		return nil, fmt.Errorf("sqlite.path is required for storage sqlite")
+	}
+	busyTimeout := cfg.SQLite.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = defaultSQLiteBusyTimeout
+	}
+
+	db, err := infrarepo.OpenSQLite(cfg.SQLite.Path, busyTimeout)
 	if err != nil {
+		l.Error("failed to open sqlite database", "path", cfg.SQLite.Path, "error", err)
 		return nil, err
 	}
 
-	retryConfig := &infrarepo.RetryConfig{
-		MaxElapsedTime:      cfg.Kafka.Retry.MaxElapsedTime,
-		InitialInterval:     cfg.Kafka.Retry.InitialInterval,
-		RandomizationFactor: cfg.Kafka.Retry.RandomizationFactor,
-		Multiplier:          cfg.Kafka.Retry.Multiplier,
-		MaxInterval:         cfg.Kafka.Retry.MaxInterval,
+	if err := migrations.RunSQLiteMigrations(context.Background(), db.DB, l); err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	return infrarepo.NewRetryingOrderRepository(baseRepo, l, retryConfig), nil
+	l.Info("sqlite storage opened", "path", cfg.SQLite.Path)
+	return infrarepo.NewSQLiteOrderRepository(db, l)
+}
+
+// NewOrderFlagRepository, NewCustomerDataRepository и NewOrderVersionRepository
+// возвращают nil без БД; зависящие от них функции при этом выключаются.
+// Флаги хранятся в order_flags при любом layout
+
+func NewOrderFlagRepository(cfg *config.Config, db *sqlx.DB, l domainrepo.Logger) (domainrepo.OrderFlagRepository, error) {
+	if db == nil {
+		return nil, nil
+	}
+	return infrarepo.NewPostgresOrderFlagRepository(db, l)
+}
+
+func NewCustomerDataRepository(cfg *config.Config, db *sqlx.DB, l domainrepo.Logger) (domainrepo.CustomerDataRepository, error) {
+	if db == nil {
+		return nil, nil
+	}
+	if usesDocuments(cfg) {
+		return infrarepo.NewPostgresDocumentCustomerDataRepository(db, l)
+	}
+	return infrarepo.NewPostgresCustomerDataRepository(db, l)
+}
+
+func NewOrderVersionRepository(cfg *config.Config, db *sqlx.DB, l domainrepo.Logger) (domainrepo.OrderVersionRepository, error) {
+	if db == nil {
+		return nil, nil
+	}
+	if usesDocuments(cfg) {
+		return infrarepo.NewPostgresDocumentVersionRepository(db, l)
+	}
+	return infrarepo.NewPostgresOrderVersionRepository(db, l)
 }
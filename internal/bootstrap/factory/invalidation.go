@@ -0,0 +1,52 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap/factory/invalidation.go
+package factory
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+	infrarepo "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/kafka"
+)
+
+const defaultInvalidationChannel = "order_cache_invalidation"
+
+// NewCacheInvalidationBus возвращает nil, если рассылка инвалидаций выключена
+func NewCacheInvalidationBus(cfg *config.Config, db *sqlx.DB, l domainrepo.Logger) (domainrepo.CacheInvalidationBus, error) {
+	inv := cfg.Cache.Invalidation
+	channel := inv.Channel
+	if channel == "" {
+		channel = defaultInvalidationChannel
+	}
+
+	switch inv.Backend {
+	case "", "none":
+		return nil, nil
+	case "postgres":
+		if db == nil {
+			return nil, fmt.Errorf("cache.invalidation.backend: postgres requires storage postgres")
+		}
+		return infrarepo.NewPostgresCacheBus(db, cfg.Database.DSN, channel, l)
+	case "kafka":
+		return kafka.NewCacheBus(cfg.Kafka.Brokers, channel, l), nil
+	default:
+		return nil, fmt.Errorf("cache.invalidation.backend: unknown backend %q", inv.Backend)
+	}
+}
+
+// NewInstanceID: имя хоста помогает найти экземпляр в логах, суффикс гарантирует уникальность
+func NewInstanceID(cfg *config.Config) string {
+	if cfg.Cache.Invalidation.InstanceID != "" {
+		return cfg.Cache.Invalidation.InstanceID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "orderservice"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
@@ -2,13 +2,28 @@
 package factory
 
 import (
+	"context"
+
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/application"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/kafka"
 )
 
-func NewKafkaConsumer(cfg config.KafkaConfig, svc application.OrderServiceInterface, l domainrepo.Logger) domainrepo.EventConsumer {
+// noopConsumer заменяет Kafka, когда брокеры не заданы: заказы принимаются только через HTTP
+type noopConsumer struct{}
+
+func (noopConsumer) Start()                             {}
+func (noopConsumer) Shutdown(ctx context.Context) error { return nil }
+
+// NewKafkaConsumer: storageBreaker и dlqBreaker могут быть nil
+func NewKafkaConsumer(cfg config.KafkaConfig, svc application.OrderServiceInterface, storageBreaker, dlqBreaker *breaker.Breaker, l domainrepo.Logger) domainrepo.EventConsumer {
+	if len(cfg.Brokers) == 0 {
+		l.Warn("kafka brokers are not configured, order consumer disabled")
+		return noopConsumer{}
+	}
+
 	retryConfig := &kafka.RetryConfig{
 		InitialInterval:     cfg.Retry.InitialInterval,
 		Multiplier:          cfg.Retry.Multiplier,
@@ -33,5 +48,7 @@ func NewKafkaConsumer(cfg config.KafkaConfig, svc application.OrderServiceInterf
 		cfg.CommitInterval,
 		cfg.BatchTimeout,
 		cfg.BatchSize,
+		storageBreaker,
+		dlqBreaker,
 	)
 }
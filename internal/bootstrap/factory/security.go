@@ -0,0 +1,66 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap/factory/security.go
+package factory
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/crypto"
+	infrarepo "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/auth"
+)
+
+// NewFieldCipher возвращает nil, если шифрование PII не настроено
+func NewFieldCipher(cfg *config.Config, l domainrepo.Logger) (domainrepo.FieldCipher, error) {
+	if cfg.PII.KeyFile == "" {
+		l.Warn("pii encryption is disabled, delivery data is stored in plaintext")
+		return nil, nil
+	}
+
+	keyring, err := crypto.LoadKeyring(cfg.PII.KeyFile)
+	if err != nil {
+		l.Error("failed to load pii keyring", "path", cfg.PII.KeyFile, "error", err)
+		return nil, err
+	}
+
+	l.Info("pii encryption enabled", "active_key", keyring.ActiveKeyID())
+	return keyring, nil
+}
+
+func NewDeliveryKeyRotator(cfg *config.Config, db *sqlx.DB, cipher domainrepo.FieldCipher, l domainrepo.Logger) *infrarepo.DeliveryKeyRotator {
+	if cipher == nil || db == nil || !cfg.PII.RotateOnStart {
+		return nil
+	}
+	if usesDocuments(cfg) {
+		return infrarepo.NewDocumentDeliveryKeyRotator(db, cipher, l, cfg.PII.RotationBatchSize)
+	}
+	return infrarepo.NewDeliveryKeyRotator(db, cipher, l, cfg.PII.RotationBatchSize)
+}
+
+func NewAuthenticator(cfg *config.Config) (*auth.Authenticator, error) {
+	defaultRole := auth.RoleViewer
+	if cfg.Auth.DefaultRole != "" {
+		role, err := auth.ParseRole(cfg.Auth.DefaultRole)
+		if err != nil {
+			return nil, fmt.Errorf("auth.default_role: %w", err)
+		}
+		defaultRole = role
+	}
+
+	keys := make([]auth.APIKey, 0, len(cfg.Auth.APIKeys))
+	for i, key := range cfg.Auth.APIKeys {
+		if key.Token == "" {
+			return nil, fmt.Errorf("auth.api_keys[%d]: token is empty", i)
+		}
+		role, err := auth.ParseRole(key.Role)
+		if err != nil {
+			return nil, fmt.Errorf("auth.api_keys[%d]: %w", i, err)
+		}
+		keys = append(keys, auth.APIKey{ID: key.ID, Token: key.Token, Role: role})
+	}
+
+	return auth.NewAuthenticator(keys, defaultRole), nil
+}
@@ -3,15 +3,41 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache"
 )
 
 func (a *App) Run() {
 	a.Logger.Info("server starting", "addr", a.Server.Addr)
 	a.KafkaConsumer.Start()
 
+	if a.CacheBus != nil {
+		if err := a.CacheBus.Start(a.Service.HandleCacheEvent); err != nil {
+			a.Logger.Error("failed to start cache invalidation listener", "error", err)
+		}
+	}
+
 	go a.restoreCacheFromDB()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopBackground = cancel
+	if a.CacheVerifyInterval > 0 {
+		go a.verifyCachePeriodically(ctx, a.CacheVerifyInterval)
+	}
+	if a.Replicas != nil {
+		go a.Replicas.Run(ctx, a.ReplicaHealthInterval)
+	}
+	if a.Archive != nil {
+		go a.maintainPartitions(ctx, a.PartitionInterval)
+	}
+
+	if a.KeyRotator != nil {
+		go a.rotateDeliveryKeys()
+	}
+
 	go func() {
 		if err := a.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			a.Logger.Error("server listen failed", "error", err)
@@ -22,6 +48,10 @@ func (a *App) Run() {
 func (a *App) Shutdown(ctx context.Context) {
 	a.Logger.Info("shutdown initiated")
 
+	if a.stopBackground != nil {
+		a.stopBackground()
+	}
+
 	if err := a.KafkaConsumer.Shutdown(ctx); err != nil {
 		a.Logger.Error("failed to shutdown kafka consumer", "error", err)
 	}
@@ -30,14 +60,23 @@ func (a *App) Shutdown(ctx context.Context) {
 		a.Logger.Error("server forced to shutdown", "error", err)
 	}
 
+	// снимок пишется до остановки кэша и БД: для него нужны и записи, и их updated_at
+	if a.CacheSnapshot != nil {
+		if _, err := a.CacheSnapshot.Save(ctx); err != nil {
+			a.Logger.Error("failed to save cache snapshot", "error", err)
+		}
+	}
+
 	resources := []struct {
 		name string
 		res  Shutdownable
 	}{
+		{"cache invalidation bus", a.CacheBus},
 		{"cache", a.Cache},
 		{"logger", a.Logger},
 		{"repository", a.Repo},
 		{"database", a.DB},
+		{"read replicas", a.Replicas},
 	}
 
 	for _, resource := range resources {
@@ -62,9 +101,88 @@ func (a *App) Shutdown(ctx context.Context) {
 func (a *App) restoreCacheFromDB() {
 	ctx := context.Background()
 
+	if a.CacheSnapshot != nil {
+		restored, err := a.CacheSnapshot.Load(ctx)
+		if err == nil {
+			a.Logger.Info("cache restored from snapshot", "orders", restored)
+			return
+		}
+		if errors.Is(err, cache.ErrSnapshotNotFound) {
+			a.Logger.Info("cache snapshot not found, restoring from DB")
+		} else {
+			a.Logger.Warn("failed to load cache snapshot, restoring from DB", "error", err)
+		}
+	}
+
 	if err := a.CacheRestorer.Restore(ctx); err != nil {
 		a.Logger.Error("failed to restore cache from DB", "error", err)
 	} else {
 		a.Logger.Info("cache restored successfully from DB")
 	}
 }
+
+func (a *App) rotateDeliveryKeys() {
+	ctx := context.Background()
+
+	rotated, err := a.KeyRotator.Rotate(ctx)
+	if err != nil {
+		a.Logger.Error("failed to rotate delivery encryption keys", "rotated", rotated, "error", err)
+		return
+	}
+	a.Logger.Info("delivery encryption keys rotated", "rotated", rotated)
+}
+
+// maintainPartitions сразу и затем с периодом interval создает секции наперед и архивирует старые
+func (a *App) maintainPartitions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		a.runPartitionMaintenance(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *App) runPartitionMaintenance(ctx context.Context) {
+	now := time.Now()
+	if err := a.Archive.EnsurePartitions(ctx, now); err != nil && ctx.Err() == nil {
+		a.Logger.Error("failed to create orders partitions", "error", err)
+	}
+
+	if a.ArchiveMaxAge <= 0 {
+		return
+	}
+
+	archived, err := a.Archive.ArchiveBefore(ctx, now.Add(-a.ArchiveMaxAge))
+	if err != nil {
+		if ctx.Err() == nil {
+			a.Logger.Error("failed to archive orders", "archived_months", archived, "error", err)
+		}
+		return
+	}
+	if archived > 0 {
+		a.Logger.Info("old orders archived", "archived_months", archived)
+	}
+}
+
+func (a *App) verifyCachePeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// итоги сверки пишет в лог сам сервис
+			if _, err := a.Service.VerifyCache(ctx); err != nil && ctx.Err() == nil {
+				a.Logger.Warn("cache verification skipped", "error", err)
+			}
+		}
+	}
+}
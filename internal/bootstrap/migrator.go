@@ -7,8 +7,10 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/bootstrap/factory"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config"
+	infrarepo "github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/migrations"
 )
 
@@ -17,8 +19,7 @@ func RunMigrations(ctx context.Context, db *sqlx.DB, migrationsPath string, l do
 		return migrations.RunMigrations(ctx, db.DB, migrationsPath, l)
 	}
 
-	retryPolicy := backoff.NewExponentialBackOff()
-	retryPolicy.MaxElapsedTime = cfg.Kafka.Retry.MaxElapsedTime
+	retryPolicy := infrarepo.NewBackOff(ctx, factory.RetryPolicy(cfg.Migrations.Retry))
 
 	if err := backoff.Retry(operation, retryPolicy); err != nil {
 		l.Error("failed to run migrations after retries", "error", err)
@@ -0,0 +1,18 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/cache_event.go
+package entities
+
+type CacheEventType string
+
+const (
+	CacheEventSet    CacheEventType = "set"
+	CacheEventDelete CacheEventType = "delete"
+	CacheEventClear  CacheEventType = "clear"
+)
+
+// CacheEvent - изменение заказа на одном экземпляре сервиса, после которого
+// остальные экземпляры должны сбросить свою копию. Origin - ID экземпляра-источника
+type CacheEvent struct {
+	Type     CacheEventType `json:"type"`
+	OrderUID string         `json:"order_uid,omitempty"`
+	Origin   string         `json:"origin"`
+}
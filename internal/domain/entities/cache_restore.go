@@ -0,0 +1,45 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/cache_restore.go
+package entities
+
+import "time"
+
+// OrderCursor - позиция в порядке (date_created, order_uid) для keyset-пагинации;
+// нулевой курсор означает начало таблицы
+type OrderCursor struct {
+	DateCreated time.Time `json:"date_created"`
+	OrderUID    string    `json:"order_uid"`
+}
+
+func CursorOf(order Order) OrderCursor {
+	return OrderCursor{DateCreated: order.DateCreated.Time, OrderUID: order.OrderUID}
+}
+
+func (c OrderCursor) IsZero() bool {
+	return c.DateCreated.IsZero() && c.OrderUID == ""
+}
+
+type CacheRestoreState string
+
+const (
+	CacheRestoreIdle      CacheRestoreState = "idle"
+	CacheRestoreRunning   CacheRestoreState = "running"
+	CacheRestoreCompleted CacheRestoreState = "completed"
+	CacheRestoreFailed    CacheRestoreState = "failed"
+)
+
+// CacheRestoreProgress - состояние прогрева кэша; Target - сколько заказов
+// планируется загрузить (не больше емкости кэша). Cursor - последний загруженный заказ,
+// Resumed - прогрев продолжен с курсора упавшего
+type CacheRestoreProgress struct {
+	State      CacheRestoreState `json:"state"`
+	Target     int               `json:"target"`
+	Restored   int               `json:"restored"`
+	Percent    int               `json:"percent"`
+	Batches    int               `json:"batches"`
+	Retries    int               `json:"retries"`
+	Resumed    bool              `json:"resumed"`
+	Cursor     *OrderCursor      `json:"cursor,omitempty"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
@@ -0,0 +1,36 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/cache_stats.go
+package entities
+
+// CacheStats - счетчики кэша с момента старта; Capacity и MaxBytes равные 0 - без ограничения.
+// Evictions считает вытеснения по емкости и max_bytes, но не истечение TTL
+type CacheStats struct {
+	Policy    string  `json:"policy"`
+	Size      int     `json:"size"`
+	Capacity  int     `json:"capacity"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRatio  float64 `json:"hit_ratio"`
+	Bytes     int64   `json:"bytes"`
+	MaxBytes  int64   `json:"max_bytes"`
+}
+
+// Add суммирует счетчики сегментов и пересчитывает HitRatio
+func (s CacheStats) Add(other CacheStats) CacheStats {
+	s.Size += other.Size
+	s.Capacity += other.Capacity
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Evictions += other.Evictions
+	s.Bytes += other.Bytes
+	s.MaxBytes += other.MaxBytes
+	s.HitRatio = HitRatio(s.Hits, s.Misses)
+	return s
+}
+
+func HitRatio(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
@@ -0,0 +1,22 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/cache_verification.go
+package entities
+
+import "time"
+
+// CacheVerification - итог сверки выборки кэша с БД. Stale - заказ в кэше отличается от БД,
+// Missing - заказа в БД уже нет, Failed - сверить не удалось из-за ошибки чтения БД.
+// Diverged содержит первые из расходящихся ID
+type CacheVerification struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Checked    int       `json:"checked"`
+	Stale      int       `json:"stale"`
+	Missing    int       `json:"missing"`
+	Failed     int       `json:"failed"`
+	Repaired   int       `json:"repaired"`
+	Diverged   []string  `json:"diverged,omitempty"`
+}
+
+func (v CacheVerification) Consistent() bool {
+	return v.Stale == 0 && v.Missing == 0
+}
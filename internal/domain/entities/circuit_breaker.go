@@ -0,0 +1,23 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/circuit_breaker.go
+package entities
+
+import "time"
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerStats - состояние предохранителя и счетчики с момента старта;
+// Failures - подряд идущие ошибки в текущем состоянии, Rejected - вызовы, отклоненные без попытки
+type CircuitBreakerStats struct {
+	Name     string       `json:"name"`
+	State    CircuitState `json:"state"`
+	Failures int          `json:"failures"`
+	Opens    uint64       `json:"opens"`
+	Rejected uint64       `json:"rejected"`
+	OpenedAt *time.Time   `json:"opened_at,omitempty"`
+}
@@ -0,0 +1,87 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/consistency.go
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+type ConsistencyRule string
+
+const (
+	RuleGoodsTotal      ConsistencyRule = "goods_total"
+	RuleItemTotalPrice  ConsistencyRule = "item_total_price"
+	RulePaymentAmount   ConsistencyRule = "payment_amount"
+	RuleItemTrackNumber ConsistencyRule = "item_track_number"
+)
+
+func ConsistencyRules() []ConsistencyRule {
+	return []ConsistencyRule{RuleGoodsTotal, RuleItemTotalPrice, RulePaymentAmount, RuleItemTrackNumber}
+}
+
+// Inconsistency - расхождение между связанными полями заказа;
+// в отличие от FieldError каждое поле по отдельности может быть корректным
+type Inconsistency struct {
+	Rule ConsistencyRule `json:"rule"`
+	FieldError
+}
+
+// OrderFlag - сохраненное расхождение, которое требует ручной проверки
+type OrderFlag struct {
+	OrderUID  string          `json:"order_uid" db:"order_uid"`
+	Rule      ConsistencyRule `json:"rule" db:"rule"`
+	Field     string          `json:"field" db:"field"`
+	Message   string          `json:"message" db:"message"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+func (o Order) CheckConsistency() []Inconsistency {
+	var out []Inconsistency
+	add := func(rule ConsistencyRule, field string, err error, format string, args ...interface{}) {
+		out = append(out, Inconsistency{
+			Rule: rule,
+			FieldError: FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("%s: "+format, append([]interface{}{err}, args...)...),
+				Err:     err,
+			},
+		})
+	}
+
+	currency, _ := ParseCurrency(o.Payment.Currency)
+	goodsTotal := NewMoney(0, currency)
+	for i, item := range o.Items {
+		goodsTotal, _ = goodsTotal.Add(NewMoney(int64(item.TotalPrice), currency))
+
+		if !item.totalPriceMatches() {
+			add(RuleItemTotalPrice, fmt.Sprintf("items[%d].total_price", i), ErrItemTotalPriceMismatch,
+				"price %d with sale %d%% does not give %d", item.Price, item.Sale, item.TotalPrice)
+		}
+		if item.TrackNumber != o.TrackNumber {
+			add(RuleItemTrackNumber, fmt.Sprintf("items[%d].track_number", i), ErrItemTrackNumberMismatch,
+				"%q differs from order %q", item.TrackNumber, o.TrackNumber)
+		}
+	}
+
+	if !o.Payment.GoodsTotalMoney().Equal(goodsTotal) {
+		add(RuleGoodsTotal, "payment.goods_total", ErrGoodsTotalMismatch,
+			"expected %s, got %s", goodsTotal, o.Payment.GoodsTotalMoney())
+	}
+
+	expectedAmount, _ := o.Payment.GoodsTotalMoney().Add(o.Payment.DeliveryCostMoney())
+	expectedAmount, _ = expectedAmount.Add(o.Payment.CustomFeeMoney())
+	if !o.Payment.AmountMoney().Equal(expectedAmount) {
+		add(RulePaymentAmount, "payment.amount", ErrPaymentAmountMismatch,
+			"expected %s, got %s", expectedAmount, o.Payment.AmountMoney())
+	}
+
+	return out
+}
+
+// допускаем округление как вниз, так и вверх: продюсеры считают по-разному
+func (i Item) totalPriceMatches() bool {
+	exact := i.Price * (100 - i.Sale)
+	floor := exact / 100
+	ceil := (exact + 99) / 100
+	return i.TotalPrice == floor || i.TotalPrice == ceil
+}
@@ -10,3 +10,36 @@ type Delivery struct {
 	Region  string `json:"region" db:"region"`
 	Email   string `json:"email" db:"email"`
 }
+
+func (d Delivery) Validate() error {
+	var v validator
+	d.validate(&v, "")
+	return v.err()
+}
+
+func (d Delivery) validate(v *validator, prefix string) {
+	v.required(joinPath(prefix, "name"), d.Name, ErrFieldRequired)
+	v.required(joinPath(prefix, "zip"), d.Zip, ErrFieldRequired)
+	v.required(joinPath(prefix, "city"), d.City, ErrFieldRequired)
+	v.required(joinPath(prefix, "address"), d.Address, ErrFieldRequired)
+	v.required(joinPath(prefix, "region"), d.Region, ErrFieldRequired)
+
+	if !validPhone(d.Phone) {
+		v.add(joinPath(prefix, "phone"), ErrInvalidPhoneFormat)
+	}
+	if d.Email != "" && !emailPattern.MatchString(d.Email) {
+		v.add(joinPath(prefix, "email"), ErrInvalidEmailFormat)
+	}
+}
+
+func validPhone(phone string) bool {
+	if len(phone) < 2 || phone[0] != '+' {
+		return false
+	}
+	for _, r := range phone[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
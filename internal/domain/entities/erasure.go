@@ -0,0 +1,34 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/erasure.go
+package entities
+
+import "time"
+
+// ErasedValue заменяет PII-поля доставки после удаления данных клиента;
+// город, регион и индекс остаются для отчетности
+const ErasedValue = "[erased]"
+
+// Erasure - запись журнала удаления данных клиента (data-subject request)
+type Erasure struct {
+	ID          int64     `json:"id" db:"id"`
+	CustomerID  string    `json:"customer_id" db:"customer_id"`
+	OrderUIDs   []string  `json:"order_uids" db:"-"`
+	RequestedBy string    `json:"requested_by" db:"requested_by"`
+	ErasedAt    time.Time `json:"erased_at" db:"erased_at"`
+}
+
+// Erased возвращает доставку с PII-полями, замененными на ErasedValue
+func (d Delivery) Erased() Delivery {
+	d.Name = ErasedValue
+	d.Phone = ErasedValue
+	d.Address = ErasedValue
+	d.Email = ErasedValue
+	return d
+}
+
+// CustomerExport - все данные клиента для выгрузки по запросу
+type CustomerExport struct {
+	CustomerID string    `json:"customer_id"`
+	ExportedAt time.Time `json:"exported_at"`
+	Orders     []Order   `json:"orders"`
+	Erasures   []Erasure `json:"erasures"`
+}
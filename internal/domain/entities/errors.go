@@ -7,7 +7,23 @@ var (
 	ErrOrderUIDRequired     = errors.New("order_uid is required")
 	ErrTrackNumberRequired  = errors.New("track_number is required")
 	ErrItemsEmpty           = errors.New("items cannot be empty")
-	ErrInvalidPaymentAmount = errors.New("payment amount must be > 0")
+	ErrInvalidPaymentAmount = errors.New("payment amount must be >= 0")
 	ErrInvalidEmailFormat   = errors.New("invalid email format")
 	ErrInvalidPhoneFormat   = errors.New("phone must start with +")
+	ErrFieldRequired        = errors.New("field is required")
+	ErrNegativeValue        = errors.New("value must be >= 0")
+	ErrInvalidSale          = errors.New("sale must be between 0 and 100")
+	ErrDuplicateChrtID      = errors.New("chrt_id is already used by another item of the order")
+	ErrInvalidCurrency      = errors.New("invalid ISO 4217 currency code")
+	ErrCurrencyMismatch     = errors.New("currency mismatch")
+	ErrInvalidTimestamp     = errors.New("invalid timestamp")
+
+	ErrGoodsTotalMismatch      = errors.New("goods_total does not match sum of item total prices")
+	ErrItemTotalPriceMismatch  = errors.New("total_price does not match price and sale")
+	ErrPaymentAmountMismatch   = errors.New("amount does not match goods_total + delivery_cost + custom_fee")
+	ErrItemTrackNumberMismatch = errors.New("item track_number does not match order")
+
+	// нарушения, которые видит только хранилище
+	ErrTrackNumberTaken = errors.New("track_number is already used by another order")
+	ErrOrderArchived    = errors.New("order is archived and cannot be saved again")
 )
@@ -28,3 +28,20 @@ func (i *Item) Equal(other Item) bool {
 		     i.Brand == other.Brand &&
 		     i.Status == other.Status
 }
+
+func (i Item) Validate() error {
+	var v validator
+	i.validate(&v, "")
+	return v.err()
+}
+
+// validate повторяет CHECK-ограничения items; текстовые поля в схеме могут быть пустыми.
+// sale сверх схемы ограничен 0..100: это процент скидки, и вне диапазона правило
+// item_total_price получает отрицательную или превышающую price итоговую цену
+func (i Item) validate(v *validator, prefix string) {
+	v.nonNegative(joinPath(prefix, "price"), i.Price)
+	v.nonNegative(joinPath(prefix, "total_price"), i.TotalPrice)
+	if i.Sale < 0 || i.Sale > 100 {
+		v.add(joinPath(prefix, "sale"), ErrInvalidSale)
+	}
+}
@@ -0,0 +1,105 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/money.go
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type Currency struct {
+	code       string
+	minorUnits int
+}
+
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	units, ok := currencyMinorUnits[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	return Currency{code: code, minorUnits: units}, nil
+}
+
+func (c Currency) Code() string          { return c.code }
+func (c Currency) MinorUnits() int       { return c.minorUnits }
+func (c Currency) IsZero() bool          { return c.code == "" }
+func (c Currency) String() string        { return c.code }
+func (c Currency) Equal(o Currency) bool { return c.code == o.code }
+
+// Money хранит сумму в минимальных единицах валюты (копейки, центы),
+// поэтому арифметика не теряет точность
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{amount: amount, currency: currency}
+}
+
+func ParseMoney(amount int64, code string) (Money, error) {
+	c, err := ParseCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, c), nil
+}
+
+func (m Money) Amount() int64      { return m.amount }
+func (m Money) Currency() Currency { return m.currency }
+func (m Money) IsNegative() bool   { return m.amount < 0 }
+
+func (m Money) Add(o Money) (Money, error) {
+	if !m.currency.Equal(o.currency) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	return Money{amount: m.amount + o.amount, currency: m.currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if !m.currency.Equal(o.currency) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	return Money{amount: m.amount - o.amount, currency: m.currency}, nil
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.currency.Equal(o.currency) && m.amount == o.amount
+}
+
+// String форматирует сумму в основных единицах: 1817 USD -> "18.17 USD"
+func (m Money) String() string {
+	amount := m.amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if m.currency.minorUnits == 0 {
+		return fmt.Sprintf("%s%d %s", sign, amount, m.currency)
+	}
+	div := int64(1)
+	for i := 0; i < m.currency.minorUnits; i++ {
+		div *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/div, m.currency.minorUnits, amount%div, m.currency)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+		Formatted string `json:"formatted"`
+	}{m.amount, m.currency.code, m.String()})
+}
+
+func (p Payment) money(amount int) Money {
+	c, _ := ParseCurrency(p.Currency)
+	return NewMoney(int64(amount), c)
+}
+
+func (p Payment) AmountMoney() Money       { return p.money(p.Amount) }
+func (p Payment) DeliveryCostMoney() Money { return p.money(p.DeliveryCost) }
+func (p Payment) GoodsTotalMoney() Money   { return p.money(p.GoodsTotal) }
+func (p Payment) CustomFeeMoney() Money    { return p.money(p.CustomFee) }
@@ -0,0 +1,84 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/money_test.go
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrency(t *testing.T) {
+	c, err := ParseCurrency("usd")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", c.Code())
+	assert.Equal(t, 2, c.MinorUnits())
+
+	c, err = ParseCurrency("JPY")
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.MinorUnits())
+
+	_, err = ParseCurrency("DOLLARS")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+
+	_, err = ParseCurrency("ABC")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+func TestParseCurrency_FullISOTable(t *testing.T) {
+	for code, units := range map[string]int{"BRL": 2, "MXN": 2, "ZAR": 2, "XOF": 0, "IQD": 3, "LYD": 3, "CLF": 4} {
+		c, err := ParseCurrency(code)
+		require.NoError(t, err, code)
+		assert.Equal(t, units, c.MinorUnits(), code)
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a, _ := ParseMoney(1500, "USD")
+	b, _ := ParseMoney(317, "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1817), sum.Amount())
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.True(t, diff.IsNegative())
+
+	eur, _ := ParseMoney(1, "EUR")
+	_, err = a.Add(eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_String(t *testing.T) {
+	cases := []struct {
+		amount int64
+		code   string
+		want   string
+	}{
+		{1817, "USD", "18.17 USD"},
+		{5, "USD", "0.05 USD"},
+		{-105, "RUB", "-1.05 RUB"},
+		{1500, "JPY", "1500 JPY"},
+		{1234, "KWD", "1.234 KWD"},
+	}
+	for _, tc := range cases {
+		m, err := ParseMoney(tc.amount, tc.code)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, m.String())
+	}
+}
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	m, _ := ParseMoney(1817, "USD")
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1817,"currency":"USD","formatted":"18.17 USD"}`, string(data))
+}
+
+func TestPaymentValidate_Currency(t *testing.T) {
+	p := validOrder().Payment
+	p.Currency = "XXX1"
+	assert.ErrorIs(t, p.Validate(), ErrInvalidCurrency)
+}
@@ -1,23 +1,33 @@
 // github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/order.go
 package entities
 
-import "strings"
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
 
 type Order struct {
-	OrderUID        string   `json:"order_uid" db:"order_uid"`
-	TrackNumber     string   `json:"track_number" db:"track_number"`
-	Entry           string   `json:"entry" db:"entry"`
-	Delivery        Delivery `json:"delivery"`
-	Payment         Payment  `json:"payment"`
-	Items           []Item   `json:"items"`
-	Locale          string   `json:"locale" db:"locale"`
-	InternalSig     string   `json:"internal_signature" db:"internal_signature"`
-	CustomerID      string   `json:"customer_id" db:"customer_id"`
-	DeliveryService string   `json:"delivery_service" db:"delivery_service"`
-	ShardKey        string   `json:"shardkey" db:"shardkey"`
-	SMID            int      `json:"sm_id" db:"sm_id"`
-	DateCreated     string   `json:"date_created" db:"date_created"`
-	OOFShard        string   `json:"oof_shard" db:"oof_shard"`
+	OrderUID        string    `json:"order_uid" db:"order_uid"`
+	TrackNumber     string    `json:"track_number" db:"track_number"`
+	Entry           string    `json:"entry" db:"entry"`
+	Delivery        Delivery  `json:"delivery"`
+	Payment         Payment   `json:"payment"`
+	Items           []Item    `json:"items"`
+	Locale          string    `json:"locale" db:"locale"`
+	InternalSig     string    `json:"internal_signature" db:"internal_signature"`
+	CustomerID      string    `json:"customer_id" db:"customer_id"`
+	DeliveryService string    `json:"delivery_service" db:"delivery_service"`
+	ShardKey        string    `json:"shardkey" db:"shardkey"`
+	SMID            int       `json:"sm_id" db:"sm_id"`
+	DateCreated     Timestamp `json:"date_created" db:"date_created"`
+	OOFShard        string    `json:"oof_shard" db:"oof_shard"`
+	// Archived - заказ прочитан из архива, а не из рабочих таблиц; в хранилище не пишется
+	Archived bool `json:"archived,omitempty" db:"-"`
+	// UpdatedAt - updated_at строки, из которой прочитан заказ; нулевой, если версия
+	// неизвестна (заказ пришел из запроса на запись, из архивного файла или из Redis)
+	UpdatedAt time.Time `json:"-" db:"-"`
 }
 
 func (o *Order) Equal(other Order) bool {
@@ -27,6 +37,15 @@ func (o *Order) Equal(other Order) bool {
 		o.itemsEqual(other)
 }
 
+// EqualContent сравнивает заказы без учета порядка товаров: хранилища его не обещают,
+// и копия из БД может перечислять те же товары иначе, чем закэшированная
+func (o *Order) EqualContent(other Order) bool {
+	return o.basicFieldsEqual(other) &&
+		o.deliveryEqual(other) &&
+		o.paymentEqual(other) &&
+		itemsEqual(sortedItems(o.Items), sortedItems(other.Items))
+}
+
 func (o *Order) basicFieldsEqual(other Order) bool {
 	return o.OrderUID == other.OrderUID &&
 		o.TrackNumber == other.TrackNumber &&
@@ -36,7 +55,7 @@ func (o *Order) basicFieldsEqual(other Order) bool {
 		o.CustomerID == other.CustomerID &&
 		o.ShardKey == other.ShardKey &&
 		o.SMID == other.SMID &&
-		o.DateCreated == other.DateCreated &&
+		o.DateCreated.Equal(other.DateCreated) &&
 		o.OOFShard == other.OOFShard
 }
 
@@ -56,7 +75,7 @@ func (o *Order) paymentEqual(other Order) bool {
 		o.Payment.Currency == other.Payment.Currency &&
 		o.Payment.Provider == other.Payment.Provider &&
 		o.Payment.Amount == other.Payment.Amount &&
-		o.Payment.PaymentDT == other.Payment.PaymentDT &&
+		o.Payment.PaymentDT.Equal(other.Payment.PaymentDT) &&
 		o.Payment.Bank == other.Payment.Bank &&
 		o.Payment.DeliveryCost == other.Payment.DeliveryCost &&
 		o.Payment.GoodsTotal == other.Payment.GoodsTotal &&
@@ -64,39 +83,60 @@ func (o *Order) paymentEqual(other Order) bool {
 }
 
 func (o *Order) itemsEqual(other Order) bool {
-	if len(o.Items) != len(other.Items) {
+	return itemsEqual(o.Items, other.Items)
+}
+
+func itemsEqual(a, b []Item) bool {
+	if len(a) != len(b) {
 		return false
 	}
-	for i := range o.Items {
-		if !o.Items[i].Equal(other.Items[i]) {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
 			return false
 		}
 	}
 	return true
 }
 
+// sortedItems возвращает копию товаров по возрастанию chrt_id, уникального в заказе
+func sortedItems(items []Item) []Item {
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b Item) int {
+		return cmp.Compare(a.ChrtID, b.ChrtID)
+	})
+	return sorted
+}
+
 func (o Order) Validate() error {
-	switch {
-	case o.OrderUID == "":
-		return ErrOrderUIDRequired
-	case o.TrackNumber == "":
-		return ErrTrackNumberRequired
-	case len(o.Items) == 0:
-		return ErrItemsEmpty
-	case o.Payment.Amount < 0:
-		return ErrInvalidPaymentAmount
-	case o.Delivery.Email != "" && !strings.Contains(o.Delivery.Email, "@"):
-		return ErrInvalidEmailFormat
-	case o.Delivery.Phone != "":
-		if !strings.HasPrefix(o.Delivery.Phone, "+") {
-			return ErrInvalidPhoneFormat
-		}
-		digits := o.Delivery.Phone[1:]
-		for _, r := range digits {
-			if r < '0' || r > '9' {
-				return ErrInvalidPhoneFormat
-			}
+	var v validator
+
+	v.required("order_uid", o.OrderUID, ErrOrderUIDRequired)
+	v.required("track_number", o.TrackNumber, ErrTrackNumberRequired)
+	v.required("entry", o.Entry, ErrFieldRequired)
+	v.required("locale", o.Locale, ErrFieldRequired)
+	v.required("customer_id", o.CustomerID, ErrFieldRequired)
+	v.required("delivery_service", o.DeliveryService, ErrFieldRequired)
+	if o.DateCreated.IsZero() {
+		v.add("date_created", ErrFieldRequired)
+	}
+	v.nonNegative("sm_id", o.SMID)
+
+	o.Delivery.validate(&v, "delivery")
+	o.Payment.validate(&v, "payment")
+
+	if len(o.Items) == 0 {
+		v.add("items", ErrItemsEmpty)
+	}
+	// (chrt_id, order_uid) - первичный ключ items, повтор chrt_id хранилище не примет
+	seen := make(map[int]struct{}, len(o.Items))
+	for i, item := range o.Items {
+		prefix := fmt.Sprintf("items[%d]", i)
+		item.validate(&v, prefix)
+		if _, dup := seen[item.ChrtID]; dup {
+			v.add(prefix+".chrt_id", ErrDuplicateChrtID)
 		}
+		seen[item.ChrtID] = struct{}{}
 	}
-	return nil
+
+	return v.err()
 }
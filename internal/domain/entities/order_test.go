@@ -0,0 +1,228 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/order_test.go
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validOrder() Order {
+	return Order{
+		OrderUID:        "b563feb7b2b84b6test",
+		TrackNumber:     "WBILMTESTTRACK",
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SMID:            99,
+		DateCreated:     NewTimestamp(time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)),
+		OOFShard:        "1",
+		Delivery: Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1817,
+			PaymentDT:    NewUnixTime(time.Unix(1637907727, 0)),
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+		},
+		Items: []Item{
+			{
+				ChrtID:      9934930,
+				TrackNumber: "WBILMTESTTRACK",
+				Price:       453,
+				RID:         "ab4219087a764ae0btest",
+				Name:        "Mascaras",
+				Sale:        30,
+				Size:        "0",
+				TotalPrice:  317,
+				NmID:        2389212,
+				Brand:       "Vivienne Sabo",
+				Status:      202,
+			},
+		},
+	}
+}
+
+func fields(errs ValidationErrors) []string {
+	out := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		out = append(out, fe.Field)
+	}
+	return out
+}
+
+func TestOrderValidate_Valid(t *testing.T) {
+	assert.NoError(t, validOrder().Validate())
+}
+
+func TestOrderValidate_CollectsAllViolations(t *testing.T) {
+	order := validOrder()
+	order.OrderUID = ""
+	order.Delivery.Email = "a@b.c"
+	order.Payment.CustomFee = -1
+	order.Items = append(order.Items, order.Items[0], order.Items[0])
+	order.Items[1].ChrtID = 9934931
+	order.Items[2].ChrtID = 9934932
+	order.Items[2].Price = -10
+	order.Items[2].Sale = 150
+
+	err := order.Validate()
+	require.Error(t, err)
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	assert.ElementsMatch(t, []string{
+		"order_uid",
+		"delivery.email",
+		"payment.custom_fee",
+		"items[2].sale",
+		"items[2].price",
+	}, fields(verrs))
+
+	assert.ErrorIs(t, err, ErrOrderUIDRequired)
+	assert.ErrorIs(t, err, ErrInvalidEmailFormat)
+	assert.ErrorIs(t, err, ErrNegativeValue)
+}
+
+func TestOrderValidate_DuplicateChrtID(t *testing.T) {
+	order := validOrder()
+	order.Items = append(order.Items, order.Items[0])
+
+	err := order.Validate()
+	require.Error(t, err)
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	assert.Equal(t, []string{"items[1].chrt_id"}, fields(verrs))
+	assert.ErrorIs(t, err, ErrDuplicateChrtID)
+}
+
+func TestOrderEqualContent_IgnoresItemOrder(t *testing.T) {
+	order := validOrder()
+	second := order.Items[0]
+	second.ChrtID++
+	second.Name = "second"
+	order.Items = append(order.Items, second)
+
+	reordered := order
+	reordered.Items = []Item{order.Items[1], order.Items[0]}
+	assert.False(t, order.Equal(reordered))
+	assert.True(t, order.EqualContent(reordered))
+	assert.Equal(t, second, order.Items[1], "EqualContent must not reorder the original items")
+
+	reordered.Items = []Item{order.Items[1], order.Items[1]}
+	assert.False(t, order.EqualContent(reordered))
+}
+
+func TestItemValidate_MatchesSchema(t *testing.T) {
+	// текстовые поля items в схеме NOT NULL, но могут быть пустыми
+	item := validOrder().Items[0]
+	item.TrackNumber, item.RID, item.Name, item.Brand = "", "", "", ""
+	assert.NoError(t, item.Validate())
+}
+
+func TestOrderValidate_EmptyItems(t *testing.T) {
+	order := validOrder()
+	order.Items = nil
+
+	err := order.Validate()
+	assert.ErrorIs(t, err, ErrItemsEmpty)
+}
+
+func TestDeliveryValidate_Phone(t *testing.T) {
+	cases := map[string]bool{
+		"+9720000000": true,
+		"9720000000":  false,
+		"+972-000":    false,
+		"+":           false,
+		"":            false,
+	}
+	for phone, ok := range cases {
+		d := validOrder().Delivery
+		d.Phone = phone
+		err := d.Validate()
+		if ok {
+			assert.NoError(t, err, phone)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidPhoneFormat, phone)
+		}
+	}
+}
+
+func TestDeliveryValidate_Email(t *testing.T) {
+	cases := map[string]bool{
+		"":                true,
+		"test@gmail.com":  true,
+		"t@ab.cd":         true,
+		"test@gmail":      false,
+		"@gmail.com":      false,
+		"test@g.com":      false,
+		"test@gmail.c":    false,
+		"no-at-sign.test": false,
+	}
+	for email, ok := range cases {
+		d := validOrder().Delivery
+		d.Email = email
+		err := d.Validate()
+		if ok {
+			assert.NoError(t, err, email)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidEmailFormat, email)
+		}
+	}
+}
+
+func TestItemValidate_Sale(t *testing.T) {
+	item := validOrder().Items[0]
+	item.Sale = 101
+	assert.ErrorIs(t, item.Validate(), ErrInvalidSale)
+}
+
+func TestCheckConsistency_Valid(t *testing.T) {
+	assert.Empty(t, validOrder().CheckConsistency())
+}
+
+func TestCheckConsistency_Mismatches(t *testing.T) {
+	order := validOrder()
+	order.Items = append(order.Items, order.Items[0])
+	order.Items[1].ChrtID = 9934931
+	order.Items[1].TrackNumber = "OTHER"
+	order.Items[1].TotalPrice = 300
+
+	rules := map[ConsistencyRule]string{}
+	for _, inc := range order.CheckConsistency() {
+		rules[inc.Rule] = inc.Field
+	}
+
+	assert.Equal(t, map[ConsistencyRule]string{
+		RuleItemTotalPrice:  "items[1].total_price",
+		RuleItemTrackNumber: "items[1].track_number",
+		RuleGoodsTotal:      "payment.goods_total",
+	}, rules)
+}
+
+func TestCheckConsistency_PaymentAmount(t *testing.T) {
+	order := validOrder()
+	order.Payment.CustomFee = 10
+
+	inc := order.CheckConsistency()
+	require.Len(t, inc, 1)
+	assert.Equal(t, RulePaymentAmount, inc[0].Rule)
+	assert.ErrorIs(t, inc[0], ErrPaymentAmountMismatch)
+}
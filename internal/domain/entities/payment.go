@@ -2,14 +2,35 @@
 package entities
 
 type Payment struct {
-	Transaction  string `json:"transaction" db:"transaction"`
-	RequestID    string `json:"request_id" db:"request_id"`
-	Currency     string `json:"currency" db:"currency"`
-	Provider     string `json:"provider" db:"provider"`
-	Amount       int    `json:"amount" db:"amount"`
-	PaymentDT    int64  `json:"payment_dt" db:"payment_dt"`
-	Bank         string `json:"bank" db:"bank"`
-	DeliveryCost int    `json:"delivery_cost" db:"delivery_cost"`
-	GoodsTotal   int    `json:"goods_total" db:"goods_total"`
-	CustomFee    int    `json:"custom_fee" db:"custom_fee"`
+	Transaction  string   `json:"transaction" db:"transaction"`
+	RequestID    string   `json:"request_id" db:"request_id"`
+	Currency     string   `json:"currency" db:"currency"`
+	Provider     string   `json:"provider" db:"provider"`
+	Amount       int      `json:"amount" db:"amount"`
+	PaymentDT    UnixTime `json:"payment_dt" db:"payment_dt"`
+	Bank         string   `json:"bank" db:"bank"`
+	DeliveryCost int      `json:"delivery_cost" db:"delivery_cost"`
+	GoodsTotal   int      `json:"goods_total" db:"goods_total"`
+	CustomFee    int      `json:"custom_fee" db:"custom_fee"`
+}
+
+func (p Payment) Validate() error {
+	var v validator
+	p.validate(&v, "")
+	return v.err()
+}
+
+func (p Payment) validate(v *validator, prefix string) {
+	v.required(joinPath(prefix, "transaction"), p.Transaction, ErrFieldRequired)
+	if _, err := ParseCurrency(p.Currency); err != nil {
+		v.add(joinPath(prefix, "currency"), ErrInvalidCurrency)
+	}
+	v.required(joinPath(prefix, "provider"), p.Provider, ErrFieldRequired)
+
+	if p.Amount < 0 {
+		v.add(joinPath(prefix, "amount"), ErrInvalidPaymentAmount)
+	}
+	v.nonNegative(joinPath(prefix, "delivery_cost"), p.DeliveryCost)
+	v.nonNegative(joinPath(prefix, "goods_total"), p.GoodsTotal)
+	v.nonNegative(joinPath(prefix, "custom_fee"), p.CustomFee)
 }
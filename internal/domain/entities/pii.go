@@ -0,0 +1,104 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/pii.go
+package entities
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PII-поля доставки. Список общий для шифрования в БД,
+// маскирования в ответах API и редактирования логов
+const (
+	PIIName    = "name"
+	PIIPhone   = "phone"
+	PIIAddress = "address"
+	PIIEmail   = "email"
+)
+
+func PIIFields() []string {
+	return []string{PIIName, PIIPhone, PIIAddress, PIIEmail}
+}
+
+// TransformPII применяет fn к каждому непустому PII-полю и возвращает копию
+func (d Delivery) TransformPII(fn func(field, value string) (string, error)) (Delivery, error) {
+	fields := []struct {
+		name string
+		ptr  *string
+	}{
+		{PIIName, &d.Name},
+		{PIIPhone, &d.Phone},
+		{PIIAddress, &d.Address},
+		{PIIEmail, &d.Email},
+	}
+
+	for _, f := range fields {
+		if *f.ptr == "" {
+			continue
+		}
+		v, err := fn(f.name, *f.ptr)
+		if err != nil {
+			return Delivery{}, err
+		}
+		*f.ptr = v
+	}
+	return d, nil
+}
+
+func (d Delivery) Masked() Delivery {
+	masked, _ := d.TransformPII(func(field, value string) (string, error) {
+		return MaskPII(field, value), nil
+	})
+	return masked
+}
+
+func MaskPII(field, value string) string {
+	switch field {
+	case PIIPhone:
+		return MaskPhone(value)
+	case PIIEmail:
+		return MaskEmail(value)
+	default:
+		return MaskWords(value)
+	}
+}
+
+// MaskPhone: +79991231234 -> +7******1234
+func MaskPhone(phone string) string {
+	if !strings.HasPrefix(phone, "+") || len(phone) < 7 {
+		return maskAll(phone)
+	}
+	return phone[:2] + strings.Repeat("*", len(phone)-6) + phone[len(phone)-4:]
+}
+
+// MaskEmail: john.doe@gmail.com -> j*******@gmail.com
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return maskAll(email)
+	}
+	return keepFirst(email[:at]) + email[at:]
+}
+
+// MaskWords: Test Testov -> T*** T*****; цифры (номер дома) сохраняются
+func MaskWords(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		if strings.IndexFunc(w, func(r rune) bool { return r < '0' || r > '9' }) == -1 {
+			continue
+		}
+		words[i] = keepFirst(w)
+	}
+	return strings.Join(words, " ")
+}
+
+func keepFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return maskAll(s)
+	}
+	return string(r) + strings.Repeat("*", utf8.RuneCountInString(s[size:]))
+}
+
+func maskAll(s string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(s))
+}
@@ -0,0 +1,38 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/pii_test.go
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMasking(t *testing.T) {
+	assert.Equal(t, "+7******1234", MaskPhone("+79991231234"))
+	assert.Equal(t, "****", MaskPhone("1234"))
+	assert.Equal(t, "t***@gmail.com", MaskEmail("test@gmail.com"))
+	assert.Equal(t, "T*** T*****", MaskWords("Test Testov"))
+	assert.Equal(t, "P****** 15", MaskWords("Ploshad 15"))
+	assert.Equal(t, "И*** И*****", MaskWords("Иван Иванов"))
+}
+
+func TestDelivery_Masked(t *testing.T) {
+	d := Delivery{
+		Name:    "Test Testov",
+		Phone:   "+9720000000",
+		Zip:     "2639809",
+		City:    "Kiryat Mozkin",
+		Address: "Ploshad Mira 15",
+		Region:  "Kraiot",
+		Email:   "test@gmail.com",
+	}
+
+	m := d.Masked()
+	assert.Equal(t, "T*** T*****", m.Name)
+	assert.Equal(t, "+9*****0000", m.Phone)
+	assert.Equal(t, "P****** M*** 15", m.Address)
+	assert.Equal(t, "t***@gmail.com", m.Email)
+	assert.Equal(t, d.City, m.City)
+	assert.Equal(t, d.Zip, m.Zip)
+	assert.Equal(t, "Test Testov", d.Name, "original must not change")
+}
@@ -0,0 +1,182 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/timestamp.go
+package entities
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// форматы, которые реально присылают продюсеры; строки без зоны считаем UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ParseTime разбирает RFC3339 (с зоной и без) или unix-секунды и приводит к UTC
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return fromUnix(sec), nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return normalizeTime(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+}
+
+// Postgres TIMESTAMP хранит микросекунды, поэтому обрезаем до них,
+// иначе заказ после чтения из БД перестает быть Equal исходному
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+func parseJSONTime(data []byte) (time.Time, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return time.Time{}, nil
+	}
+
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return time.Time{}, err
+		}
+		return ParseTime(s)
+	}
+
+	sec, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTimestamp, data)
+	}
+	return fromUnix(sec), nil
+}
+
+func scanTime(src interface{}) (time.Time, error) {
+	switch v := src.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return normalizeTime(v), nil
+	case int64:
+		return fromUnix(v), nil
+	case string:
+		return ParseTime(v)
+	case []byte:
+		return ParseTime(string(v))
+	default:
+		return time.Time{}, fmt.Errorf("%w: unsupported type %T", ErrInvalidTimestamp, src)
+	}
+}
+
+// Timestamp - момент времени в UTC, в JSON отдается строкой RFC3339
+type Timestamp struct {
+	time.Time
+}
+
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: normalizeTime(t)}
+}
+
+func (t Timestamp) Equal(other Timestamp) bool {
+	return t.Time.Equal(other.Time)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	parsed, err := parseJSONTime(data)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t *Timestamp) Scan(src interface{}) error {
+	parsed, err := scanTime(src)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t Timestamp) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.UTC(), nil
+}
+
+// UnixTime - момент времени в UTC, в JSON и БД хранится unix-секундами
+type UnixTime struct {
+	time.Time
+}
+
+func NewUnixTime(t time.Time) UnixTime {
+	return UnixTime{Time: t.UTC().Truncate(time.Second)}
+}
+
+func (t UnixTime) Equal(other UnixTime) bool {
+	return t.Time.Equal(other.Time)
+}
+
+func (t UnixTime) unix() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.unix(), 10)), nil
+}
+
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	parsed, err := parseJSONTime(data)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed.Truncate(time.Second)
+	return nil
+}
+
+func (t *UnixTime) Scan(src interface{}) error {
+	parsed, err := scanTime(src)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed.Truncate(time.Second)
+	return nil
+}
+
+func (t UnixTime) Value() (driver.Value, error) {
+	return t.unix(), nil
+}
@@ -0,0 +1,82 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/timestamp_test.go
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime_Formats(t *testing.T) {
+	want := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+
+	for _, in := range []string{
+		"2021-11-26T06:22:19Z",
+		"2021-11-26T09:22:19+03:00",
+		"2021-11-26T06:22:19",
+		"2021-11-26 06:22:19",
+		"1637907739",
+	} {
+		got, err := ParseTime(in)
+		require.NoError(t, err, in)
+		assert.True(t, want.Equal(got), "%s: got %v", in, got)
+		assert.Equal(t, time.UTC, got.Location(), in)
+	}
+
+	_, err := ParseTime("26.11.2021")
+	assert.ErrorIs(t, err, ErrInvalidTimestamp)
+}
+
+func TestTimestamp_JSONRoundTrip(t *testing.T) {
+	var ts Timestamp
+	require.NoError(t, json.Unmarshal([]byte(`"2021-11-26T09:22:19+03:00"`), &ts))
+
+	data, err := json.Marshal(ts)
+	require.NoError(t, err)
+	assert.Equal(t, `"2021-11-26T06:22:19Z"`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`1637907739`), &ts))
+	assert.Equal(t, int64(1637907739), ts.Unix())
+}
+
+func TestUnixTime_JSONRoundTrip(t *testing.T) {
+	var ut UnixTime
+	require.NoError(t, json.Unmarshal([]byte(`1637907727`), &ut))
+
+	data, err := json.Marshal(ut)
+	require.NoError(t, err)
+	assert.Equal(t, `1637907727`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`"2021-11-26T06:22:07Z"`), &ut))
+	assert.Equal(t, int64(1637907727), ut.Unix())
+
+	data, err = json.Marshal(UnixTime{})
+	require.NoError(t, err)
+	assert.Equal(t, `0`, string(data))
+}
+
+func TestTimestamp_ScanNormalizesToUTC(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	src := time.Date(2021, 11, 26, 9, 22, 19, 123456789, moscow)
+
+	var ts Timestamp
+	require.NoError(t, ts.Scan(src))
+	assert.Equal(t, time.UTC, ts.Location())
+	assert.Equal(t, 123456000, ts.Nanosecond())
+
+	var ut UnixTime
+	require.NoError(t, ut.Scan(int64(1637907727)))
+	v, err := ut.Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1637907727), v)
+}
+
+func TestOrderEqual_AcrossTimeZones(t *testing.T) {
+	a := validOrder()
+	b := validOrder()
+	require.NoError(t, json.Unmarshal([]byte(`"2021-11-26T09:22:19+03:00"`), &b.DateCreated))
+	assert.True(t, a.Equal(b))
+}
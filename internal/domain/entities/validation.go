@@ -0,0 +1,78 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities/validation.go
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// повторяет CHECK (email LIKE '%_@__%.__%') из миграции 0001
+var emailPattern = regexp.MustCompile(`^.+@.{2,}\..{2,}$`)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors собирает все нарушения сразу, а не только первое
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, fe := range v {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(v))
+	for _, fe := range v {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field string, err error) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: err.Error(), Err: err})
+}
+
+func (v *validator) required(field, value string, err error) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, err)
+	}
+}
+
+func (v *validator) nonNegative(field string, value int) {
+	if value < 0 {
+		v.add(field, ErrNegativeValue)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func joinPath(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
@@ -6,4 +6,6 @@ import "errors"
 var (
 	ErrInvalidOrder  = errors.New("invalid order")
 	ErrOrderNotFound = errors.New("order not found")
+	// ErrCircuitOpen - предохранитель разомкнут, вызов отклонен без обращения к хранилищу
+	ErrCircuitOpen = errors.New("circuit breaker is open")
 )
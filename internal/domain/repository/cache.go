@@ -13,5 +13,13 @@ type Cache interface {
 	Delete(key string) bool
 	Clear()
 	GetAll(limit int) []entities.Order
+	Stats() entities.CacheStats
 	Shutdown(ctx context.Context) error
 }
+
+// LocalCache реализуют кэши с общим уровнем (Redis): события других экземпляров сбрасывают
+// только копии этого экземпляра, общий уровень уже обновил источник события
+type LocalCache interface {
+	EvictLocal(key string) bool
+	ClearLocal()
+}
@@ -0,0 +1,17 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/cache_invalidation_bus.go
+package repository
+
+import (
+	"context"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// CacheInvalidationBus рассылает события изменения кэша всем экземплярам сервиса
+type CacheInvalidationBus interface {
+	Publish(ctx context.Context, event entities.CacheEvent) error
+	// Start запускает доставку событий в handler, включая собственные:
+	// фильтрация по Origin - забота получателя
+	Start(handler func(entities.CacheEvent)) error
+	Shutdown(ctx context.Context) error
+}
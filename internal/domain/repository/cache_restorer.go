@@ -0,0 +1,15 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/cache_restorer.go
+package repository
+
+import (
+	"context"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+type CacheRestorer interface {
+	Restore(ctx context.Context) error
+	// TryStart запускает Restore в фоне; false - прогрев уже идет
+	TryStart(ctx context.Context) bool
+	Progress() entities.CacheRestoreProgress
+}
@@ -0,0 +1,15 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/circuit_breaker.go
+package repository
+
+import (
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// CircuitBreaker - предохранитель перед внешней зависимостью (БД, DLQ)
+type CircuitBreaker interface {
+	Stats() entities.CircuitBreakerStats
+	// RetryAfter - сколько осталось до пробных вызовов; 0, если предохранитель не разомкнут
+	RetryAfter() time.Duration
+}
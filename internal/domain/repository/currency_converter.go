@@ -0,0 +1,8 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/currency_converter.go
+package repository
+
+import "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+
+type CurrencyConverter interface {
+	Convert(m entities.Money, to entities.Currency) (entities.Money, error)
+}
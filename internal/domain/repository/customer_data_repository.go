@@ -0,0 +1,16 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/customer_data_repository.go
+package repository
+
+import (
+	"context"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+type CustomerDataRepository interface {
+	GetCustomerOrderIDs(ctx context.Context, customerID string) ([]string, error)
+	// EraseCustomer в одной транзакции анонимизирует доставку всех заказов клиента
+	// и пишет запись в журнал удалений; возвращает запись с заполненными OrderUIDs
+	EraseCustomer(ctx context.Context, erasure entities.Erasure) (entities.Erasure, error)
+	GetErasures(ctx context.Context, customerID string) ([]entities.Erasure, error)
+}
@@ -0,0 +1,10 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/field_cipher.go
+package repository
+
+type FieldCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+	// NeedsRotation - значение не зашифровано или зашифровано неактивным ключом
+	NeedsRotation(value string) bool
+	Rotate(value string) (string, error)
+}
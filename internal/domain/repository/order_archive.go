@@ -0,0 +1,19 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/order_archive.go
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// OrderArchive хранит заказы, вынесенные из рабочих таблиц по возрасту
+type OrderArchive interface {
+	// ArchiveBefore переносит в архив заказы за месяцы, целиком закончившиеся до before;
+	// возвращает число перенесенных месяцев
+	ArchiveBefore(ctx context.Context, before time.Time) (int, error)
+	// GetArchivedOrder возвращает заказ из архива с Archived = true
+	// или domain.ErrOrderNotFound, если в архиве его нет
+	GetArchivedOrder(ctx context.Context, id string) (entities.Order, error)
+}
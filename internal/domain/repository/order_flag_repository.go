@@ -0,0 +1,14 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/order_flag_repository.go
+package repository
+
+import (
+	"context"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+type OrderFlagRepository interface {
+	// ReplaceFlags заменяет все флаги заказа; пустой список снимает флаги
+	ReplaceFlags(ctx context.Context, orderUID string, flags []entities.OrderFlag) error
+	GetFlags(ctx context.Context, limit, offset int) ([]entities.OrderFlag, error)
+}
@@ -11,6 +11,11 @@ type OrderRepository interface {
 	GetOrder(ctx context.Context, id string) (entities.Order, error)
 	GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error)
 	GetOrdersCount(ctx context.Context) (int, error)
+	// GetRecentCursor возвращает курсор, после которого идут n самых свежих по date_created
+	// заказов; нулевой курсор, если заказов не больше n
+	GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error)
+	// GetOrdersAfter возвращает заказы строго после курсора по возрастанию (date_created, order_uid)
+	GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error)
 	DeleteOrder(ctx context.Context, id string) error
 	ClearOrders(ctx context.Context) error
 	Shutdown(ctx context.Context) error
@@ -0,0 +1,17 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/order_version_repository.go
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// OrderVersionRepository отдает отметки updated_at, по которым проверяется свежесть снимка кэша
+type OrderVersionRepository interface {
+	// GetVersions возвращает updated_at для заказов из ids; удаленных заказов в ответе нет
+	GetVersions(ctx context.Context, ids []string) (map[string]time.Time, error)
+	// GetUpdatedSince возвращает ID заказов, измененных после since, начиная с самых свежих
+	GetUpdatedSince(ctx context.Context, since time.Time, limit int) ([]string, error)
+	// GetLatestVersion возвращает максимальный updated_at по всем заказам
+	GetLatestVersion(ctx context.Context) (time.Time, error)
+}
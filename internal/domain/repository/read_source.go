@@ -0,0 +1,18 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/read_source.go
+package repository
+
+import "context"
+
+type primaryReadKey struct{}
+
+// WithPrimaryRead требует читать из primary, минуя реплики: ответ реплики может отставать
+// от записи, сделанной другим экземпляром сервиса
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+// PrimaryReadRequested - запрошено ли чтение из primary
+func PrimaryReadRequested(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadKey{}).(bool)
+	return primary
+}
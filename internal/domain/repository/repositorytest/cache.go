@@ -0,0 +1,192 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest/cache.go
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// contractCapacity - емкость кэша в подтестах, где вытеснение не проверяется
+const contractCapacity = 64
+
+// RunCache прогоняет контракт Cache. newCache вызывается в каждом подтесте и должен вернуть
+// пустой кэш на capacity заказов; Shutdown контракт вызывает сам при завершении подтеста
+func RunCache(t *testing.T, newCache func(t *testing.T, capacity int) domainrepo.Cache) {
+	open := func(t *testing.T, capacity int) domainrepo.Cache {
+		c := newCache(t, capacity)
+		t.Cleanup(func() {
+			assert.NoError(t, c.Shutdown(context.Background()))
+		})
+		return c
+	}
+
+	t.Run("SetAndGet", func(t *testing.T) { testCacheSetAndGet(t, open(t, contractCapacity)) })
+	t.Run("Overwrite", func(t *testing.T) { testCacheOverwrite(t, open(t, contractCapacity)) })
+	t.Run("Delete", func(t *testing.T) { testCacheDelete(t, open(t, contractCapacity)) })
+	t.Run("Clear", func(t *testing.T) { testCacheClear(t, open(t, contractCapacity)) })
+	t.Run("GetAllLimit", func(t *testing.T) { testCacheGetAllLimit(t, open(t, contractCapacity)) })
+	t.Run("Stats", func(t *testing.T) { testCacheStats(t, open(t, contractCapacity)) })
+	t.Run("CapacityBound", func(t *testing.T) { testCacheCapacityBound(t, open(t, 8)) })
+	t.Run("ConcurrentAccess", func(t *testing.T) { testCacheConcurrentAccess(t, open(t, contractCapacity)) })
+	t.Run("LargeItemList", func(t *testing.T) { testCacheLargeItemList(t, open(t, contractCapacity)) })
+}
+
+func testCacheSetAndGet(t *testing.T, c domainrepo.Cache) {
+	order := NewOrder("a", time.Now())
+	order.Items = Items(order.TrackNumber, 1, 3)
+	c.Set(order.OrderUID, order)
+
+	got, ok := c.Get("a")
+	require.True(t, ok, "expected order in cache")
+	AssertOrderEqual(t, order, got)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+func testCacheOverwrite(t *testing.T, c domainrepo.Cache) {
+	order := NewOrder("a", time.Now())
+	c.Set(order.OrderUID, order)
+
+	order.TrackNumber = "UPDATED"
+	order.Items = Items(order.TrackNumber, 5, 2)
+	c.Set(order.OrderUID, order)
+
+	got, ok := c.Get("a")
+	require.True(t, ok, "expected order in cache")
+	AssertOrderEqual(t, order, got)
+	assert.Equal(t, 1, c.Stats().Size)
+}
+
+func testCacheDelete(t *testing.T, c domainrepo.Cache) {
+	c.Set("a", NewOrder("a", time.Now()))
+	c.Set("b", NewOrder("b", time.Now()))
+
+	assert.True(t, c.Delete("a"))
+	assert.False(t, c.Delete("a"))
+	assert.False(t, c.Delete("missing"))
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.Stats().Size)
+}
+
+func testCacheClear(t *testing.T, c domainrepo.Cache) {
+	c.Clear()
+	for _, uid := range []string{"a", "b", "c"} {
+		c.Set(uid, NewOrder(uid, time.Now()))
+	}
+
+	c.Clear()
+
+	assert.Empty(t, c.GetAll(10))
+	assert.Zero(t, c.Stats().Size)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	// после очистки кэш продолжает работать
+	c.Set("d", NewOrder("d", time.Now()))
+	_, ok = c.Get("d")
+	assert.True(t, ok)
+}
+
+func testCacheGetAllLimit(t *testing.T, c domainrepo.Cache) {
+	uids := []string{"a", "b", "c", "d", "e"}
+	for _, uid := range uids {
+		c.Set(uid, NewOrder(uid, time.Now()))
+	}
+
+	assert.Len(t, c.GetAll(3), 3)
+	assert.ElementsMatch(t, uids, orderUIDs(c.GetAll(10)))
+	assert.Empty(t, c.GetAll(0))
+	assert.Empty(t, c.GetAll(-1))
+}
+
+func testCacheStats(t *testing.T, c domainrepo.Cache) {
+	c.Set("a", NewOrder("a", time.Now()))
+	before := c.Stats()
+
+	_, ok := c.Get("a")
+	require.True(t, ok)
+	_, ok = c.Get("missing")
+	require.False(t, ok)
+
+	after := c.Stats()
+	assert.Equal(t, before.Hits+1, after.Hits)
+	assert.Equal(t, before.Misses+1, after.Misses)
+	assert.Equal(t, 1, after.Size)
+	assert.InDelta(t, float64(after.Hits)/float64(after.Hits+after.Misses), after.HitRatio, 1e-9)
+}
+
+func testCacheCapacityBound(t *testing.T, c domainrepo.Cache) {
+	const inserted = 100
+	for i := 0; i < inserted; i++ {
+		uid := fmt.Sprintf("order-%d", i)
+		c.Set(uid, NewOrder(uid, time.Now()))
+	}
+
+	// шардированный кэш округляет емкость по сегментам, поэтому граница - Stats().Capacity
+	stats := c.Stats()
+	require.Positive(t, stats.Capacity)
+	assert.LessOrEqual(t, stats.Size, stats.Capacity)
+	assert.Less(t, stats.Capacity, inserted)
+	assert.LessOrEqual(t, len(c.GetAll(inserted)), stats.Capacity)
+}
+
+func testCacheConcurrentAccess(t *testing.T, c domainrepo.Cache) {
+	const workers, ops, keys = 8, 100, 16
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < ops; i++ {
+				uid := fmt.Sprintf("order-%d", (worker+i)%keys)
+				switch i % 4 {
+				case 0, 1:
+					c.Set(uid, NewOrder(uid, time.Now()))
+				case 2:
+					if got, ok := c.Get(uid); ok {
+						assert.Equal(t, uid, got.OrderUID)
+					}
+				case 3:
+					if worker == 0 {
+						c.Delete(uid)
+					} else {
+						c.GetAll(keys)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, c.Stats().Size, keys)
+	for _, got := range c.GetAll(keys) {
+		cached, ok := c.Get(got.OrderUID)
+		if assert.True(t, ok) {
+			AssertOrderEqual(t, got, cached)
+		}
+	}
+}
+
+func testCacheLargeItemList(t *testing.T, c domainrepo.Cache) {
+	order := NewOrder("large", time.Now())
+	order.Items = Items(order.TrackNumber, 1, large)
+	c.Set(order.OrderUID, order)
+
+	got, ok := c.Get(order.OrderUID)
+	require.True(t, ok, "expected order with %d items in cache", large)
+	AssertOrderEqual(t, order, got)
+}
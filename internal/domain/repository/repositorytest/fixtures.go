@@ -0,0 +1,101 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest/fixtures.go
+
+// Package repositorytest - контрактные тесты для реализаций repository.OrderRepository
+// и repository.Cache. Новый бэкенд подключается одной функцией-фабрикой и проходит
+// тот же набор проверок, что и Postgres
+package repositorytest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// NewOrder возвращает заполненный заказ, который проходит ограничения схемы Postgres.
+// DateCreated округлен до микросекунд, PaymentDT - до секунд, как их хранят БД
+func NewOrder(uid string, created time.Time) entities.Order {
+	track := "TRACK-" + uid
+	return entities.Order{
+		OrderUID:        uid,
+		TrackNumber:     track,
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      "customer-" + uid,
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SMID:            99,
+		DateCreated:     entities.NewTimestamp(created),
+		OOFShard:        "1",
+		Delivery: entities.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: entities.Payment{
+			Transaction:  "tx-" + uid,
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1817,
+			PaymentDT:    entities.NewUnixTime(created.Truncate(time.Second)),
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+		},
+		Items: Items(track, 1, 1),
+	}
+}
+
+// Items возвращает n товаров с chrt_id от first по возрастанию
+func Items(track string, first, n int) []entities.Item {
+	items := make([]entities.Item, 0, n)
+	for i := 0; i < n; i++ {
+		chrtID := first + i
+		items = append(items, entities.Item{
+			ChrtID:      chrtID,
+			TrackNumber: track,
+			Price:       453,
+			RID:         fmt.Sprintf("rid-%d", chrtID),
+			Name:        fmt.Sprintf("item-%d", chrtID),
+			Sale:        30,
+			Size:        "0",
+			TotalPrice:  317,
+			NmID:        2389212,
+			Brand:       "Vivienne Sabo",
+			Status:      202,
+		})
+	}
+	return items
+}
+
+// AssertOrderEqual сравнивает заказы без учета порядка товаров: его хранилища не обещают
+func AssertOrderEqual(t *testing.T, want, got entities.Order) bool {
+	t.Helper()
+	want, got = sortItems(want), sortItems(got)
+	return assert.True(t, want.Equal(got), "orders differ:\nwant %+v\ngot  %+v", want, got)
+}
+
+func sortItems(order entities.Order) entities.Order {
+	order.Items = slices.Clone(order.Items)
+	slices.SortFunc(order.Items, func(a, b entities.Item) int {
+		return cmp.Compare(a.ChrtID, b.ChrtID)
+	})
+	return order
+}
+
+func orderUIDs(orders []entities.Order) []string {
+	ids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, o.OrderUID)
+	}
+	return ids
+}
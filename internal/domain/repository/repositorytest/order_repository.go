@@ -0,0 +1,347 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest/order_repository.go
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// large - число товаров в заказе для проверки больших списков
+const large = 1000
+
+// RunOrderRepository прогоняет контракт OrderRepository. newRepo вызывается в каждом
+// подтесте и должен вернуть пустое хранилище; освобождение ресурсов - забота фабрики
+func RunOrderRepository(t *testing.T, newRepo func(t *testing.T) domainrepo.OrderRepository) {
+	t.Run("SaveAndGet", func(t *testing.T) { testSaveAndGet(t, newRepo(t)) })
+	t.Run("GetMissing", func(t *testing.T) { testGetMissing(t, newRepo(t)) })
+	t.Run("UpdateReplacesOrder", func(t *testing.T) { testUpdateReplacesOrder(t, newRepo(t)) })
+	t.Run("ListByOrderUID", func(t *testing.T) { testListByOrderUID(t, newRepo(t)) })
+	t.Run("RecentKeyset", func(t *testing.T) { testRecentKeyset(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("Clear", func(t *testing.T) { testClear(t, newRepo(t)) })
+	t.Run("ConcurrentWrites", func(t *testing.T) { testConcurrentWrites(t, newRepo(t)) })
+	t.Run("LargeItemList", func(t *testing.T) { testLargeItemList(t, newRepo(t)) })
+	t.Run("TrackNumberUnique", func(t *testing.T) { testTrackNumberUnique(t, newRepo(t)) })
+	t.Run("ItemKeyUnique", func(t *testing.T) { testItemKeyUnique(t, newRepo(t)) })
+}
+
+func testSaveAndGet(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	order := NewOrder("order-1", time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC))
+	order.Items = Items(order.TrackNumber, 10, 3)
+	require.NoError(t, repo.SaveOrder(ctx, order))
+
+	got, err := repo.GetOrder(ctx, order.OrderUID)
+	require.NoError(t, err)
+	AssertOrderEqual(t, order, got)
+
+	count, err := repo.GetOrdersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func testGetMissing(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	_, err := repo.GetOrder(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.ErrorIs(t, repo.DeleteOrder(ctx, "missing"), domain.ErrOrderNotFound)
+
+	orders, err := repo.GetAllOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+
+	count, err := repo.GetOrdersCount(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	cursor, err := repo.GetRecentCursor(ctx, 0)
+	require.NoError(t, err)
+	assert.True(t, cursor.IsZero())
+}
+
+func testUpdateReplacesOrder(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	order := NewOrder("order-1", time.Now())
+	order.Items = Items(order.TrackNumber, 1, 3)
+	require.NoError(t, repo.SaveOrder(ctx, order))
+
+	// повторное сохранение заменяет все части заказа, а товары - целиком
+	order.TrackNumber = "UPDATED"
+	order.Delivery.Name = "Updated Name"
+	order.Payment.Amount = 2000
+	order.Items = Items(order.TrackNumber, 3, 2)
+	order.Items[0].Price = 500
+	require.NoError(t, repo.SaveOrder(ctx, order))
+
+	got, err := repo.GetOrder(ctx, order.OrderUID)
+	require.NoError(t, err)
+	AssertOrderEqual(t, order, got)
+
+	count, err := repo.GetOrdersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func testListByOrderUID(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	saved := map[string]entities.Order{}
+	for i, uid := range []string{"d", "b", "a", "c", "e"} {
+		order := NewOrder(uid, time.Now())
+		order.Items = Items(order.TrackNumber, 1, i+1)
+		require.NoError(t, repo.SaveOrder(ctx, order))
+		saved[uid] = order
+	}
+
+	page, err := repo.GetAllOrders(ctx, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, orderUIDs(page))
+	for _, got := range page {
+		AssertOrderEqual(t, saved[got.OrderUID], got)
+	}
+
+	page, err = repo.GetAllOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, orderUIDs(page))
+
+	page, err = repo.GetAllOrders(ctx, 10, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e"}, orderUIDs(page))
+
+	page, err = repo.GetAllOrders(ctx, 10, 5)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+
+	count, err := repo.GetOrdersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
+
+func testRecentKeyset(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// у b и c одинаковое время - порядок решает order_uid
+	require.NoError(t, repo.SaveOrder(ctx, NewOrder("a", base)))
+	require.NoError(t, repo.SaveOrder(ctx, NewOrder("c", base.Add(time.Hour))))
+	require.NoError(t, repo.SaveOrder(ctx, NewOrder("b", base.Add(time.Hour))))
+	require.NoError(t, repo.SaveOrder(ctx, NewOrder("d", base.Add(2*time.Hour))))
+
+	cursor, err := repo.GetRecentCursor(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "b", cursor.OrderUID)
+	assert.True(t, cursor.DateCreated.Equal(base.Add(time.Hour)))
+
+	after, err := repo.GetOrdersAfter(ctx, cursor, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"c", "d"}, orderUIDs(after))
+
+	after, err = repo.GetOrdersAfter(ctx, entities.CursorOf(after[0]), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d"}, orderUIDs(after))
+
+	// курсор за пределами хранилища - нулевой, и с него читается все с начала
+	cursor, err = repo.GetRecentCursor(ctx, 4)
+	require.NoError(t, err)
+	assert.True(t, cursor.IsZero())
+
+	after, err = repo.GetOrdersAfter(ctx, cursor, 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, orderUIDs(after))
+	assert.Len(t, after[0].Items, 1)
+}
+
+func testDelete(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	for _, uid := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.SaveOrder(ctx, NewOrder(uid, time.Now())))
+	}
+
+	require.NoError(t, repo.DeleteOrder(ctx, "b"))
+	_, err := repo.GetOrder(ctx, "b")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.ErrorIs(t, repo.DeleteOrder(ctx, "b"), domain.ErrOrderNotFound)
+
+	orders, err := repo.GetAllOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, orderUIDs(orders))
+
+	// заказ с тем же order_uid сохраняется заново без остатков удаленного
+	order := NewOrder("b", time.Now())
+	order.Items = Items(order.TrackNumber, 5, 1)
+	require.NoError(t, repo.SaveOrder(ctx, order))
+	got, err := repo.GetOrder(ctx, "b")
+	require.NoError(t, err)
+	AssertOrderEqual(t, order, got)
+}
+
+func testClear(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	require.NoError(t, repo.ClearOrders(ctx))
+	for _, uid := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.SaveOrder(ctx, NewOrder(uid, time.Now())))
+	}
+
+	require.NoError(t, repo.ClearOrders(ctx))
+
+	count, err := repo.GetOrdersCount(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	orders, err := repo.GetAllOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+
+	_, err = repo.GetOrder(ctx, "a")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func testConcurrentWrites(t *testing.T, repo domainrepo.OrderRepository) {
+	const workers, perWorker = 8, 10
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				uid := fmt.Sprintf("w%d-%d", worker, j)
+				assert.NoError(t, repo.SaveOrder(ctx, NewOrder(uid, time.Now())))
+				_, err := repo.GetOrder(ctx, uid)
+				assert.NoError(t, err)
+
+				// все пишут один и тот же заказ со своим набором товаров
+				shared := NewOrder("shared", time.Now())
+				shared.TrackNumber = fmt.Sprintf("TRACK-w%d", worker)
+				shared.Items = Items(shared.TrackNumber, worker*100+1, 2)
+				assert.NoError(t, repo.SaveOrder(ctx, shared))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	count, err := repo.GetOrdersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, workers*perWorker+1, count)
+
+	// победила одна из записей целиком: товары не смешались между писателями
+	shared, err := repo.GetOrder(ctx, "shared")
+	require.NoError(t, err)
+	require.Len(t, shared.Items, 2)
+	for _, item := range shared.Items {
+		assert.Equal(t, shared.TrackNumber, item.TrackNumber)
+	}
+}
+
+func testLargeItemList(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	order := NewOrder("large", time.Now())
+	order.Items = Items(order.TrackNumber, 1, large)
+	require.NoError(t, repo.SaveOrder(ctx, order))
+
+	got, err := repo.GetOrder(ctx, order.OrderUID)
+	require.NoError(t, err)
+	AssertOrderEqual(t, order, got)
+
+	orders, err := repo.GetAllOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Len(t, orders[0].Items, large)
+
+	order.Items = Items(order.TrackNumber, large/2, large/2)
+	require.NoError(t, repo.SaveOrder(ctx, order))
+
+	got, err = repo.GetOrder(ctx, order.OrderUID)
+	require.NoError(t, err)
+	AssertOrderEqual(t, order, got)
+}
+
+func testTrackNumberUnique(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	a := NewOrder("a", time.Now())
+	require.NoError(t, repo.SaveOrder(ctx, a))
+
+	// чужой номер - конфликт данных, заказ не сохраняется
+	b := NewOrder("b", time.Now())
+	b.TrackNumber = a.TrackNumber
+	assertConflict(t, repo.SaveOrder(ctx, b), "track_number", entities.ErrTrackNumberTaken)
+	_, err := repo.GetOrder(ctx, "b")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	// свой номер при обновлении не конфликтует
+	require.NoError(t, repo.SaveOrder(ctx, a))
+
+	// номер освобождается, когда владелец его меняет или удаляется
+	old := a.TrackNumber
+	a.TrackNumber = "TRACK-a2"
+	require.NoError(t, repo.SaveOrder(ctx, a))
+	require.NoError(t, repo.SaveOrder(ctx, b))
+
+	c := NewOrder("c", time.Now())
+	c.TrackNumber = a.TrackNumber
+	assertConflict(t, repo.SaveOrder(ctx, c), "track_number", entities.ErrTrackNumberTaken)
+	require.NoError(t, repo.DeleteOrder(ctx, "a"))
+	require.NoError(t, repo.SaveOrder(ctx, c))
+
+	got, err := repo.GetOrder(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, old, got.TrackNumber)
+}
+
+func testItemKeyUnique(t *testing.T, repo domainrepo.OrderRepository) {
+	ctx := context.Background()
+
+	// ключ товара - (chrt_id, order_uid): повтор внутри заказа отклоняется целиком
+	order := NewOrder("a", time.Now())
+	order.Items = append(Items(order.TrackNumber, 1, 2), Items(order.TrackNumber, 2, 1)...)
+	assertConflict(t, repo.SaveOrder(ctx, order), "items[2].chrt_id", entities.ErrDuplicateChrtID)
+	_, err := repo.GetOrder(ctx, "a")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	// тот же chrt_id в разных заказах допустим
+	order.Items = Items(order.TrackNumber, 1, 2)
+	require.NoError(t, repo.SaveOrder(ctx, order))
+	other := NewOrder("b", time.Now())
+	other.Items = Items(other.TrackNumber, 1, 2)
+	require.NoError(t, repo.SaveOrder(ctx, other))
+
+	// неудачное обновление не трогает сохраненные товары
+	bad := order
+	bad.Items = Items(order.TrackNumber, 5, 1)
+	bad.Items = append(bad.Items, bad.Items[0])
+	assertConflict(t, repo.SaveOrder(ctx, bad), "items[1].chrt_id", entities.ErrDuplicateChrtID)
+	got, err := repo.GetOrder(ctx, "a")
+	require.NoError(t, err)
+	AssertOrderEqual(t, order, got)
+}
+
+// assertConflict проверяет, что ошибка - конфликт данных (не повторяется) по полю field
+func assertConflict(t *testing.T, err error, field string, target error) {
+	t.Helper()
+	require.ErrorIs(t, err, domain.ErrInvalidOrder)
+	assert.ErrorIs(t, err, target)
+
+	var verrs entities.ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 1)
+	assert.Equal(t, field, verrs[0].Field)
+}
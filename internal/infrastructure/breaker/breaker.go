@@ -0,0 +1,214 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker/breaker.go
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+// Config: после FailureThreshold ошибок подряд предохранитель размыкается на OpenTimeout,
+// затем пропускает HalfOpenRequests пробных вызовов; нулевые значения заменяются значениями по умолчанию
+type Config struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenRequests int
+}
+
+// Breaker - предохранитель closed/open/half-open. Ошибки из ignore и отмена контекста
+// вызывающим не считаются отказом зависимости
+type Breaker struct {
+	name   string
+	config Config
+	ignore []error
+	logger domainrepo.Logger
+	now    func() time.Time
+
+	mu         sync.Mutex
+	state      entities.CircuitState
+	generation uint64
+	failures   int
+	successes  int
+	inFlight   int
+	openedAt   time.Time
+	opens      uint64
+	rejected   uint64
+}
+
+func New(name string, config Config, l domainrepo.Logger, ignore ...error) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultOpenTimeout
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = defaultHalfOpenRequests
+	}
+	return &Breaker{
+		name:   name,
+		config: config,
+		ignore: ignore,
+		logger: l,
+		now:    time.Now,
+		state:  entities.CircuitClosed,
+	}
+}
+
+// Execute вызывает fn, если предохранитель его пропускает, иначе сразу возвращает domain.ErrCircuitOpen;
+// nil-предохранитель пропускает все вызовы
+func (b *Breaker) Execute(fn func() error) error {
+	if b == nil {
+		return fn()
+	}
+	generation, err := b.acquire()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	b.release(generation, err)
+	return err
+}
+
+func (b *Breaker) acquire() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == entities.CircuitOpen {
+		if b.now().Sub(b.openedAt) < b.config.OpenTimeout {
+			b.rejected++
+			return 0, fmt.Errorf("%w: %s", domain.ErrCircuitOpen, b.name)
+		}
+		b.setState(entities.CircuitHalfOpen)
+	}
+
+	if b.state == entities.CircuitHalfOpen {
+		if b.inFlight >= b.config.HalfOpenRequests-b.successes {
+			b.rejected++
+			return 0, fmt.Errorf("%w: %s", domain.ErrCircuitOpen, b.name)
+		}
+		b.inFlight++
+	}
+	return b.generation, nil
+}
+
+// release учитывает результат; вызовы, начатые до смены состояния, на него уже не влияют
+func (b *Breaker) release(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	halfOpen := b.state == entities.CircuitHalfOpen
+	if halfOpen {
+		b.inFlight--
+	}
+
+	if !b.isFailure(err) {
+		if err == nil {
+			b.failures = 0
+		}
+		if halfOpen && err == nil {
+			b.successes++
+			if b.successes >= b.config.HalfOpenRequests {
+				b.setState(entities.CircuitClosed)
+			}
+		}
+		return
+	}
+
+	b.failures++
+	if halfOpen || (b.state == entities.CircuitClosed && b.failures >= b.config.FailureThreshold) {
+		b.logger.Warn("circuit breaker opened",
+			"breaker", b.name,
+			"failures", b.failures,
+			"open_timeout", b.config.OpenTimeout,
+			"error", err,
+		)
+		b.setState(entities.CircuitOpen)
+	}
+}
+
+// isFailure: nil, отмена контекста и ожидаемые ошибки (например, заказ не найден) отказом не считаются
+func (b *Breaker) isFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, domain.ErrCircuitOpen) {
+		return false
+	}
+	for _, target := range b.ignore {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// setState вызывается под mu
+func (b *Breaker) setState(state entities.CircuitState) {
+	if b.state == state {
+		return
+	}
+	prev := b.state
+	b.state = state
+	b.generation++
+	b.successes = 0
+	b.inFlight = 0
+
+	switch state {
+	case entities.CircuitOpen:
+		b.openedAt = b.now()
+		b.opens++
+	case entities.CircuitClosed:
+		b.failures = 0
+		b.logger.Info("circuit breaker closed", "breaker", b.name)
+	}
+	b.logger.Debug("circuit breaker state changed",
+		"breaker", b.name,
+		"from", string(prev),
+		"to", string(state),
+	)
+}
+
+func (b *Breaker) Stats() entities.CircuitBreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := entities.CircuitBreakerStats{
+		Name:     b.name,
+		State:    b.state,
+		Failures: b.failures,
+		Opens:    b.opens,
+		Rejected: b.rejected,
+	}
+	if b.state != entities.CircuitClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
+
+func (b *Breaker) RetryAfter() time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != entities.CircuitOpen {
+		return 0
+	}
+	return max(b.config.OpenTimeout-b.now().Sub(b.openedAt), 0)
+}
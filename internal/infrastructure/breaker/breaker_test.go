@@ -0,0 +1,142 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker/breaker_test.go
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger"
+)
+
+var errDown = errors.New("connection refused")
+
+// newTestBreaker возвращает предохранитель с управляемыми часами
+func newTestBreaker(t *testing.T, config Config, ignore ...error) (*Breaker, *time.Time) {
+	l, err := logger.NewLogger(logger.DEV)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := New("storage", config, l, ignore...)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail() error    { return errDown }
+func succeed() error { return nil }
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(t, Config{FailureThreshold: 3, OpenTimeout: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, b.Execute(fail), errDown)
+	}
+	assert.Equal(t, entities.CircuitClosed, b.Stats().State)
+	assert.Equal(t, 2, b.Stats().Failures)
+
+	assert.ErrorIs(t, b.Execute(fail), errDown)
+	assert.Equal(t, entities.CircuitOpen, b.Stats().State)
+	assert.Equal(t, time.Minute, b.RetryAfter())
+
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	assert.ErrorIs(t, err, domain.ErrCircuitOpen)
+	assert.False(t, called, "open breaker must not call through")
+
+	stats := b.Stats()
+	assert.Equal(t, uint64(1), stats.Opens)
+	assert.Equal(t, uint64(1), stats.Rejected)
+	require.NotNil(t, stats.OpenedAt)
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(t, Config{FailureThreshold: 2})
+
+	assert.Error(t, b.Execute(fail))
+	assert.NoError(t, b.Execute(succeed))
+	assert.Error(t, b.Execute(fail))
+
+	assert.Equal(t, entities.CircuitClosed, b.Stats().State)
+	assert.Equal(t, 1, b.Stats().Failures)
+}
+
+func TestBreaker_IgnoredErrors(t *testing.T) {
+	b, _ := newTestBreaker(t, Config{FailureThreshold: 1}, domain.ErrOrderNotFound)
+
+	assert.ErrorIs(t, b.Execute(func() error { return domain.ErrOrderNotFound }), domain.ErrOrderNotFound)
+	assert.ErrorIs(t, b.Execute(func() error { return fmt.Errorf("query: %w", context.Canceled) }), context.Canceled)
+
+	assert.Equal(t, entities.CircuitClosed, b.Stats().State)
+	assert.Zero(t, b.Stats().Failures)
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	t.Run("closes after successful probes", func(t *testing.T) {
+		b, now := newTestBreaker(t, Config{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenRequests: 2})
+		require.Error(t, b.Execute(fail))
+
+		*now = now.Add(30 * time.Second)
+		assert.ErrorIs(t, b.Execute(succeed), domain.ErrCircuitOpen)
+		assert.Equal(t, 30*time.Second, b.RetryAfter())
+
+		*now = now.Add(30 * time.Second)
+		assert.Zero(t, b.RetryAfter())
+		require.NoError(t, b.Execute(succeed))
+		assert.Equal(t, entities.CircuitHalfOpen, b.Stats().State)
+
+		require.NoError(t, b.Execute(succeed))
+		assert.Equal(t, entities.CircuitClosed, b.Stats().State)
+		assert.Nil(t, b.Stats().OpenedAt)
+	})
+
+	t.Run("failed probe reopens", func(t *testing.T) {
+		b, now := newTestBreaker(t, Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+		require.Error(t, b.Execute(fail))
+
+		*now = now.Add(time.Minute)
+		require.Error(t, b.Execute(fail))
+		assert.Equal(t, entities.CircuitOpen, b.Stats().State)
+		assert.Equal(t, uint64(2), b.Stats().Opens)
+		assert.Equal(t, time.Minute, b.RetryAfter())
+	})
+
+	t.Run("limits concurrent probes", func(t *testing.T) {
+		b, now := newTestBreaker(t, Config{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenRequests: 1})
+		require.Error(t, b.Execute(fail))
+		*now = now.Add(time.Minute)
+
+		var inner error
+		err := b.Execute(func() error {
+			inner = b.Execute(succeed)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.ErrorIs(t, inner, domain.ErrCircuitOpen, "second probe must wait for the first one")
+		assert.Equal(t, entities.CircuitClosed, b.Stats().State)
+	})
+}
+
+func TestBreaker_StaleResultIgnored(t *testing.T) {
+	b, _ := newTestBreaker(t, Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	// вызов начат в closed, а закончился уже после размыкания другим вызовом
+	err := b.Execute(func() error {
+		require.Error(t, b.Execute(fail))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.CircuitOpen, b.Stats().State)
+}
+
+func TestBreaker_Nil(t *testing.T) {
+	var b *Breaker
+	assert.ErrorIs(t, b.Execute(fail), errDown)
+	assert.Zero(t, b.RetryAfter())
+}
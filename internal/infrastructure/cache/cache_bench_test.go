@@ -0,0 +1,62 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/cache_bench_test.go
+package cache
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+const benchKeys = 10000
+
+func benchOrderIDs() []string {
+	ids := make([]string, benchKeys)
+	for i := range ids {
+		ids[i] = "b563feb7b2b84b6test" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+// benchmarkParallel: writePercent процентов операций - Set, остальные - Get
+func benchmarkParallel(b *testing.B, cache domainrepo.Cache, writePercent int) {
+	ids := benchOrderIDs()
+	for _, id := range ids {
+		cache.Set(id, entities.Order{OrderUID: id})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			id := ids[r.Intn(len(ids))]
+			if r.Intn(100) < writePercent {
+				cache.Set(id, entities.Order{OrderUID: id})
+			} else {
+				cache.Get(id)
+			}
+		}
+	})
+}
+
+func BenchmarkCache_Parallel(b *testing.B) {
+	caches := []struct {
+		name string
+		new  func() domainrepo.Cache
+	}{
+		{"lru", func() domainrepo.Cache { return NewOrderLRUCache(&MockLogger{}, benchKeys) }},
+		{"sharded-16", func() domainrepo.Cache { return NewShardedOrderCache(&MockLogger{}, benchKeys, 16, PolicyLRU) }},
+		{"sharded-64", func() domainrepo.Cache { return NewShardedOrderCache(&MockLogger{}, benchKeys, 64, PolicyLRU) }},
+	}
+
+	for _, writes := range []int{0, 10, 50} {
+		for _, c := range caches {
+			b.Run(c.name+"/writes-"+strconv.Itoa(writes)+"%", func(b *testing.B) {
+				benchmarkParallel(b, c.new(), writes)
+			})
+		}
+	}
+}
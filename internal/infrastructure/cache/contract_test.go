@@ -0,0 +1,48 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/contract_test.go
+package cache
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
+)
+
+func TestCacheContract(t *testing.T) {
+	backends := map[string]func(t *testing.T, capacity int) domainrepo.Cache{
+		"lru": func(t *testing.T, capacity int) domainrepo.Cache {
+			return NewOrderLRUCache(&MockLogger{}, capacity)
+		},
+		"sharded": func(t *testing.T, capacity int) domainrepo.Cache {
+			return NewShardedOrderCache(&MockLogger{}, capacity, 4, PolicyLRU)
+		},
+		"redis": func(t *testing.T, capacity int) domainrepo.Cache {
+			return newContractRedisCache(t, capacity)
+		},
+		"tiered": func(t *testing.T, capacity int) domainrepo.Cache {
+			local := NewOrderLRUCache(&MockLogger{}, capacity)
+			return NewTieredOrderCache(local, newContractRedisCache(t, capacity), &MockLogger{})
+		},
+	}
+	for _, policy := range []Policy{PolicyLFU, PolicyARC, PolicyTinyLFU} {
+		backends[string(policy)] = func(t *testing.T, capacity int) domainrepo.Cache {
+			return NewPolicyOrderCache(&MockLogger{}, capacity, policy)
+		}
+	}
+
+	for name, newCache := range backends {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.RunCache(t, newCache)
+		})
+	}
+}
+
+// newContractRedisCache не закрывает клиента: соединение закрывает Shutdown кэша
+func newContractRedisCache(t *testing.T, capacity int) domainrepo.Cache {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewRedisOrderCache(client, &MockLogger{}, RedisOptions{Capacity: capacity})
+}
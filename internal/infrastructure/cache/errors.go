@@ -0,0 +1,11 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/errors.go
+package cache
+
+import "errors"
+
+var (
+	ErrSnapshotNotFound  = errors.New("cache snapshot not found")
+	ErrSnapshotCorrupt   = errors.New("cache snapshot is corrupt")
+	ErrSnapshotWrite     = errors.New("failed to write cache snapshot")
+	ErrRestoreInProgress = errors.New("cache restoration already in progress")
+)
@@ -0,0 +1,68 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/options.go
+package cache
+
+import "time"
+
+const defaultSweepInterval = time.Minute
+
+type options struct {
+	ttl           time.Duration
+	sweepInterval time.Duration
+	maxBytes      int64
+	now           func() time.Time
+}
+
+type Option func(*options)
+
+// WithTTL задает время жизни записи; 0 - записи не устаревают
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithSweepInterval задает период фоновой очистки устаревших записей
+func WithSweepInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.sweepInterval = d
+	}
+}
+
+// WithMaxBytes ограничивает суммарный оценочный размер заказов в кэше; 0 - без ограничения
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		sweepInterval: defaultSweepInterval,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.sweepInterval <= 0 {
+		o.sweepInterval = defaultSweepInterval
+	}
+	return o
+}
+
+func (o options) expiresAt() time.Time {
+	if o.ttl <= 0 {
+		return time.Time{}
+	}
+	return o.now().Add(o.ttl)
+}
+
+func (o options) expired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && !o.now().Before(expiresAt)
+}
+
+// withClock подменяет источник времени в тестах
+func withClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
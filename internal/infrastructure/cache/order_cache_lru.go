@@ -5,14 +5,17 @@ import (
 	"container/list"
 	"context"
 	"sync"
+	"time"
 
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 )
 
 type entry struct {
-	key   string
-	value entities.Order
+	key       string
+	value     entities.Order
+	size      int64
+	expiresAt time.Time
 }
 
 type orderLRUCache struct {
@@ -21,18 +24,38 @@ type orderLRUCache struct {
 	cache    map[string]*list.Element
 	ll       *list.List
 	logger   domainrepo.Logger
+
+	opts      options
+	bytes     int64
+	counters  counters
+	stopSweep chan struct{}
+	stopOnce  sync.Once
 }
 
-func NewOrderLRUCache(l domainrepo.Logger, capacity int) domainrepo.Cache {
+func NewOrderLRUCache(l domainrepo.Logger, capacity int, opts ...Option) domainrepo.Cache {
 	if capacity <= 0 {
-		capacity = -1
 		l.Info("cache capacity is set to unlimited")
 	}
+	c := newOrderLRUCache(l, capacity, newOptions(opts))
+	if c.opts.ttl > 0 {
+		go sweepLoop(c.opts.sweepInterval, c.stopSweep, c.sweep)
+	}
+	return c
+}
+
+// newOrderLRUCache не запускает фоновую очистку: для сегментов шардированного
+// кэша ее запускает владелец, одну на все сегменты
+func newOrderLRUCache(l domainrepo.Logger, capacity int, o options) *orderLRUCache {
+	if capacity <= 0 {
+		capacity = -1
+	}
 	return &orderLRUCache{
-		capacity: capacity,
-		cache:    make(map[string]*list.Element, max(0, capacity)),
-		ll:       list.New(),
-		logger:   l,
+		capacity:  capacity,
+		cache:     make(map[string]*list.Element, max(0, capacity)),
+		ll:        list.New(),
+		logger:    l,
+		opts:      o,
+		stopSweep: make(chan struct{}),
 	}
 }
 
@@ -40,10 +63,24 @@ func (c *orderLRUCache) Set(orderID string, order entities.Order) {
 	c.RWMutex.Lock()
 	defer c.RWMutex.Unlock()
 
+	size := estimateOrderSize(orderID, order)
+	if c.opts.maxBytes > 0 && size > c.opts.maxBytes {
+		// заказ больше всего бюджета: не кэшируем, иначе он вытеснит все остальное
+		if elem, exist := c.cache[orderID]; exist {
+			c.removeElement(elem)
+		}
+		c.logger.Warn("order exceeds cache max_bytes, not cached", "order_id", orderID, "size", size)
+		return
+	}
+
 	if elem, exist := c.cache[orderID]; exist {
 		entry := elem.Value.(*entry)
+		c.bytes += size - entry.size
 		entry.value = order
+		entry.size = size
+		entry.expiresAt = c.opts.expiresAt()
 		c.ll.MoveToFront(elem)
+		c.evict(elem)
 		c.logger.Debug("order updated in cache", "order_id", orderID)
 		return
 	}
@@ -51,16 +88,42 @@ func (c *orderLRUCache) Set(orderID string, order entities.Order) {
 	if c.capacity > 0 && c.ll.Len() >= c.capacity {
 		lastElem := c.ll.Back()
 		if lastElem != nil {
-			lastEntry := lastElem.Value.(*entry)
-			delete(c.cache, lastEntry.key)
-			c.ll.Remove(lastElem)
+			lastEntry := c.removeElement(lastElem)
+			c.counters.evictions++
 			c.logger.Debug("cache exceeded, most unused order deleted", "order_id", lastEntry.key)
 		}
 	}
 
-	newEntry := &entry{key: orderID, value: order}
+	newEntry := &entry{key: orderID, value: order, size: size, expiresAt: c.opts.expiresAt()}
 	newElem := c.ll.PushFront(newEntry)
 	c.cache[orderID] = newElem
+	c.bytes += size
+	c.evict(newElem)
+}
+
+// evict вытесняет самые старые записи, пока кэш не уложится в max_bytes;
+// keep - только что записанный элемент, он не вытесняется
+func (c *orderLRUCache) evict(keep *list.Element) {
+	if c.opts.maxBytes <= 0 {
+		return
+	}
+	for c.bytes > c.opts.maxBytes {
+		lastElem := c.ll.Back()
+		if lastElem == nil || lastElem == keep {
+			return
+		}
+		lastEntry := c.removeElement(lastElem)
+		c.counters.evictions++
+		c.logger.Debug("cache max_bytes exceeded, most unused order deleted", "order_id", lastEntry.key)
+	}
+}
+
+func (c *orderLRUCache) removeElement(elem *list.Element) *entry {
+	e := elem.Value.(*entry)
+	c.ll.Remove(elem)
+	delete(c.cache, e.key)
+	c.bytes -= e.size
+	return e
 }
 
 // не стал использовать RLock и оборачивать отдельно Lock - c.ll.MoveToFront(elem)
@@ -70,12 +133,21 @@ func (c *orderLRUCache) Get(orderID string) (entities.Order, bool) {
 
 	elem, exist := c.cache[orderID]
 	if !exist {
+		c.counters.misses++
 		c.logger.Debug("there is no such order", "order_id", orderID)
 		return entities.Order{}, false
 	}
 
-	c.ll.MoveToFront(elem)
 	entry := elem.Value.(*entry)
+	if c.opts.expired(entry.expiresAt) {
+		c.removeElement(elem)
+		c.counters.misses++
+		c.logger.Debug("order in cache expired", "order_id", orderID)
+		return entities.Order{}, false
+	}
+
+	c.counters.hits++
+	c.ll.MoveToFront(elem)
 	c.logger.Debug("retrieved order from cache", "order_id", orderID)
 	return entry.value, true
 }
@@ -102,6 +174,9 @@ func (c *orderLRUCache) GetAll(limit int) []entities.Order {
 	count := 0
 	for elem := c.ll.Front(); elem != nil && count < limit; elem = elem.Next() {
 		entry := elem.Value.(*entry)
+		if c.opts.expired(entry.expiresAt) {
+			continue
+		}
 		orders = append(orders, entry.value)
 		count++
 	}
@@ -113,13 +188,18 @@ func (c *orderLRUCache) GetAll(limit int) []entities.Order {
 	return orders
 }
 
+func (c *orderLRUCache) Stats() entities.CacheStats {
+	c.RWMutex.RLock()
+	defer c.RWMutex.RUnlock()
+	return c.counters.stats(string(PolicyLRU), c.ll.Len(), max(0, c.capacity), c.bytes, c.opts.maxBytes)
+}
+
 func (c *orderLRUCache) Delete(orderID string) bool {
 	c.RWMutex.Lock()
 	defer c.RWMutex.Unlock()
 
 	if elem, exist := c.cache[orderID]; exist {
-		c.ll.Remove(elem)
-		delete(c.cache, orderID)
+		c.removeElement(elem)
 		c.logger.Info("order deleted", "order_id", orderID)
 		return true
 	}
@@ -135,6 +215,7 @@ func (c *orderLRUCache) reset() {
 	}
 	c.ll.Init()
 	c.cache = make(map[string]*list.Element, cacheCapacity)
+	c.bytes = 0
 }
 
 func (c *orderLRUCache) Clear() {
@@ -145,9 +226,46 @@ func (c *orderLRUCache) Clear() {
 }
 
 func (c *orderLRUCache) Shutdown(ctx context.Context) error {
+	c.stopOnce.Do(func() { close(c.stopSweep) })
+
 	c.RWMutex.Lock()
 	defer c.RWMutex.Unlock()
 	c.reset()
 	c.logger.Info("cache cleared during shutdown")
 	return nil
 }
+
+func sweepLoop(interval time.Duration, stop <-chan struct{}, sweep func() int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sweep удаляет устаревшие записи, которые никто не запрашивал после истечения TTL
+func (c *orderLRUCache) sweep() int {
+	c.RWMutex.Lock()
+	defer c.RWMutex.Unlock()
+
+	removed := 0
+	for elem := c.ll.Back(); elem != nil; {
+		prev := elem.Prev()
+		if c.opts.expired(elem.Value.(*entry).expiresAt) {
+			c.removeElement(elem)
+			removed++
+		}
+		elem = prev
+	}
+
+	if removed > 0 {
+		c.logger.Debug("expired orders swept from cache", "removed", removed, "remaining", c.ll.Len())
+	}
+	return removed
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
 )
@@ -263,3 +264,126 @@ func TestOrderCache_CapacityOne(t *testing.T) {
 		t.Error("Expected order 'b' to exist in cache")
 	}
 }
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestOrderCache_TTLExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	cache := NewOrderLRUCache(&MockLogger{}, 10, WithTTL(time.Minute), withClock(clock.Now))
+	defer cache.Shutdown(context.Background())
+
+	cache.Set("a", entities.Order{OrderUID: "a"})
+	clock.Advance(30 * time.Second)
+	cache.Set("b", entities.Order{OrderUID: "b"})
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected order 'a' to be alive before TTL")
+	}
+
+	clock.Advance(45 * time.Second)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected order 'a' to expire after TTL")
+	}
+	if got := cache.GetAll(10); len(got) != 1 || got[0].OrderUID != "b" {
+		t.Errorf("Expected only order 'b' in GetAll, got %v", got)
+	}
+}
+
+func TestOrderCache_Sweep(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	cache := NewOrderLRUCache(&MockLogger{}, 10, WithTTL(time.Minute), withClock(clock.Now)).(*orderLRUCache)
+	defer cache.Shutdown(context.Background())
+
+	for i := 0; i < 5; i++ {
+		cache.Set(strconv.Itoa(i), entities.Order{OrderUID: strconv.Itoa(i)})
+	}
+	clock.Advance(time.Minute)
+	cache.Set("fresh", entities.Order{OrderUID: "fresh"})
+
+	if removed := cache.sweep(); removed != 5 {
+		t.Errorf("Expected 5 expired orders swept, got %d", removed)
+	}
+	if cache.ll.Len() != 1 || len(cache.cache) != 1 {
+		t.Errorf("Expected 1 order left, got list=%d map=%d", cache.ll.Len(), len(cache.cache))
+	}
+}
+
+func TestOrderCache_MaxBytes(t *testing.T) {
+	small := entities.Order{OrderUID: "small", Items: make([]entities.Item, 1)}
+	large := entities.Order{OrderUID: "large", Items: make([]entities.Item, 100)}
+	budget := estimateOrderSize("large", large) + estimateOrderSize("s0", small)
+
+	cache := NewOrderLRUCache(&MockLogger{}, 0, WithMaxBytes(budget)).(*orderLRUCache)
+
+	for i := 0; i < 10; i++ {
+		cache.Set("s"+strconv.Itoa(i), small)
+	}
+	cache.Set("large", large)
+
+	if cache.bytes > budget {
+		t.Errorf("Expected cache bytes %d to fit budget %d", cache.bytes, budget)
+	}
+	if _, ok := cache.Get("large"); !ok {
+		t.Error("Expected just inserted large order to stay in cache")
+	}
+	if _, ok := cache.Get("s0"); ok {
+		t.Error("Expected oldest small order to be evicted")
+	}
+
+	huge := entities.Order{OrderUID: "huge", Items: make([]entities.Item, 1000)}
+	cache.Set("huge", huge)
+	if _, ok := cache.Get("huge"); ok {
+		t.Error("Expected order larger than the whole budget not to be cached")
+	}
+
+	cache.Delete("large")
+	cache.Clear()
+	if cache.bytes != 0 {
+		t.Errorf("Expected zero bytes after clear, got %d", cache.bytes)
+	}
+}
+
+func TestOrderCache_Stats(t *testing.T) {
+	cache := NewOrderLRUCache(&MockLogger{}, 2)
+	cache.Set("a", entities.Order{OrderUID: "a"})
+	cache.Set("b", entities.Order{OrderUID: "b"})
+	cache.Set("c", entities.Order{OrderUID: "c"})
+	cache.Get("c")
+	cache.Get("b")
+	cache.Get("c")
+	cache.Get("a")
+
+	stats := cache.Stats()
+	if stats.Policy != "lru" || stats.Size != 2 || stats.Capacity != 2 {
+		t.Errorf("unexpected size stats: %+v", stats)
+	}
+	if stats.Hits != 3 || stats.Misses != 1 || stats.Evictions != 1 {
+		t.Errorf("unexpected counters: %+v", stats)
+	}
+	if stats.HitRatio != 0.75 {
+		t.Errorf("expected hit ratio 0.75, got %v", stats.HitRatio)
+	}
+	if stats.Bytes <= 0 {
+		t.Errorf("expected estimated bytes, got %d", stats.Bytes)
+	}
+
+	cache.Clear()
+	if stats := cache.Stats(); stats.Size != 0 || stats.Bytes != 0 || stats.Hits != 3 {
+		t.Errorf("expected Clear to drop entries but keep counters, got %+v", stats)
+	}
+}
@@ -0,0 +1,235 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/order_cache_policy.go
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+type policyEntry struct {
+	value     entities.Order
+	size      int64
+	expiresAt time.Time
+}
+
+// policyOrderCache - кэш с подключаемой политикой вытеснения (LFU, ARC, W-TinyLFU).
+// Get меняет состояние политики, поэтому все операции идут под эксклюзивной блокировкой
+type policyOrderCache struct {
+	sync.Mutex
+	policy  evictionPolicy
+	name    Policy
+	entries map[string]*policyEntry
+	logger  domainrepo.Logger
+
+	opts      options
+	bytes     int64
+	capacity  int
+	counters  counters
+	stopSweep chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewPolicyOrderCache создает кэш с заданной политикой; для PolicyLRU
+// возвращается orderLRUCache
+func NewPolicyOrderCache(l domainrepo.Logger, capacity int, policy Policy, opts ...Option) domainrepo.Cache {
+	if policy == PolicyLRU {
+		return NewOrderLRUCache(l, capacity, opts...)
+	}
+	if capacity <= 0 {
+		l.Info("cache capacity is set to unlimited")
+	}
+
+	c := newPolicyOrderCache(l, capacity, policy, newOptions(opts))
+	if c.opts.ttl > 0 {
+		go sweepLoop(c.opts.sweepInterval, c.stopSweep, c.sweep)
+	}
+	l.Info("cache created", "policy", string(policy), "capacity", capacity)
+	return c
+}
+
+func newPolicyOrderCache(l domainrepo.Logger, capacity int, policy Policy, o options) *policyOrderCache {
+	return &policyOrderCache{
+		policy:    newEvictionPolicy(policy, capacity),
+		name:      policy,
+		entries:   make(map[string]*policyEntry, max(0, capacity)),
+		capacity:  max(0, capacity),
+		logger:    l,
+		opts:      o,
+		stopSweep: make(chan struct{}),
+	}
+}
+
+func (c *policyOrderCache) Set(orderID string, order entities.Order) {
+	c.Lock()
+	defer c.Unlock()
+
+	size := estimateOrderSize(orderID, order)
+	if c.opts.maxBytes > 0 && size > c.opts.maxBytes {
+		if _, exist := c.entries[orderID]; exist {
+			c.removeKey(orderID)
+		}
+		c.logger.Warn("order exceeds cache max_bytes, not cached", "order_id", orderID, "size", size)
+		return
+	}
+
+	if e, exist := c.entries[orderID]; exist {
+		c.bytes += size - e.size
+		e.value = order
+		e.size = size
+		e.expiresAt = c.opts.expiresAt()
+		c.policy.hit(orderID)
+		c.evictBytes(orderID)
+		c.logger.Debug("order updated in cache", "order_id", orderID)
+		return
+	}
+
+	c.entries[orderID] = &policyEntry{value: order, size: size, expiresAt: c.opts.expiresAt()}
+	c.bytes += size
+
+	for _, key := range c.policy.insert(orderID) {
+		c.dropEntry(key)
+		c.counters.evictions++
+		c.logger.Debug("cache exceeded, order evicted", "order_id", key, "policy", string(c.name))
+	}
+	c.evictBytes(orderID)
+}
+
+// evictBytes вытесняет записи по выбору политики, пока кэш не уложится в max_bytes
+func (c *policyOrderCache) evictBytes(keep string) {
+	if c.opts.maxBytes <= 0 {
+		return
+	}
+	for c.bytes > c.opts.maxBytes {
+		key, ok := c.policy.victim()
+		if !ok || key == keep {
+			return
+		}
+		c.removeKey(key)
+		c.counters.evictions++
+		c.logger.Debug("cache max_bytes exceeded, order evicted", "order_id", key, "policy", string(c.name))
+	}
+}
+
+func (c *policyOrderCache) dropEntry(key string) {
+	if e, ok := c.entries[key]; ok {
+		c.bytes -= e.size
+		delete(c.entries, key)
+	}
+}
+
+func (c *policyOrderCache) removeKey(key string) {
+	c.policy.remove(key)
+	c.dropEntry(key)
+}
+
+func (c *policyOrderCache) Get(orderID string) (entities.Order, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, exist := c.entries[orderID]
+	if !exist {
+		c.policy.miss(orderID)
+		c.counters.misses++
+		c.logger.Debug("there is no such order", "order_id", orderID)
+		return entities.Order{}, false
+	}
+
+	if c.opts.expired(e.expiresAt) {
+		c.removeKey(orderID)
+		c.policy.miss(orderID)
+		c.counters.misses++
+		c.logger.Debug("order in cache expired", "order_id", orderID)
+		return entities.Order{}, false
+	}
+
+	c.policy.hit(orderID)
+	c.counters.hits++
+	c.logger.Debug("retrieved order from cache", "order_id", orderID)
+	return e.value, true
+}
+
+func (c *policyOrderCache) GetAll(limit int) []entities.Order {
+	c.Lock()
+	defer c.Unlock()
+
+	if limit <= 0 {
+		return []entities.Order{}
+	}
+
+	orders := make([]entities.Order, 0, min(limit, len(c.entries)))
+	for _, key := range c.policy.keys(len(c.entries)) {
+		if len(orders) == limit {
+			break
+		}
+		e := c.entries[key]
+		if e == nil || c.opts.expired(e.expiresAt) {
+			continue
+		}
+		orders = append(orders, e.value)
+	}
+	return orders
+}
+
+func (c *policyOrderCache) Stats() entities.CacheStats {
+	c.Lock()
+	defer c.Unlock()
+	return c.counters.stats(string(c.name), len(c.entries), c.capacity, c.bytes, c.opts.maxBytes)
+}
+
+func (c *policyOrderCache) Delete(orderID string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, exist := c.entries[orderID]; exist {
+		c.removeKey(orderID)
+		c.logger.Info("order deleted", "order_id", orderID)
+		return true
+	}
+
+	c.logger.Info("order not deleted", "order_id", orderID)
+	return false
+}
+
+func (c *policyOrderCache) reset() {
+	c.policy.reset()
+	c.entries = make(map[string]*policyEntry)
+	c.bytes = 0
+}
+
+func (c *policyOrderCache) Clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.reset()
+	c.logger.Info("cache cleared")
+}
+
+func (c *policyOrderCache) sweep() int {
+	c.Lock()
+	defer c.Unlock()
+
+	removed := 0
+	for key, e := range c.entries {
+		if c.opts.expired(e.expiresAt) {
+			c.removeKey(key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.logger.Debug("expired orders swept from cache", "removed", removed, "remaining", len(c.entries))
+	}
+	return removed
+}
+
+func (c *policyOrderCache) Shutdown(ctx context.Context) error {
+	c.stopOnce.Do(func() { close(c.stopSweep) })
+
+	c.Lock()
+	defer c.Unlock()
+	c.reset()
+	c.logger.Info("cache cleared during shutdown")
+	return nil
+}
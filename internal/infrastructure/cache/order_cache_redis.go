@@ -0,0 +1,401 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/order_cache_redis.go
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+const (
+	defaultRedisKeyPrefix = "orderservice:cache:"
+	defaultRedisTimeout   = 200 * time.Millisecond
+	// GetAll и Clear проходят по многим ключам, им не хватит таймаута одной операции
+	redisBulkTimeout = 30 * time.Second
+	redisScanBatch   = 500
+)
+
+// RedisOptions: Capacity <= 0 и TTL <= 0 снимают соответствующее ограничение;
+// Timeout ограничивает каждую операцию с Redis; с Cipher PII доставки хранятся
+// в Redis зашифрованными, как и в БД
+type RedisOptions struct {
+	KeyPrefix string
+	Capacity  int
+	TTL       time.Duration
+	Timeout   time.Duration
+	Cipher    domainrepo.FieldCipher
+}
+
+// redisOrderCache хранит заказы в Redis в JSON под ключами <prefix>order:<uid>.
+// Порядок использования ведется в sorted set <prefix>index (score - время последнего
+// обращения), размеры записей - в hash <prefix>sizes. Записи, истекшие по TTL,
+// вычищаются из индекса лениво - в GetAll и при вытеснении.
+// Счетчики hits/misses/evictions локальны для экземпляра.
+// Ошибки Redis не пробрасываются: Get считается промахом, запись пропускается
+type redisOrderCache struct {
+	client *redis.Client
+	logger domainrepo.Logger
+	opts   RedisOptions
+	now    func() time.Time
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// getScript читает заказ и отмечает обращение в индексе одним запросом
+var getScript = redis.NewScript(`
+local v = redis.call('GET', KEYS[1])
+if v then
+	redis.call('ZADD', KEYS[2], ARGV[1], ARGV[2])
+end
+return v
+`)
+
+// setScript пишет заказ, обновляет индекс и вытесняет давно не использованные
+// записи сверх емкости; возвращает число вытесненных
+var setScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[4])
+redis.call('HSET', KEYS[3], ARGV[4], string.len(ARGV[1]))
+
+local capacity = tonumber(ARGV[5])
+if capacity <= 0 then
+	return 0
+end
+local over = redis.call('ZCARD', KEYS[2]) - capacity
+if over <= 0 then
+	return 0
+end
+local victims = redis.call('ZRANGE', KEYS[2], 0, over - 1)
+for _, id in ipairs(victims) do
+	redis.call('DEL', ARGV[6] .. id)
+	redis.call('HDEL', KEYS[3], id)
+	redis.call('ZREM', KEYS[2], id)
+end
+return #victims
+`)
+
+func NewRedisOrderCache(client *redis.Client, l domainrepo.Logger, opts RedisOptions) domainrepo.Cache {
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = defaultRedisKeyPrefix
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultRedisTimeout
+	}
+	l.Info("redis cache created",
+		"addr", client.Options().Addr,
+		"key_prefix", opts.KeyPrefix,
+		"capacity", opts.Capacity,
+		"ttl", opts.TTL,
+	)
+	return &redisOrderCache{
+		client: client,
+		logger: l,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+func (c *redisOrderCache) orderKey(orderID string) string {
+	return c.opts.KeyPrefix + "order:" + orderID
+}
+
+func (c *redisOrderCache) indexKey() string {
+	return c.opts.KeyPrefix + "index"
+}
+
+func (c *redisOrderCache) sizesKey() string {
+	return c.opts.KeyPrefix + "sizes"
+}
+
+func (c *redisOrderCache) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.opts.Timeout)
+}
+
+func (c *redisOrderCache) bulkCtx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), max(c.opts.Timeout, redisBulkTimeout))
+}
+
+func (c *redisOrderCache) score() int64 {
+	return c.now().UnixMilli()
+}
+
+// encode сериализует заказ, шифруя PII доставки
+func (c *redisOrderCache) encode(order entities.Order) ([]byte, error) {
+	if c.opts.Cipher != nil {
+		delivery, err := order.Delivery.TransformPII(func(_, value string) (string, error) {
+			return c.opts.Cipher.Encrypt(value)
+		})
+		if err != nil {
+			return nil, err
+		}
+		order.Delivery = delivery
+	}
+	return json.Marshal(order)
+}
+
+func (c *redisOrderCache) decode(data string) (entities.Order, error) {
+	var order entities.Order
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		return entities.Order{}, err
+	}
+	if c.opts.Cipher == nil {
+		return order, nil
+	}
+
+	delivery, err := order.Delivery.TransformPII(func(_, value string) (string, error) {
+		return c.opts.Cipher.Decrypt(value)
+	})
+	if err != nil {
+		return entities.Order{}, err
+	}
+	order.Delivery = delivery
+	return order, nil
+}
+
+func (c *redisOrderCache) Set(orderID string, order entities.Order) {
+	data, err := c.encode(order)
+	if err != nil {
+		c.logger.Error("failed to encode order for redis cache", "order_id", orderID, "error", err)
+		return
+	}
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	evicted, err := setScript.Run(ctx, c.client,
+		[]string{c.orderKey(orderID), c.indexKey(), c.sizesKey()},
+		data, c.opts.TTL.Milliseconds(), c.score(), orderID, c.opts.Capacity, c.opts.KeyPrefix+"order:",
+	).Int()
+	if err != nil {
+		c.logger.Warn("failed to set order in redis cache", "order_id", orderID, "error", err)
+		return
+	}
+	if evicted > 0 {
+		c.evictions.Add(uint64(evicted))
+		c.logger.Debug("redis cache exceeded, least recently used orders evicted", "evicted", evicted)
+	}
+}
+
+func (c *redisOrderCache) Get(orderID string) (entities.Order, bool) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	data, err := getScript.Run(ctx, c.client,
+		[]string{c.orderKey(orderID), c.indexKey()},
+		c.score(), orderID,
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		c.misses.Add(1)
+		c.logger.Debug("there is no such order", "order_id", orderID)
+		return entities.Order{}, false
+	}
+	if err != nil {
+		c.misses.Add(1)
+		c.logger.Warn("failed to get order from redis cache", "order_id", orderID, "error", err)
+		return entities.Order{}, false
+	}
+
+	order, err := c.decode(data)
+	if err != nil {
+		// запись другой версии сервиса, битые данные или ключ шифрования выведен - лучше перечитать из БД
+		c.misses.Add(1)
+		c.logger.Warn("failed to decode order from redis cache", "order_id", orderID, "error", err)
+		c.Delete(orderID)
+		return entities.Order{}, false
+	}
+
+	c.hits.Add(1)
+	c.logger.Debug("retrieved order from cache", "order_id", orderID)
+	return order, true
+}
+
+// GetAll отдает заказы от недавно использованных к давним, попутно убирая из индекса истекшие
+func (c *redisOrderCache) GetAll(limit int) []entities.Order {
+	if limit <= 0 {
+		return []entities.Order{}
+	}
+
+	ctx, cancel := c.bulkCtx()
+	defer cancel()
+
+	orders := make([]entities.Order, 0, min(limit, redisScanBatch))
+	for start := int64(0); len(orders) < limit; {
+		ids, err := c.client.ZRevRange(ctx, c.indexKey(), start, start+int64(limit-len(orders))-1).Result()
+		if err != nil {
+			c.logger.Warn("failed to read redis cache index", "error", err)
+			break
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = c.orderKey(id)
+		}
+		values, err := c.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			c.logger.Warn("failed to read orders from redis cache", "error", err)
+			break
+		}
+
+		var stale []string
+		for i, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				stale = append(stale, ids[i])
+				continue
+			}
+			order, err := c.decode(s)
+			if err != nil {
+				stale = append(stale, ids[i])
+				continue
+			}
+			orders = append(orders, order)
+		}
+
+		// удаленные из индекса записи сдвигают позиции остальных
+		start += int64(len(ids))
+		if len(stale) > 0 && c.dropFromIndex(ctx, stale...) {
+			start -= int64(len(stale))
+		}
+	}
+	return orders
+}
+
+func (c *redisOrderCache) dropFromIndex(ctx context.Context, ids ...string) bool {
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+	_, err := c.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZRem(ctx, c.indexKey(), members...)
+		p.HDel(ctx, c.sizesKey(), ids...)
+		return nil
+	})
+	if err != nil {
+		c.logger.Warn("failed to clean redis cache index", "error", err)
+		return false
+	}
+	return true
+}
+
+func (c *redisOrderCache) Delete(orderID string) bool {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	var del *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		del = p.Del(ctx, c.orderKey(orderID))
+		p.ZRem(ctx, c.indexKey(), orderID)
+		p.HDel(ctx, c.sizesKey(), orderID)
+		return nil
+	})
+	if err != nil {
+		c.logger.Warn("failed to delete order from redis cache", "order_id", orderID, "error", err)
+		return false
+	}
+
+	if del.Val() > 0 {
+		c.logger.Info("order deleted", "order_id", orderID)
+		return true
+	}
+	c.logger.Info("order not deleted", "order_id", orderID)
+	return false
+}
+
+// EvictLocal и ClearLocal ничего не делают: у экземпляра нет собственных копий,
+// а общий кэш уже изменил экземпляр - источник события
+func (c *redisOrderCache) EvictLocal(string) bool { return false }
+
+func (c *redisOrderCache) ClearLocal() {}
+
+// Clear удаляет все ключи с префиксом кэша; заказы других префиксов не трогает
+func (c *redisOrderCache) Clear() {
+	ctx, cancel := c.bulkCtx()
+	defer cancel()
+
+	iter := c.client.Scan(ctx, 0, c.opts.KeyPrefix+"*", redisScanBatch).Iterator()
+	batch := make([]string, 0, redisScanBatch)
+	flush := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		if err := c.client.Del(ctx, batch...).Err(); err != nil {
+			c.logger.Warn("failed to clear redis cache", "error", err)
+			return false
+		}
+		batch = batch[:0]
+		return true
+	}
+
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == redisScanBatch && !flush() {
+			return
+		}
+	}
+	if err := iter.Err(); err != nil {
+		c.logger.Warn("failed to scan redis cache keys", "error", err)
+		return
+	}
+	if flush() {
+		c.logger.Info("cache cleared")
+	}
+}
+
+func (c *redisOrderCache) Stats() entities.CacheStats {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	stats := entities.CacheStats{
+		Policy:    "redis",
+		Capacity:  max(0, c.opts.Capacity),
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+	stats.HitRatio = entities.HitRatio(stats.Hits, stats.Misses)
+
+	size, err := c.client.ZCard(ctx, c.indexKey()).Result()
+	if err != nil {
+		c.logger.Warn("failed to read redis cache size", "error", err)
+		return stats
+	}
+	stats.Size = int(size)
+
+	sizes, err := c.client.HVals(ctx, c.sizesKey()).Result()
+	if err != nil {
+		c.logger.Warn("failed to read redis cache sizes", "error", err)
+		return stats
+	}
+	for _, s := range sizes {
+		n, _ := strconv.ParseInt(s, 10, 64)
+		stats.Bytes += n
+	}
+	return stats
+}
+
+// Shutdown закрывает соединение, но данные в Redis оставляет - ради них он и нужен
+func (c *redisOrderCache) Shutdown(ctx context.Context) error {
+	if err := c.client.Close(); err != nil {
+		return err
+	}
+	c.logger.Info("redis cache connection closed")
+	return nil
+}
@@ -0,0 +1,264 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/order_cache_redis_test.go
+package cache
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// newTestRedisCache поднимает miniredis в процессе; часы кэша сдвигаются вручную,
+// чтобы порядок обращений не зависел от разрешения системного таймера
+func newTestRedisCache(t *testing.T, opts RedisOptions) (*redisOrderCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewRedisOrderCache(client, &MockLogger{}, opts).(*redisOrderCache)
+	c.now = func() time.Time {
+		clock.Advance(time.Millisecond)
+		return clock.Now()
+	}
+	t.Cleanup(func() { client.Close() })
+	return c, mr
+}
+
+func TestRedisCache_RoundTrip(t *testing.T) {
+	c, _ := newTestRedisCache(t, RedisOptions{})
+
+	order := snapshotOrder("a")
+	c.Set(order.OrderUID, order)
+
+	got, ok := c.Get("a")
+	if !ok {
+		t.Fatal("expected order in redis cache")
+	}
+	if !reflect.DeepEqual(got, order) {
+		t.Errorf("order changed after round trip:\nwant %+v\ngot  %+v", order, got)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("expected miss for unknown order")
+	}
+}
+
+func TestRedisCache_EncryptsPII(t *testing.T) {
+	c, mr := newTestRedisCache(t, RedisOptions{Cipher: testCipher{}})
+
+	order := snapshotOrder("a")
+	c.Set(order.OrderUID, order)
+
+	raw, err := mr.Get(c.orderKey("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pii := range []string{"John Doe", "+123456", "test@example.com"} {
+		if strings.Contains(raw, pii) {
+			t.Errorf("redis value contains plaintext %q", pii)
+		}
+	}
+
+	got, ok := c.Get("a")
+	if !ok || !reflect.DeepEqual(got, order) {
+		t.Errorf("expected decrypted order, got %+v", got)
+	}
+	if all := c.GetAll(10); len(all) != 1 || !reflect.DeepEqual(all[0], order) {
+		t.Errorf("expected decrypted order from GetAll, got %+v", all)
+	}
+}
+
+func TestRedisCache_TTL(t *testing.T) {
+	c, mr := newTestRedisCache(t, RedisOptions{TTL: time.Minute})
+
+	c.Set("a", snapshotOrder("a"))
+	if ttl := mr.TTL(c.orderKey("a")); ttl != time.Minute {
+		t.Errorf("expected key ttl 1m, got %v", ttl)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected order to expire")
+	}
+	if got := c.GetAll(10); len(got) != 0 {
+		t.Errorf("expected no orders after expiry, got %d", len(got))
+	}
+	if stats := c.Stats(); stats.Size != 0 || stats.Bytes != 0 {
+		t.Errorf("expected expired order to be dropped from index, got %+v", stats)
+	}
+}
+
+func TestRedisCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestRedisCache(t, RedisOptions{Capacity: 2})
+
+	c.Set("a", snapshotOrder("a"))
+	c.Set("b", snapshotOrder("b"))
+	c.Get("a")
+	c.Set("c", snapshotOrder("c"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected least recently used order to be evicted")
+	}
+	ids := []string{}
+	for _, o := range c.GetAll(10) {
+		ids = append(ids, o.OrderUID)
+	}
+	if want := []string{"c", "a"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected GetAll order %v, got %v", want, ids)
+	}
+
+	stats := c.Stats()
+	if stats.Policy != "redis" || stats.Size != 2 || stats.Capacity != 2 || stats.Evictions != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Bytes <= 0 {
+		t.Errorf("unexpected counters: %+v", stats)
+	}
+}
+
+func TestRedisCache_DeleteAndClear(t *testing.T) {
+	c, mr := newTestRedisCache(t, RedisOptions{KeyPrefix: "test:"})
+	mr.Set("other:key", "keep")
+
+	c.Set("a", snapshotOrder("a"))
+	c.Set("b", snapshotOrder("b"))
+
+	if !c.Delete("a") {
+		t.Error("expected order to be deleted")
+	}
+	if c.Delete("a") {
+		t.Error("expected second delete to report missing order")
+	}
+
+	c.Clear()
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected cache to be cleared")
+	}
+	if v, err := mr.Get("other:key"); err != nil || v != "keep" {
+		t.Errorf("expected keys outside prefix to survive Clear, got %q, %v", v, err)
+	}
+}
+
+func TestRedisCache_UnavailableIsMiss(t *testing.T) {
+	c, mr := newTestRedisCache(t, RedisOptions{Timeout: 50 * time.Millisecond})
+	c.Set("a", snapshotOrder("a"))
+	mr.Close()
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected miss when redis is unavailable")
+	}
+	c.Set("b", snapshotOrder("b"))
+	if got := c.GetAll(10); len(got) != 0 {
+		t.Errorf("expected empty GetAll, got %d", len(got))
+	}
+}
+
+func TestTieredCache_ReadsThroughToRedis(t *testing.T) {
+	remote, _ := newTestRedisCache(t, RedisOptions{})
+
+	// два экземпляра сервиса с собственными LRU и общим Redis
+	first := NewTieredOrderCache(NewOrderLRUCache(&MockLogger{}, 10), remote, &MockLogger{})
+	secondLocal := NewOrderLRUCache(&MockLogger{}, 10)
+	second := NewTieredOrderCache(secondLocal, remote, &MockLogger{})
+
+	order := snapshotOrder("a")
+	first.Set("a", order)
+
+	got, ok := second.Get("a")
+	if !ok || !reflect.DeepEqual(got, order) {
+		t.Fatalf("expected order from shared redis, got %v %+v", ok, got)
+	}
+	if _, ok := secondLocal.Get("a"); !ok {
+		t.Error("expected redis hit to populate local tier")
+	}
+
+	if !second.Delete("a") {
+		t.Error("expected delete to report removed order")
+	}
+	if _, ok := remote.Get("a"); ok {
+		t.Error("expected delete to reach redis")
+	}
+
+	stats := second.Stats()
+	if stats.Policy != "lru+redis" {
+		t.Errorf("unexpected tiered policy %q", stats.Policy)
+	}
+	if stats.Hits != 2 {
+		t.Errorf("expected hits from both tiers, got %+v", stats)
+	}
+}
+
+// slowRemote выполняет afterGet между ответом Redis и возвратом из Get,
+// имитируя запись, которая обгоняет дочитывание
+type slowRemote struct {
+	domainrepo.Cache
+	afterGet func()
+}
+
+func (r *slowRemote) Get(orderID string) (entities.Order, bool) {
+	order, ok := r.Cache.Get(orderID)
+	if r.afterGet != nil {
+		r.afterGet()
+	}
+	return order, ok
+}
+
+func TestTieredCache_WriteCancelsOvertakenFill(t *testing.T) {
+	redisCache, _ := newTestRedisCache(t, RedisOptions{})
+	remote := &slowRemote{Cache: redisCache}
+	local := NewOrderLRUCache(&MockLogger{}, 10)
+	c := NewTieredOrderCache(local, remote, &MockLogger{})
+
+	stale := snapshotOrder("a")
+	redisCache.Set("a", stale)
+
+	fresh := snapshotOrder("a")
+	fresh.TrackNumber = "FRESH"
+	remote.afterGet = func() {
+		remote.afterGet = nil
+		c.Set("a", fresh)
+	}
+
+	if got, ok := c.Get("a"); !ok || got.TrackNumber != stale.TrackNumber {
+		t.Fatalf("expected stale order from the overtaken read, got %v %+v", ok, got)
+	}
+	if _, ok := local.Get("a"); ok {
+		t.Error("expected overtaken fill to stay out of the local tier")
+	}
+
+	got, ok := c.Get("a")
+	if !ok || got.TrackNumber != "FRESH" {
+		t.Errorf("expected fresh order after the write, got %v %+v", ok, got)
+	}
+}
+
+func TestTieredCache_LocalEvictionKeepsRedis(t *testing.T) {
+	remote, _ := newTestRedisCache(t, RedisOptions{})
+	local := NewOrderLRUCache(&MockLogger{}, 10)
+	c := NewTieredOrderCache(local, remote, &MockLogger{}).(*tieredOrderCache)
+
+	c.Set("a", snapshotOrder("a"))
+	c.Set("b", snapshotOrder("b"))
+	c.Get("a")
+	c.Get("b")
+
+	if !c.EvictLocal("a") {
+		t.Error("expected local copy to be evicted")
+	}
+	c.ClearLocal()
+
+	if _, ok := local.Get("b"); ok {
+		t.Error("expected local tier to be cleared")
+	}
+	for _, id := range []string{"a", "b"} {
+		if _, ok := remote.Get(id); !ok {
+			t.Errorf("expected order %s to stay in redis", id)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/order_cache_sharded.go
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// segment - сегмент шардированного кэша; фоновую очистку запускает владелец
+type segment interface {
+	domainrepo.Cache
+	sweep() int
+}
+
+// shardedOrderCache делит ключи между независимыми сегментами по хэшу order_uid,
+// чтобы читатели разных заказов не ждали друг друга на одном мьютексе.
+// Вытеснение идет внутри сегмента, поэтому порядок вытеснения приблизительный
+type shardedOrderCache struct {
+	shards []segment
+	mask   uint32
+	policy Policy
+	logger domainrepo.Logger
+
+	stopSweep chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewShardedOrderCache: shards округляется вверх до степени двойки;
+// capacity и max_bytes делятся между сегментами поровну, policy действует в каждом сегменте
+func NewShardedOrderCache(l domainrepo.Logger, capacity, shards int, policy Policy, opts ...Option) domainrepo.Cache {
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+
+	o := newOptions(opts)
+	shardCapacity := 0
+	if capacity > 0 {
+		shardCapacity = (capacity + n - 1) / n
+	} else {
+		l.Info("cache capacity is set to unlimited")
+	}
+	if o.maxBytes > 0 {
+		o.maxBytes = (o.maxBytes + int64(n) - 1) / int64(n)
+	}
+
+	c := &shardedOrderCache{
+		shards:    make([]segment, n),
+		mask:      uint32(n - 1),
+		policy:    policy,
+		logger:    l,
+		stopSweep: make(chan struct{}),
+	}
+	for i := range c.shards {
+		if policy == PolicyLRU {
+			c.shards[i] = newOrderLRUCache(l, shardCapacity, o)
+		} else {
+			c.shards[i] = newPolicyOrderCache(l, shardCapacity, policy, o)
+		}
+	}
+
+	if o.ttl > 0 {
+		go sweepLoop(o.sweepInterval, c.stopSweep, c.sweep)
+	}
+
+	l.Info("sharded cache created", "shards", n, "shard_capacity", shardCapacity, "policy", string(policy))
+	return c
+}
+
+// fnv-1a без аллокаций
+func shardHash(key string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return h
+}
+
+func (c *shardedOrderCache) shard(key string) segment {
+	return c.shards[shardHash(key)&c.mask]
+}
+
+func (c *shardedOrderCache) Get(orderID string) (entities.Order, bool) {
+	return c.shard(orderID).Get(orderID)
+}
+
+func (c *shardedOrderCache) Set(orderID string, order entities.Order) {
+	c.shard(orderID).Set(orderID, order)
+}
+
+func (c *shardedOrderCache) Delete(orderID string) bool {
+	return c.shard(orderID).Delete(orderID)
+}
+
+// GetAll берет самые свежие записи каждого сегмента по очереди: глобального
+// LRU-порядка нет, но недавно использованные заказы все равно идут первыми
+func (c *shardedOrderCache) GetAll(limit int) []entities.Order {
+	if limit <= 0 {
+		return []entities.Order{}
+	}
+
+	perShard := make([][]entities.Order, len(c.shards))
+	total := 0
+	for i, s := range c.shards {
+		perShard[i] = s.GetAll(limit)
+		total += len(perShard[i])
+	}
+
+	orders := make([]entities.Order, 0, min(limit, total))
+	for pos := 0; len(orders) < limit && len(orders) < total; pos++ {
+		for _, part := range perShard {
+			if pos < len(part) && len(orders) < limit {
+				orders = append(orders, part[pos])
+			}
+		}
+	}
+	return orders
+}
+
+// Stats суммирует счетчики сегментов
+func (c *shardedOrderCache) Stats() entities.CacheStats {
+	stats := entities.CacheStats{Policy: string(c.policy)}
+	for _, s := range c.shards {
+		stats = stats.Add(s.Stats())
+	}
+	return stats
+}
+
+func (c *shardedOrderCache) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+func (c *shardedOrderCache) sweep() int {
+	removed := 0
+	for _, s := range c.shards {
+		removed += s.sweep()
+	}
+	return removed
+}
+
+func (c *shardedOrderCache) Shutdown(ctx context.Context) error {
+	c.stopOnce.Do(func() { close(c.stopSweep) })
+	for _, s := range c.shards {
+		if err := s.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/order_cache_sharded_test.go
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+func TestShardedCache_SetGetDelete(t *testing.T) {
+	cache := NewShardedOrderCache(&MockLogger{}, 100, 8, PolicyLRU)
+
+	for i := 0; i < 50; i++ {
+		id := "order" + strconv.Itoa(i)
+		cache.Set(id, entities.Order{OrderUID: id})
+	}
+	for i := 0; i < 50; i++ {
+		id := "order" + strconv.Itoa(i)
+		got, ok := cache.Get(id)
+		if !ok || got.OrderUID != id {
+			t.Fatalf("Expected order %s in cache, got %v (found=%v)", id, got, ok)
+		}
+	}
+
+	if !cache.Delete("order7") {
+		t.Error("Expected order7 to be deleted")
+	}
+	if _, ok := cache.Get("order7"); ok {
+		t.Error("Expected deleted order to be missing")
+	}
+
+	cache.Clear()
+	if got := cache.GetAll(100); len(got) != 0 {
+		t.Errorf("Expected empty cache after clear, got %d orders", len(got))
+	}
+}
+
+func TestShardedCache_ShardCount(t *testing.T) {
+	cache := NewShardedOrderCache(&MockLogger{}, 10, 5, PolicyLRU).(*shardedOrderCache)
+	if len(cache.shards) != 8 {
+		t.Errorf("Expected shards rounded up to 8, got %d", len(cache.shards))
+	}
+	if cache.shards[0].(*orderLRUCache).capacity != 2 {
+		t.Errorf("Expected shard capacity 2, got %d", cache.shards[0].(*orderLRUCache).capacity)
+	}
+}
+
+func TestShardedCache_GetAllLimit(t *testing.T) {
+	cache := NewShardedOrderCache(&MockLogger{}, 0, 4, PolicyLRU)
+	for i := 0; i < 20; i++ {
+		id := strconv.Itoa(i)
+		cache.Set(id, entities.Order{OrderUID: id})
+	}
+
+	if got := cache.GetAll(7); len(got) != 7 {
+		t.Errorf("Expected 7 orders, got %d", len(got))
+	}
+
+	seen := make(map[string]bool)
+	for _, o := range cache.GetAll(100) {
+		if seen[o.OrderUID] {
+			t.Errorf("Duplicate order %s in GetAll", o.OrderUID)
+		}
+		seen[o.OrderUID] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("Expected 20 distinct orders, got %d", len(seen))
+	}
+}
+
+func TestShardedCache_ParallelAccess(t *testing.T) {
+	cache := NewShardedOrderCache(&MockLogger{}, 1000, 16, PolicyLRU)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := strconv.Itoa(i)
+			cache.Set(id, entities.Order{OrderUID: id})
+			cache.Get(id)
+			cache.GetAll(10)
+		}(i)
+	}
+	wg.Wait()
+
+	if err := cache.Shutdown(context.Background()); err != nil {
+		t.Errorf("Unexpected shutdown error: %v", err)
+	}
+}
+
+func TestShardedCache_StatsAggregatesShards(t *testing.T) {
+	cache := NewShardedOrderCache(&MockLogger{}, 64, 4, PolicyLFU, WithMaxBytes(1<<20))
+	for i := 0; i < 20; i++ {
+		id := strconv.Itoa(i)
+		cache.Set(id, entities.Order{OrderUID: id})
+		cache.Get(id)
+	}
+	cache.Get("missing")
+
+	stats := cache.Stats()
+	if stats.Policy != "lfu" || stats.Size != 20 || stats.Capacity != 64 || stats.MaxBytes != 1<<20 {
+		t.Errorf("unexpected size stats: %+v", stats)
+	}
+	if stats.Hits != 20 || stats.Misses != 1 {
+		t.Errorf("unexpected counters: %+v", stats)
+	}
+	if want := 20.0 / 21.0; stats.HitRatio != want {
+		t.Errorf("expected hit ratio %v, got %v", want, stats.HitRatio)
+	}
+}
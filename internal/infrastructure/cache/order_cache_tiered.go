@@ -0,0 +1,134 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/order_cache_tiered.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// tieredStripes - число полос версий; запись отменяет дочитывание только ключей своей полосы
+const tieredStripes = 256
+
+// tieredStripe: version растет при каждой записи ключа полосы. Замок держится только
+// на время операций с local, запросы к Redis идут без него
+type tieredStripe struct {
+	mu      sync.Mutex
+	version uint64
+}
+
+// tieredOrderCache - локальный кэш перед общим (Redis): чтение сначала идет в local,
+// промах дочитывается из remote и оседает в local. Запись идет в remote, а локальная копия
+// сбрасывается и дочитывается при следующем чтении: порядок параллельных записей знает
+// только Redis, и local не может оставить у себя версию, которую remote уже перезаписал.
+// Изменения с других экземпляров local сам не видит - для этого нужна
+// cache.invalidation или короткий TTL локального уровня
+type tieredOrderCache struct {
+	local  domainrepo.Cache
+	remote domainrepo.Cache
+	logger domainrepo.Logger
+
+	stripes [tieredStripes]tieredStripe
+}
+
+func NewTieredOrderCache(local, remote domainrepo.Cache, l domainrepo.Logger) domainrepo.Cache {
+	return &tieredOrderCache{
+		local:  local,
+		remote: remote,
+		logger: l,
+	}
+}
+
+func (c *tieredOrderCache) stripe(orderID string) *tieredStripe {
+	return &c.stripes[shardHash(orderID)%tieredStripes]
+}
+
+// Get: ответ Redis оседает в local, только если за время запроса ключ полосы не менялся,
+// иначе дочитывание могло бы вернуть в local версию, которую уже заменила запись
+func (c *tieredOrderCache) Get(orderID string) (entities.Order, bool) {
+	if order, ok := c.local.Get(orderID); ok {
+		return order, true
+	}
+
+	s := c.stripe(orderID)
+	s.mu.Lock()
+	version := s.version
+	s.mu.Unlock()
+
+	order, ok := c.remote.Get(orderID)
+	if ok {
+		s.mu.Lock()
+		if s.version == version {
+			c.local.Set(orderID, order)
+		}
+		s.mu.Unlock()
+	}
+	return order, ok
+}
+
+func (c *tieredOrderCache) Set(orderID string, order entities.Order) {
+	c.remote.Set(orderID, order)
+	c.EvictLocal(orderID)
+}
+
+func (c *tieredOrderCache) Delete(orderID string) bool {
+	remote := c.remote.Delete(orderID)
+	local := c.EvictLocal(orderID)
+	return remote || local
+}
+
+func (c *tieredOrderCache) Clear() {
+	c.remote.Clear()
+	c.ClearLocal()
+}
+
+// EvictLocal сбрасывает только локальную копию: Redis не трогается
+func (c *tieredOrderCache) EvictLocal(orderID string) bool {
+	s := c.stripe(orderID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version++
+	return c.local.Delete(orderID)
+}
+
+// ClearLocal очищает только локальный уровень; берет все полосы, чтобы ни одно
+// начатое дочитывание не пережило очистку
+func (c *tieredOrderCache) ClearLocal() {
+	for i := range c.stripes {
+		c.stripes[i].mu.Lock()
+		c.stripes[i].version++
+	}
+	c.local.Clear()
+	for i := range c.stripes {
+		c.stripes[i].mu.Unlock()
+	}
+}
+
+// GetAll читает из общего уровня: в нем заказы всех экземпляров.
+// Если Redis недоступен или пуст, отдается содержимое локального уровня
+func (c *tieredOrderCache) GetAll(limit int) []entities.Order {
+	if orders := c.remote.GetAll(limit); len(orders) > 0 {
+		return orders
+	}
+	return c.local.GetAll(limit)
+}
+
+// Stats: размер, емкость и вытеснения - общего уровня; попадания - сумма по уровням,
+// промахи - только общего уровня, так как промах local с попаданием в remote промахом не считается
+func (c *tieredOrderCache) Stats() entities.CacheStats {
+	local := c.local.Stats()
+	stats := c.remote.Stats()
+
+	stats.Policy = local.Policy + "+" + stats.Policy
+	stats.Hits += local.Hits
+	stats.HitRatio = entities.HitRatio(stats.Hits, stats.Misses)
+	return stats
+}
+
+func (c *tieredOrderCache) Shutdown(ctx context.Context) error {
+	return errors.Join(c.local.Shutdown(ctx), c.remote.Shutdown(ctx))
+}
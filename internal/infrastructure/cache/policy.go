@@ -0,0 +1,59 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/policy.go
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Policy string
+
+const (
+	PolicyLRU     Policy = "lru"
+	PolicyLFU     Policy = "lfu"
+	PolicyARC     Policy = "arc"
+	PolicyTinyLFU Policy = "w-tinylfu"
+	defaultPolicy        = PolicyLRU
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return defaultPolicy, nil
+	case PolicyLRU, PolicyLFU, PolicyARC, PolicyTinyLFU:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown cache policy %q", s)
+	}
+}
+
+// evictionPolicy хранит только ключи и решает, кого вытеснять;
+// значения, TTL и бюджет по байтам держит policyCache
+type evictionPolicy interface {
+	// hit - обращение к ключу, который есть в кэше
+	hit(key string)
+	// miss - обращение к отсутствующему ключу; нужно политикам с историей
+	miss(key string)
+	// insert добавляет ключ и возвращает вытесненные ключи; вытесненным
+	// может оказаться и сам key, если политика не пустила его в кэш
+	insert(key string) []string
+	remove(key string)
+	// victim выбирает ключ для вытеснения при превышении бюджета по байтам
+	victim() (string, bool)
+	// keys - до limit ключей, самые ценные первыми
+	keys(limit int) []string
+	reset()
+}
+
+func newEvictionPolicy(p Policy, capacity int) evictionPolicy {
+	switch p {
+	case PolicyLFU:
+		return newLFUPolicy(capacity)
+	case PolicyARC:
+		return newARCPolicy(capacity)
+	case PolicyTinyLFU:
+		return newTinyLFUPolicy(capacity)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/policy_arc.go
+package cache
+
+import "container/list"
+
+const (
+	arcT1 = iota // в кэше, одно обращение
+	arcT2        // в кэше, больше одного обращения
+	arcB1        // призраки вытесненных из T1
+	arcB2        // призраки вытесненных из T2
+)
+
+type arcNode struct {
+	key  string
+	list int
+}
+
+// arcPolicy - Adaptive Replacement Cache (Megiddo, Modha). Ключи, к которым
+// обращались один раз (например, при массовой загрузке), живут в T1 и не
+// вытесняют из T2 часто читаемые заказы; размер T1 (p) подстраивается по
+// попаданиям в списки призраков B1/B2
+type arcPolicy struct {
+	capacity int
+	p        int
+	lists    [4]*list.List
+	nodes    map[string]*list.Element
+}
+
+func newARCPolicy(capacity int) *arcPolicy {
+	a := &arcPolicy{capacity: capacity}
+	for i := range a.lists {
+		a.lists[i] = list.New()
+	}
+	a.reset()
+	return a
+}
+
+func (a *arcPolicy) reset() {
+	for _, l := range a.lists {
+		l.Init()
+	}
+	a.nodes = make(map[string]*list.Element, max(0, 2*a.capacity))
+	a.p = 0
+}
+
+func (a *arcPolicy) len(i int) int {
+	return a.lists[i].Len()
+}
+
+func (a *arcPolicy) moveTo(elem *list.Element, to int) {
+	n := elem.Value.(*arcNode)
+	a.lists[n.list].Remove(elem)
+	n.list = to
+	a.nodes[n.key] = a.lists[to].PushFront(n)
+}
+
+func (a *arcPolicy) dropLRU(i int) {
+	if elem := a.lists[i].Back(); elem != nil {
+		a.lists[i].Remove(elem)
+		delete(a.nodes, elem.Value.(*arcNode).key)
+	}
+}
+
+// replace переносит LRU-ключ из T1 или T2 в соответствующий список призраков
+func (a *arcPolicy) replace(inB2 bool) (string, bool) {
+	if a.len(arcT1)+a.len(arcT2) < a.capacity {
+		return "", false
+	}
+	from, to := arcT2, arcB2
+	if t1 := a.len(arcT1); t1 > 0 && (t1 > a.p || (inB2 && t1 == a.p) || a.len(arcT2) == 0) {
+		from, to = arcT1, arcB1
+	}
+	elem := a.lists[from].Back()
+	if elem == nil {
+		return "", false
+	}
+	key := elem.Value.(*arcNode).key
+	a.moveTo(elem, to)
+	return key, true
+}
+
+func (a *arcPolicy) hit(key string) {
+	if elem, ok := a.nodes[key]; ok {
+		if n := elem.Value.(*arcNode); n.list == arcT1 || n.list == arcT2 {
+			a.moveTo(elem, arcT2)
+		}
+	}
+}
+
+func (a *arcPolicy) miss(string) {}
+
+func (a *arcPolicy) insert(key string) []string {
+	if a.capacity <= 0 {
+		if _, ok := a.nodes[key]; !ok {
+			a.nodes[key] = a.lists[arcT1].PushFront(&arcNode{key: key, list: arcT1})
+		}
+		return nil
+	}
+
+	var evicted []string
+	collect := func(k string, ok bool) {
+		if ok {
+			evicted = append(evicted, k)
+		}
+	}
+
+	if elem, ok := a.nodes[key]; ok {
+		switch elem.Value.(*arcNode).list {
+		case arcT1, arcT2:
+			a.moveTo(elem, arcT2)
+			return nil
+		case arcB1:
+			a.p = min(a.capacity, a.p+max(a.len(arcB2)/max(a.len(arcB1), 1), 1))
+			collect(a.replace(false))
+		case arcB2:
+			a.p = max(0, a.p-max(a.len(arcB1)/max(a.len(arcB2), 1), 1))
+			collect(a.replace(true))
+		}
+		a.moveTo(elem, arcT2)
+		return evicted
+	}
+
+	l1 := a.len(arcT1) + a.len(arcB1)
+	total := l1 + a.len(arcT2) + a.len(arcB2)
+	switch {
+	case l1 >= a.capacity:
+		if a.len(arcT1) < a.capacity {
+			a.dropLRU(arcB1)
+			collect(a.replace(false))
+		} else if elem := a.lists[arcT1].Back(); elem != nil {
+			k := elem.Value.(*arcNode).key
+			a.dropLRU(arcT1)
+			evicted = append(evicted, k)
+		}
+	case total >= a.capacity:
+		if total >= 2*a.capacity {
+			a.dropLRU(arcB2)
+		}
+		collect(a.replace(false))
+	}
+
+	a.nodes[key] = a.lists[arcT1].PushFront(&arcNode{key: key, list: arcT1})
+	return evicted
+}
+
+func (a *arcPolicy) remove(key string) {
+	if elem, ok := a.nodes[key]; ok {
+		a.lists[elem.Value.(*arcNode).list].Remove(elem)
+		delete(a.nodes, key)
+	}
+}
+
+func (a *arcPolicy) victim() (string, bool) {
+	from := arcT2
+	if t1 := a.len(arcT1); t1 > 0 && (t1 > a.p || a.len(arcT2) == 0) {
+		from = arcT1
+	}
+	if elem := a.lists[from].Back(); elem != nil {
+		return elem.Value.(*arcNode).key, true
+	}
+	return "", false
+}
+
+func (a *arcPolicy) keys(limit int) []string {
+	keys := make([]string, 0, min(limit, a.len(arcT1)+a.len(arcT2)))
+	for _, i := range []int{arcT2, arcT1} {
+		for elem := a.lists[i].Front(); elem != nil && len(keys) < limit; elem = elem.Next() {
+			keys = append(keys, elem.Value.(*arcNode).key)
+		}
+	}
+	return keys
+}
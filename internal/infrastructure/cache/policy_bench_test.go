@@ -0,0 +1,85 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/policy_bench_test.go
+package cache
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+type traceOp struct {
+	write bool
+	key   string
+}
+
+// трасса синтетическая: ее генерирует scripts/cachetrace по модели нагрузки (Zipf-чтения,
+// поток новых заказов, массовые выгрузки), а не записывает с работающего сервиса, поэтому
+// hit ratio показывает относительное поведение политик, а не ожидаемое в продакшене
+func loadTrace(tb testing.TB) []traceOp {
+	f, err := os.Open("testdata/synthetic_access_trace.txt.gz")
+	if err != nil {
+		tb.Fatalf("failed to open trace: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		tb.Fatalf("failed to read trace: %v", err)
+	}
+
+	var ops []traceOp
+	sc := bufio.NewScanner(gz)
+	for sc.Scan() {
+		op, key, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			tb.Fatalf("invalid trace line %q", sc.Text())
+		}
+		ops = append(ops, traceOp{write: op == "S", key: key})
+	}
+	if err := sc.Err(); err != nil {
+		tb.Fatalf("failed to read trace: %v", err)
+	}
+	return ops
+}
+
+// replayTrace воспроизводит трассу так же, как ее видит сервис: промах при чтении
+// догружает заказ в кэш. Возвращает долю попаданий среди чтений
+func replayTrace(policy Policy, capacity int, trace []traceOp) float64 {
+	cache := NewPolicyOrderCache(&MockLogger{}, capacity, policy)
+	var reads, hits int
+	for _, op := range trace {
+		if op.write {
+			cache.Set(op.key, entities.Order{OrderUID: op.key})
+			continue
+		}
+		reads++
+		if _, ok := cache.Get(op.key); ok {
+			hits++
+		} else {
+			cache.Set(op.key, entities.Order{OrderUID: op.key})
+		}
+	}
+	return float64(hits) / float64(reads)
+}
+
+func BenchmarkPolicy_HitRatioSyntheticTrace(b *testing.B) {
+	trace := loadTrace(b)
+
+	for _, capacity := range []int{500, 2000} {
+		for _, policy := range []Policy{PolicyLRU, PolicyLFU, PolicyARC, PolicyTinyLFU} {
+			b.Run(string(policy)+"/capacity-"+strconv.Itoa(capacity), func(b *testing.B) {
+				var ratio float64
+				for i := 0; i < b.N; i++ {
+					ratio = replayTrace(policy, capacity, trace)
+				}
+				b.ReportMetric(100*ratio, "hit%")
+				b.ReportMetric(float64(len(trace)), "ops/trace")
+			})
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/policy_lfu.go
+package cache
+
+import (
+	"container/list"
+	"sort"
+)
+
+type lfuNode struct {
+	key  string
+	freq int
+}
+
+// lfuPolicy - O(1) LFU: ключи разложены по спискам частот, внутри частоты
+// вытесняется самый давний. Ключ без обращений после вставки имеет частоту 1
+type lfuPolicy struct {
+	capacity int
+	nodes    map[string]*list.Element
+	freqs    map[int]*list.List
+	minFreq  int
+}
+
+func newLFUPolicy(capacity int) *lfuPolicy {
+	p := &lfuPolicy{capacity: capacity}
+	p.reset()
+	return p
+}
+
+func (p *lfuPolicy) reset() {
+	p.nodes = make(map[string]*list.Element, max(0, p.capacity))
+	p.freqs = make(map[int]*list.List)
+	p.minFreq = 0
+}
+
+func (p *lfuPolicy) bucket(freq int) *list.List {
+	l, ok := p.freqs[freq]
+	if !ok {
+		l = list.New()
+		p.freqs[freq] = l
+	}
+	return l
+}
+
+func (p *lfuPolicy) unlink(elem *list.Element) *lfuNode {
+	n := elem.Value.(*lfuNode)
+	l := p.freqs[n.freq]
+	l.Remove(elem)
+	if l.Len() == 0 {
+		delete(p.freqs, n.freq)
+		if p.minFreq == n.freq {
+			p.minFreq++
+		}
+	}
+	return n
+}
+
+func (p *lfuPolicy) hit(key string) {
+	elem, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	n := p.unlink(elem)
+	n.freq++
+	p.nodes[key] = p.bucket(n.freq).PushFront(n)
+}
+
+func (p *lfuPolicy) miss(string) {}
+
+func (p *lfuPolicy) insert(key string) []string {
+	if _, ok := p.nodes[key]; ok {
+		p.hit(key)
+		return nil
+	}
+
+	var evicted []string
+	if p.capacity > 0 && len(p.nodes) >= p.capacity {
+		if victim, ok := p.victim(); ok {
+			p.remove(victim)
+			evicted = append(evicted, victim)
+		}
+	}
+
+	p.nodes[key] = p.bucket(1).PushFront(&lfuNode{key: key, freq: 1})
+	p.minFreq = 1
+	return evicted
+}
+
+func (p *lfuPolicy) remove(key string) {
+	elem, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	p.unlink(elem)
+	delete(p.nodes, key)
+	if len(p.nodes) == 0 {
+		p.minFreq = 0
+	}
+}
+
+func (p *lfuPolicy) victim() (string, bool) {
+	if len(p.nodes) == 0 {
+		return "", false
+	}
+	// после remove minFreq может указывать на пустую частоту - пересчитываем
+	if _, ok := p.freqs[p.minFreq]; !ok {
+		p.minFreq = 0
+		for f := range p.freqs {
+			if p.minFreq == 0 || f < p.minFreq {
+				p.minFreq = f
+			}
+		}
+	}
+	return p.freqs[p.minFreq].Back().Value.(*lfuNode).key, true
+}
+
+func (p *lfuPolicy) keys(limit int) []string {
+	freqs := make([]int, 0, len(p.freqs))
+	for f := range p.freqs {
+		freqs = append(freqs, f)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(freqs)))
+
+	keys := make([]string, 0, min(limit, len(p.nodes)))
+	for _, f := range freqs {
+		for elem := p.freqs[f].Front(); elem != nil && len(keys) < limit; elem = elem.Next() {
+			keys = append(keys, elem.Value.(*lfuNode).key)
+		}
+	}
+	return keys
+}
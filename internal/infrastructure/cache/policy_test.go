@@ -0,0 +1,143 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/policy_test.go
+package cache
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+var evictionPolicies = []Policy{PolicyLFU, PolicyARC, PolicyTinyLFU}
+
+func TestParsePolicy(t *testing.T) {
+	for in, want := range map[string]Policy{"": PolicyLRU, "LFU": PolicyLFU, " arc ": PolicyARC, "w-tinylfu": PolicyTinyLFU} {
+		got, err := ParsePolicy(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePolicy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParsePolicy("fifo"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
+
+func TestPolicyCache_Basic(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(string(policy), func(t *testing.T) {
+			cache := NewPolicyOrderCache(&MockLogger{}, 10, policy)
+
+			cache.Set("a", entities.Order{OrderUID: "a"})
+			got, ok := cache.Get("a")
+			if !ok || got.OrderUID != "a" {
+				t.Fatalf("Expected order 'a', got %v (found=%v)", got, ok)
+			}
+
+			cache.Set("a", entities.Order{OrderUID: "a", TrackNumber: "updated"})
+			if got, _ := cache.Get("a"); got.TrackNumber != "updated" {
+				t.Errorf("Expected updated order, got %v", got)
+			}
+
+			if !cache.Delete("a") {
+				t.Error("Expected order 'a' to be deleted")
+			}
+			if _, ok := cache.Get("a"); ok {
+				t.Error("Expected deleted order to be missing")
+			}
+			if cache.Delete("a") {
+				t.Error("Expected second delete to report false")
+			}
+		})
+	}
+}
+
+func TestPolicyCache_CapacityBound(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(string(policy), func(t *testing.T) {
+			cache := NewPolicyOrderCache(&MockLogger{}, 50, policy).(*policyOrderCache)
+
+			for i := 0; i < 500; i++ {
+				id := strconv.Itoa(i)
+				cache.Get(id)
+				cache.Set(id, entities.Order{OrderUID: id})
+				if i%3 == 0 {
+					cache.Get(strconv.Itoa(i / 2))
+				}
+				if len(cache.entries) > 50 {
+					t.Fatalf("Cache grew beyond capacity: %d entries", len(cache.entries))
+				}
+			}
+
+			if got := cache.GetAll(1000); len(got) != len(cache.entries) {
+				t.Errorf("GetAll returned %d orders, cache holds %d", len(got), len(cache.entries))
+			}
+			if got := cache.GetAll(5); len(got) != 5 {
+				t.Errorf("Expected 5 orders, got %d", len(got))
+			}
+
+			cache.Clear()
+			if len(cache.entries) != 0 || cache.bytes != 0 {
+				t.Errorf("Expected empty cache after clear, got %d entries, %d bytes", len(cache.entries), cache.bytes)
+			}
+		})
+	}
+}
+
+// горячие заказы должны пережить разовую массовую запись холодных
+func TestPolicyCache_ScanResistance(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(string(policy), func(t *testing.T) {
+			cache := NewPolicyOrderCache(&MockLogger{}, 100, policy)
+
+			for round := 0; round < 5; round++ {
+				for i := 0; i < 20; i++ {
+					id := "hot" + strconv.Itoa(i)
+					if _, ok := cache.Get(id); !ok {
+						cache.Set(id, entities.Order{OrderUID: id})
+					}
+				}
+			}
+
+			for i := 0; i < 1000; i++ {
+				id := "scan" + strconv.Itoa(i)
+				cache.Set(id, entities.Order{OrderUID: id})
+			}
+
+			survived := 0
+			for i := 0; i < 20; i++ {
+				if _, ok := cache.Get("hot" + strconv.Itoa(i)); ok {
+					survived++
+				}
+			}
+			if survived < 18 {
+				t.Errorf("Expected hot orders to survive the scan, %d of 20 left", survived)
+			}
+		})
+	}
+}
+
+func TestPolicyCache_MaxBytes(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(string(policy), func(t *testing.T) {
+			order := entities.Order{OrderUID: "o", Items: make([]entities.Item, 10)}
+			budget := 5 * estimateOrderSize("00", order)
+			cache := NewPolicyOrderCache(&MockLogger{}, 0, policy, WithMaxBytes(budget)).(*policyOrderCache)
+
+			for i := 0; i < 50; i++ {
+				cache.Set(strconv.Itoa(i+10), order)
+				if cache.bytes > budget {
+					t.Fatalf("Cache bytes %d exceed budget %d", cache.bytes, budget)
+				}
+			}
+			if len(cache.entries) != 5 {
+				t.Errorf("Expected 5 orders within budget, got %d", len(cache.entries))
+			}
+		})
+	}
+}
+
+func TestPolicyCache_LRUFallback(t *testing.T) {
+	if _, ok := NewPolicyOrderCache(&MockLogger{}, 10, PolicyLRU).(*orderLRUCache); !ok {
+		t.Error("Expected PolicyLRU to create orderLRUCache")
+	}
+}
@@ -0,0 +1,232 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/policy_tinylfu.go
+package cache
+
+import "container/list"
+
+// countMinSketch - приблизительные 4-битные счетчики частоты обращений.
+// После sampleSize инкрементов все счетчики делятся пополам, чтобы старая
+// популярность со временем затухала
+type countMinSketch struct {
+	rows       [4][]uint8
+	mask       uint32
+	additions  int
+	sampleSize int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 16
+	for width < capacity {
+		width <<= 1
+	}
+	s := &countMinSketch{
+		mask:       uint32(width - 1),
+		sampleSize: 10 * max(capacity, 16),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// indexes: двойное хэширование a + i*b поверх 64-битного fnv-1a
+func (s *countMinSketch) indexes(key string) [4]uint32 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	a, b := uint32(h), uint32(h>>32)|1
+
+	var idx [4]uint32
+	for i := range idx {
+		idx[i] = (a + uint32(i)*b) & s.mask
+	}
+	return idx
+}
+
+func (s *countMinSketch) increment(key string) {
+	for i, j := range s.indexes(key) {
+		if s.rows[i][j] < 15 {
+			s.rows[i][j]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.halve()
+	}
+}
+
+func (s *countMinSketch) estimate(key string) uint8 {
+	est := uint8(15)
+	for i, j := range s.indexes(key) {
+		est = min(est, s.rows[i][j])
+	}
+	return est
+}
+
+func (s *countMinSketch) halve() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+func (s *countMinSketch) reset() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+	s.additions = 0
+}
+
+const (
+	tlfuWindow = iota
+	tlfuProbation
+	tlfuProtected
+)
+
+type tinyLFUNode struct {
+	key     string
+	segment int
+}
+
+// tinyLFUPolicy - W-TinyLFU: новые ключи попадают в небольшое LRU-окно (1%),
+// а в основную SLRU-область (probation 20% + protected 80%) вытесненный из окна
+// кандидат проходит, только если по оценке sketch он популярнее жертвы.
+// Разовые массовые чтения так и остаются в окне и не вымывают горячие заказы
+type tinyLFUPolicy struct {
+	capacity     int
+	windowCap    int
+	mainCap      int
+	protectedCap int
+
+	sketch   *countMinSketch
+	segments [3]*list.List
+	nodes    map[string]*list.Element
+}
+
+func newTinyLFUPolicy(capacity int) *tinyLFUPolicy {
+	p := &tinyLFUPolicy{
+		capacity: capacity,
+		sketch:   newCountMinSketch(capacity),
+	}
+	if capacity > 0 {
+		p.windowCap = max(1, capacity/100)
+		p.mainCap = capacity - p.windowCap
+		p.protectedCap = p.mainCap * 80 / 100
+	}
+	for i := range p.segments {
+		p.segments[i] = list.New()
+	}
+	p.reset()
+	return p
+}
+
+func (p *tinyLFUPolicy) reset() {
+	for _, l := range p.segments {
+		l.Init()
+	}
+	p.nodes = make(map[string]*list.Element, max(0, p.capacity))
+	p.sketch.reset()
+}
+
+func (p *tinyLFUPolicy) moveTo(elem *list.Element, to int) {
+	n := elem.Value.(*tinyLFUNode)
+	p.segments[n.segment].Remove(elem)
+	n.segment = to
+	p.nodes[n.key] = p.segments[to].PushFront(n)
+}
+
+func (p *tinyLFUPolicy) drop(elem *list.Element) string {
+	n := elem.Value.(*tinyLFUNode)
+	p.segments[n.segment].Remove(elem)
+	delete(p.nodes, n.key)
+	return n.key
+}
+
+func (p *tinyLFUPolicy) hit(key string) {
+	p.sketch.increment(key)
+
+	elem, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	switch elem.Value.(*tinyLFUNode).segment {
+	case tlfuWindow:
+		p.segments[tlfuWindow].MoveToFront(elem)
+	case tlfuProtected:
+		p.segments[tlfuProtected].MoveToFront(elem)
+	case tlfuProbation:
+		p.moveTo(elem, tlfuProtected)
+		if p.segments[tlfuProtected].Len() > p.protectedCap {
+			p.moveTo(p.segments[tlfuProtected].Back(), tlfuProbation)
+		}
+	}
+}
+
+func (p *tinyLFUPolicy) miss(key string) {
+	p.sketch.increment(key)
+}
+
+func (p *tinyLFUPolicy) insert(key string) []string {
+	if _, ok := p.nodes[key]; ok {
+		p.hit(key)
+		return nil
+	}
+
+	p.sketch.increment(key)
+	p.nodes[key] = p.segments[tlfuWindow].PushFront(&tinyLFUNode{key: key, segment: tlfuWindow})
+
+	if p.capacity <= 0 || p.segments[tlfuWindow].Len() <= p.windowCap {
+		return nil
+	}
+
+	candidate := p.segments[tlfuWindow].Back()
+	if p.segments[tlfuProbation].Len()+p.segments[tlfuProtected].Len() < p.mainCap {
+		p.moveTo(candidate, tlfuProbation)
+		return nil
+	}
+
+	victim := p.segments[tlfuProbation].Back()
+	if victim == nil {
+		victim = p.segments[tlfuProtected].Back()
+	}
+	if victim == nil {
+		return []string{p.drop(candidate)}
+	}
+
+	candidateKey := candidate.Value.(*tinyLFUNode).key
+	victimKey := victim.Value.(*tinyLFUNode).key
+	if p.sketch.estimate(candidateKey) > p.sketch.estimate(victimKey) {
+		p.drop(victim)
+		p.moveTo(candidate, tlfuProbation)
+		return []string{victimKey}
+	}
+	return []string{p.drop(candidate)}
+}
+
+func (p *tinyLFUPolicy) remove(key string) {
+	if elem, ok := p.nodes[key]; ok {
+		p.drop(elem)
+	}
+}
+
+func (p *tinyLFUPolicy) victim() (string, bool) {
+	for _, seg := range []int{tlfuProbation, tlfuWindow, tlfuProtected} {
+		if elem := p.segments[seg].Back(); elem != nil {
+			return elem.Value.(*tinyLFUNode).key, true
+		}
+	}
+	return "", false
+}
+
+func (p *tinyLFUPolicy) keys(limit int) []string {
+	keys := make([]string, 0, min(limit, len(p.nodes)))
+	for _, seg := range []int{tlfuProtected, tlfuWindow, tlfuProbation} {
+		for elem := p.segments[seg].Front(); elem != nil && len(keys) < limit; elem = elem.Next() {
+			keys = append(keys, elem.Value.(*tinyLFUNode).key)
+		}
+	}
+	return keys
+}
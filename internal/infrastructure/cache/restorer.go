@@ -3,91 +3,278 @@ package cache
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 )
 
+const (
+	defaultBatchSize     = 1000
+	maxbatchSize         = 5000
+	defaultRetryInterval = time.Second
+)
+
+// RestorerConfig: Capacity <= 0 снимает ограничение на число загружаемых заказов;
+// MaxRetries - сколько раз повторяется упавший запрос пачки
+type RestorerConfig struct {
+	Timeout       time.Duration
+	BatchSize     int
+	Capacity      int
+	MaxRetries    int
+	RetryInterval time.Duration
+}
+
+// CacheRestorer прогревает кэш самыми свежими по date_created заказами. Пачки читаются
+// keyset-пагинацией от старых к свежим, поэтому самые свежие заказы оказываются в голове LRU.
+// Курсор после каждой пачки сохраняется в checkpoint: прогрев, упавший после повторов или по
+// таймауту, следующий запуск продолжает с него, а не с начала
 type CacheRestorer struct {
-	cache     domainrepo.Cache
-	repo      domainrepo.OrderRepository
-	logger    domainrepo.Logger
-	timeout   time.Duration
-	batchSize int
+	cache  domainrepo.Cache
+	repo   domainrepo.OrderRepository
+	logger domainrepo.Logger
+	cfg    RestorerConfig
+
+	mu         sync.Mutex
+	progress   entities.CacheRestoreProgress
+	checkpoint *restoreCheckpoint
 }
 
-const (
-	defaultBatchSize = 1000
-	maxbatchSize = 5000
-)
+// restoreCheckpoint - позиция незавершенного прогрева; сбрасывается после успешного
+type restoreCheckpoint struct {
+	target   int
+	restored int
+	batches  int
+	cursor   entities.OrderCursor
+}
 
-func NewCacheRestorer(cache domainrepo.Cache, repo domainrepo.OrderRepository, logger domainrepo.Logger, timeout time.Duration, batchSize int) *CacheRestorer {
-	if batchSize <= 0 || batchSize > maxbatchSize {
-		batchSize = defaultBatchSize
+func NewCacheRestorer(cache domainrepo.Cache, repo domainrepo.OrderRepository, logger domainrepo.Logger, cfg RestorerConfig) *CacheRestorer {
+	if cfg.BatchSize <= 0 || cfg.BatchSize > maxbatchSize {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
 	}
 	return &CacheRestorer{
-		cache:     cache,
-		repo:      repo,
-		logger:    logger,
-		timeout:   timeout,
-		batchSize: batchSize,
+		cache:    cache,
+		repo:     repo,
+		logger:   logger,
+		cfg:      cfg,
+		progress: entities.CacheRestoreProgress{State: entities.CacheRestoreIdle},
 	}
 }
 
+// Progress возвращает копию текущего состояния прогрева
+func (r *CacheRestorer) Progress() entities.CacheRestoreProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.progress
+}
+
 func (r *CacheRestorer) Restore(ctx context.Context) error {
-	ctx, cancel := context.WithTimeout(ctx, r.timeout)
-	defer cancel()
+	checkpoint, err := r.begin()
+	if err != nil {
+		return err
+	}
 
-	total, err := r.repo.GetOrdersCount(ctx)
+	err = r.restore(ctx, checkpoint)
+	r.finish(err)
+	return err
+}
+
+// TryStart запускает прогрев в фоне и возвращает false, если он уже идет. Проверка и
+// переход в running делаются под одной блокировкой, поэтому два запроса не запустят два прогрева
+func (r *CacheRestorer) TryStart(ctx context.Context) bool {
+	checkpoint, err := r.begin()
 	if err != nil {
-		r.logger.Error("failed to get orders count", "error", err)
+		return false
+	}
+
+	go func() {
+		err := r.restore(ctx, checkpoint)
+		r.finish(err)
+		if err != nil {
+			r.logger.Error("cache warm-up failed", "error", err)
+		}
+	}()
+	return true
+}
+
+func (r *CacheRestorer) restore(ctx context.Context, checkpoint restoreCheckpoint) error {
+	if r.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.Timeout)
+		defer cancel()
+	}
+
+	if checkpoint.target > 0 {
+		r.logger.Info("resuming cache restoration",
+			"restored", checkpoint.restored,
+			"target", checkpoint.target,
+		)
+		return r.load(ctx, checkpoint)
+	}
+
+	var total int
+	if err := r.withRetry(ctx, "count orders", func() (err error) {
+		total, err = r.repo.GetOrdersCount(ctx)
+		return err
+	}); err != nil {
 		return err
 	}
 
-	r.logger.Info("starting cache restoration", "total_orders", total)
+	target := total
+	if r.cfg.Capacity > 0 {
+		target = min(total, r.cfg.Capacity)
+	}
 
-	var restored int
-	for offset := 0; offset < total; offset += r.batchSize {
-		select {
-		case <-ctx.Done():
-			r.logger.Warn("cache restoration timed out",
-				"restored", restored,
-				"total", total,
-			)
-			return ctx.Err()
-		default:
-			batch, err := r.repo.GetAllOrders(ctx, r.batchSize, offset)
-			if err != nil {
-				r.logger.Error("failed to get orders batch",
-					"error", err,
-					"offset", offset,
-					"limit", r.batchSize,
-				)
-				continue
-			}
-
-			for _, order := range batch {
-				r.cache.Set(order.OrderUID, order)
-				restored++
-			}
-
-			// процент восстановленных записей
-			var progress int
-			if total > 0 {
-				progress = (restored * 100) / total
-			}
-
-			r.logger.Debug("processed batch",
-				"batch_size", len(batch),
-				"restored", restored,
-				"progress", progress,
+	var cursor entities.OrderCursor
+	if err := r.withRetry(ctx, "find recent orders cursor", func() (err error) {
+		cursor, err = r.repo.GetRecentCursor(ctx, target)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	r.logger.Info("starting cache restoration", "total_orders", total, "target", target)
+	return r.load(ctx, restoreCheckpoint{target: target, cursor: cursor})
+}
+
+// load читает пачки после checkpoint.cursor, пока не наберет checkpoint.target заказов
+func (r *CacheRestorer) load(ctx context.Context, checkpoint restoreCheckpoint) error {
+	r.save(checkpoint)
+
+	for checkpoint.restored < checkpoint.target {
+		limit := min(r.cfg.BatchSize, checkpoint.target-checkpoint.restored)
+
+		var batch []entities.Order
+		if err := r.withRetry(ctx, "load orders batch", func() (err error) {
+			batch, err = r.repo.GetOrdersAfter(ctx, checkpoint.cursor, limit)
+			return err
+		}); err != nil {
+			r.logger.Error("failed to get orders batch",
+				"error", err,
+				"restored", checkpoint.restored,
+				"target", checkpoint.target,
 			)
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, order := range batch {
+			r.cache.Set(order.OrderUID, order)
 		}
+		checkpoint.restored += len(batch)
+		checkpoint.batches++
+		checkpoint.cursor = entities.CursorOf(batch[len(batch)-1])
+		r.save(checkpoint)
+
+		r.logger.Debug("processed batch",
+			"batch_size", len(batch),
+			"restored", checkpoint.restored,
+			"progress", r.Progress().Percent,
+		)
 	}
 
 	r.logger.Info("cache restoration completed",
-		"restored", restored,
-		"total", total,
+		"restored", checkpoint.restored,
+		"target", checkpoint.target,
 	)
 	return nil
 }
+
+// save запоминает позицию прогрева и показывает ее в Progress
+func (r *CacheRestorer) save(checkpoint restoreCheckpoint) {
+	r.update(func(p *entities.CacheRestoreProgress) {
+		p.Target = checkpoint.target
+		p.Restored = checkpoint.restored
+		p.Batches = checkpoint.batches
+		if !checkpoint.cursor.IsZero() {
+			cursor := checkpoint.cursor
+			p.Cursor = &cursor
+		}
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoint = &checkpoint
+}
+
+// withRetry повторяет op с экспоненциальной паузой не более MaxRetries раз
+func (r *CacheRestorer) withRetry(ctx context.Context, what string, op func() error) error {
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = r.cfg.RetryInterval
+	expBackoff.MaxElapsedTime = 0
+
+	policy := backoff.WithContext(backoff.WithMaxRetries(expBackoff, uint64(r.cfg.MaxRetries)), ctx)
+
+	return backoff.RetryNotify(op, policy, func(err error, wait time.Duration) {
+		r.update(func(p *entities.CacheRestoreProgress) { p.Retries++ })
+		r.logger.Warn("cache restoration step failed, retrying",
+			"step", what,
+			"error", err,
+			"retry_in", wait,
+		)
+	})
+}
+
+// begin переводит прогрев в running и возвращает позицию, с которой его продолжить:
+// нулевую, если прошлый прогрев завершился успешно или еще не запускался
+func (r *CacheRestorer) begin() (restoreCheckpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.progress.State == entities.CacheRestoreRunning {
+		return restoreCheckpoint{}, ErrRestoreInProgress
+	}
+	now := time.Now().UTC()
+	r.progress = entities.CacheRestoreProgress{
+		State:     entities.CacheRestoreRunning,
+		StartedAt: &now,
+	}
+	if r.checkpoint == nil {
+		return restoreCheckpoint{}, nil
+	}
+	r.progress.Resumed = true
+	return *r.checkpoint, nil
+}
+
+func (r *CacheRestorer) finish(err error) {
+	r.update(func(p *entities.CacheRestoreProgress) {
+		now := time.Now().UTC()
+		p.FinishedAt = &now
+		if err != nil {
+			p.State = entities.CacheRestoreFailed
+			p.Error = fmt.Sprint(err)
+			return
+		}
+		p.State = entities.CacheRestoreCompleted
+		if p.Target == 0 {
+			p.Percent = 100
+		}
+	})
+	if err == nil {
+		r.mu.Lock()
+		r.checkpoint = nil
+		r.mu.Unlock()
+	}
+}
+
+func (r *CacheRestorer) update(fn func(p *entities.CacheRestoreProgress)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fn(&r.progress)
+	if r.progress.Target > 0 {
+		r.progress.Percent = r.progress.Restored * 100 / r.progress.Target
+	}
+}
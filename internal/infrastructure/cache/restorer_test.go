@@ -0,0 +1,187 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/restorer_test.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// storeWithOrders кладет n заказов с date_created по возрастанию: o00 - самый старый
+func storeWithOrders(n int) *fakeOrderStore {
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		order := snapshotOrder(fmt.Sprintf("o%02d", i))
+		order.DateCreated = entities.NewTimestamp(base.Add(time.Duration(i) * time.Hour))
+		store.put(order, base)
+	}
+	return store
+}
+
+func TestCacheRestorer_LoadsMostRecentWithinCapacity(t *testing.T) {
+	store := storeWithOrders(10)
+	c := NewOrderLRUCache(&MockLogger{}, 4)
+
+	restorer := NewCacheRestorer(c, store, &MockLogger{}, RestorerConfig{
+		Timeout:   time.Minute,
+		BatchSize: 3,
+		Capacity:  4,
+	})
+	if err := restorer.Restore(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	if got, want := cachedIDs(c), []string{"o09", "o08", "o07", "o06"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected most recent orders %v, got %v", want, got)
+	}
+	if store.pageCalls != 2 {
+		t.Errorf("expected 2 keyset pages, got %d", store.pageCalls)
+	}
+
+	p := restorer.Progress()
+	if p.State != entities.CacheRestoreCompleted || p.Target != 4 || p.Restored != 4 || p.Percent != 100 || p.Batches != 2 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+	if p.StartedAt == nil || p.FinishedAt == nil {
+		t.Errorf("expected start and finish times, got %+v", p)
+	}
+}
+
+func TestCacheRestorer_RetriesFailedBatch(t *testing.T) {
+	store := storeWithOrders(5)
+	store.pageFailures = 2
+	c := NewOrderLRUCache(&MockLogger{}, 10)
+
+	restorer := NewCacheRestorer(c, store, &MockLogger{}, RestorerConfig{
+		Timeout:       time.Minute,
+		BatchSize:     2,
+		Capacity:      10,
+		MaxRetries:    3,
+		RetryInterval: time.Millisecond,
+	})
+	if err := restorer.Restore(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	if got := len(cachedIDs(c)); got != 5 {
+		t.Errorf("expected 5 restored orders, got %d", got)
+	}
+	if p := restorer.Progress(); p.Retries != 2 || p.Restored != 5 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+}
+
+func TestCacheRestorer_FailsAfterRetries(t *testing.T) {
+	store := storeWithOrders(5)
+	store.pageFailures = 10
+	c := NewOrderLRUCache(&MockLogger{}, 10)
+
+	restorer := NewCacheRestorer(c, store, &MockLogger{}, RestorerConfig{
+		Timeout:       time.Minute,
+		BatchSize:     2,
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	})
+	err := restorer.Restore(context.Background())
+	if !errors.Is(err, errStoreUnavailable) {
+		t.Fatalf("expected store error, got %v", err)
+	}
+	if store.pageCalls != 3 {
+		t.Errorf("expected 1 attempt and 2 retries, got %d calls", store.pageCalls)
+	}
+
+	p := restorer.Progress()
+	if p.State != entities.CacheRestoreFailed || p.Error == "" || p.Restored != 0 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+}
+
+func TestCacheRestorer_EmptyStore(t *testing.T) {
+	restorer := NewCacheRestorer(NewOrderLRUCache(&MockLogger{}, 10), newFakeOrderStore(), &MockLogger{}, RestorerConfig{Timeout: time.Minute})
+	if err := restorer.Restore(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if p := restorer.Progress(); p.State != entities.CacheRestoreCompleted || p.Percent != 100 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+}
+
+func TestCacheRestorer_ResumesFromCursorAfterFailure(t *testing.T) {
+	store := storeWithOrders(10)
+	store.pageOK = 1
+	store.pageFailures = 1
+	c := NewOrderLRUCache(&MockLogger{}, 6)
+
+	restorer := NewCacheRestorer(c, store, &MockLogger{}, RestorerConfig{
+		Timeout:   time.Minute,
+		BatchSize: 2,
+		Capacity:  6,
+	})
+	if err := restorer.Restore(context.Background()); !errors.Is(err, errStoreUnavailable) {
+		t.Fatalf("expected store error, got %v", err)
+	}
+	p := restorer.Progress()
+	if p.State != entities.CacheRestoreFailed || p.Restored != 2 || p.Cursor == nil || p.Cursor.OrderUID != "o05" {
+		t.Fatalf("expected failure with cursor after o05, got %+v", p)
+	}
+
+	// второй запуск не считает курсор заново, а дочитывает с сохраненного
+	store.pageCalls = 0
+	if err := restorer.Restore(context.Background()); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if store.pageCalls != 2 {
+		t.Errorf("expected 2 remaining pages, got %d", store.pageCalls)
+	}
+	if got, want := cachedIDs(c), []string{"o09", "o08", "o07", "o06", "o05", "o04"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected most recent orders %v, got %v", want, got)
+	}
+	p = restorer.Progress()
+	if p.State != entities.CacheRestoreCompleted || !p.Resumed || p.Restored != 6 || p.Batches != 3 || p.Percent != 100 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+
+	// после успешного прогрева следующий начинается с начала
+	store.pageCalls = 0
+	if err := restorer.Restore(context.Background()); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if p := restorer.Progress(); p.Resumed || p.Restored != 6 || store.pageCalls != 3 {
+		t.Errorf("expected a fresh restore, got %+v after %d pages", p, store.pageCalls)
+	}
+}
+
+func TestCacheRestorer_TryStartRejectsConcurrentRun(t *testing.T) {
+	store := storeWithOrders(3)
+	c := NewOrderLRUCache(&MockLogger{}, 10)
+	restorer := NewCacheRestorer(c, store, &MockLogger{}, RestorerConfig{Timeout: time.Minute})
+
+	// прогрев уже идет: TryStart не запускает второй
+	if _, err := restorer.begin(); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if restorer.TryStart(context.Background()) {
+		t.Fatal("expected TryStart to report a running restore")
+	}
+	if err := restorer.Restore(context.Background()); !errors.Is(err, ErrRestoreInProgress) {
+		t.Fatalf("expected ErrRestoreInProgress, got %v", err)
+	}
+	restorer.finish(nil)
+
+	if !restorer.TryStart(context.Background()) {
+		t.Fatal("expected TryStart to start a restore")
+	}
+	deadline := time.Now().Add(time.Second)
+	for restorer.Progress().State == entities.CacheRestoreRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if p := restorer.Progress(); p.State != entities.CacheRestoreCompleted || p.Restored != 3 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+}
@@ -0,0 +1,41 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/size.go
+package cache
+
+import (
+	"unsafe"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// накладные расходы на запись: элемент списка, ячейка map и сама entry
+const entryOverhead = 128
+
+// estimateOrderSize оценивает память, занимаемую заказом: размеры структур
+// плюс длины строк. Точность не нужна - важно, чтобы заказ с сотней товаров
+// весил примерно в сто раз больше заказа с одним товаром
+func estimateOrderSize(key string, o entities.Order) int64 {
+	size := int64(entryOverhead + len(key))
+	size += int64(unsafe.Sizeof(o))
+	size += strLen(o.OrderUID, o.TrackNumber, o.Entry, o.Locale, o.InternalSig,
+		o.CustomerID, o.DeliveryService, o.ShardKey, o.OOFShard)
+
+	d := o.Delivery
+	size += strLen(d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email)
+
+	p := o.Payment
+	size += strLen(p.Transaction, p.RequestID, p.Currency, p.Provider, p.Bank)
+
+	size += int64(cap(o.Items)) * int64(unsafe.Sizeof(entities.Item{}))
+	for _, it := range o.Items {
+		size += strLen(it.TrackNumber, it.RID, it.Name, it.Size, it.Brand)
+	}
+	return size
+}
+
+func strLen(ss ...string) int64 {
+	var n int64
+	for _, s := range ss {
+		n += int64(len(s))
+	}
+	return n
+}
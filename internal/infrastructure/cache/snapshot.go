@@ -0,0 +1,327 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/snapshot.go
+package cache
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+const snapshotFormat = 2
+
+// snapshotHeader - первая строка файла; watermark - максимальный updated_at в БД на момент снимка
+type snapshotHeader struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+	Watermark time.Time `json:"watermark"`
+	Count     int       `json:"count"`
+}
+
+// snapshotEntry - заказ с версией, под которой он попал в кэш; нулевая версия
+// означает, что копию не с чем сверить и заказ перечитывается из БД
+type snapshotEntry struct {
+	UpdatedAt time.Time      `json:"updated_at"`
+	Order     entities.Order `json:"order"`
+	// PIIOmitted - PII доставки не записаны (нет ключа шифрования), заказ перечитывается из БД
+	PIIOmitted bool `json:"pii_omitted,omitempty"`
+}
+
+// CacheSnapshot сохраняет кэш в gzip-файл JSON-строк (от самых свежих к старым)
+// и при старте поднимает его, догружая из БД только изменившиеся заказы.
+// PII доставки шифруются cipher; без него в файл не пишутся
+type CacheSnapshot struct {
+	cache     domainrepo.Cache
+	repo      domainrepo.OrderRepository
+	versions  domainrepo.OrderVersionRepository
+	cipher    domainrepo.FieldCipher
+	logger    domainrepo.Logger
+	path      string
+	capacity  int
+	batchSize int
+	timeout   time.Duration
+}
+
+func NewCacheSnapshot(cache domainrepo.Cache, repo domainrepo.OrderRepository, versions domainrepo.OrderVersionRepository, cipher domainrepo.FieldCipher, logger domainrepo.Logger, path string, capacity, batchSize int, timeout time.Duration) *CacheSnapshot {
+	if batchSize <= 0 || batchSize > maxbatchSize {
+		batchSize = defaultBatchSize
+	}
+	return &CacheSnapshot{
+		cache:     cache,
+		repo:      repo,
+		versions:  versions,
+		cipher:    cipher,
+		logger:    logger,
+		path:      path,
+		capacity:  capacity,
+		batchSize: batchSize,
+		timeout:   timeout,
+	}
+}
+
+// Save записывает содержимое кэша в файл. Версией записи служит updated_at, с которым
+// заказ был прочитан в кэш, а не текущий в БД: иначе устаревшая копия сошла бы за свежую
+func (s *CacheSnapshot) Save(ctx context.Context) (int, error) {
+	orders := s.cache.GetAll(s.capacity)
+
+	watermark, err := s.versions.GetLatestVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	entries := make([]snapshotEntry, 0, len(orders))
+	for _, order := range orders {
+		entry, err := s.seal(order)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	header := snapshotHeader{
+		Format:    snapshotFormat,
+		CreatedAt: time.Now().UTC(),
+		Watermark: watermark,
+		Count:     len(entries),
+	}
+	if err := s.write(header, entries); err != nil {
+		s.logger.Error("failed to write cache snapshot", "path", s.path, "error", err)
+		return 0, err
+	}
+
+	s.logger.Info("cache snapshot saved", "path", s.path, "orders", len(entries), "watermark", watermark)
+	return len(entries), nil
+}
+
+// write пишет во временный файл и переименовывает его, чтобы не оставить обрезанный снимок
+func (s *CacheSnapshot) write(header snapshotHeader, entries []snapshotEntry) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := gzip.NewWriter(tmp)
+	bw := bufio.NewWriter(zw)
+	enc := json.NewEncoder(bw)
+
+	if err := enc.Encode(header); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotWrite, err)
+	}
+	return nil
+}
+
+// Load поднимает снимок в кэш: совпавшие по updated_at заказы берутся из файла,
+// изменившиеся перечитываются из БД, удаленные отбрасываются, а заказы,
+// появившиеся после watermark, догружаются до заполнения емкости
+func (s *CacheSnapshot) Load(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	header, entries, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+
+	orders := make([]entities.Order, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	var kept, refreshed, dropped int
+
+	for start := 0; start < len(entries); start += s.batchSize {
+		batch := entries[start:min(start+s.batchSize, len(entries))]
+		ids := make([]string, len(batch))
+		for i, e := range batch {
+			ids[i] = e.Order.OrderUID
+		}
+
+		versions, err := s.versions.GetVersions(ctx, ids)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, e := range batch {
+			updatedAt, ok := versions[e.Order.OrderUID]
+			if !ok {
+				dropped++
+				continue
+			}
+
+			if order, ok := s.open(e, updatedAt); ok {
+				orders = append(orders, order)
+				kept++
+			} else {
+				order, err := s.repo.GetOrder(ctx, e.Order.OrderUID)
+				if errors.Is(err, domain.ErrOrderNotFound) {
+					dropped++
+					continue
+				}
+				if err != nil {
+					return 0, err
+				}
+				orders = append(orders, order)
+				refreshed++
+			}
+			seen[e.Order.OrderUID] = struct{}{}
+		}
+	}
+
+	// заказы, появившиеся после снимка, менее ценны, чем горячие из снимка, поэтому
+	// идут в хвост очереди вытеснения
+	var fresh []entities.Order
+	if free := s.capacity - len(orders); free > 0 {
+		ids, err := s.versions.GetUpdatedSince(ctx, header.Watermark, free)
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			order, err := s.repo.GetOrder(ctx, id)
+			if errors.Is(err, domain.ErrOrderNotFound) {
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			fresh = append(fresh, order)
+		}
+	}
+
+	// Set ставит запись в голову очереди, поэтому вставляем от старых к свежим
+	for i := len(fresh) - 1; i >= 0; i-- {
+		s.cache.Set(fresh[i].OrderUID, fresh[i])
+	}
+	for i := len(orders) - 1; i >= 0; i-- {
+		s.cache.Set(orders[i].OrderUID, orders[i])
+	}
+
+	s.logger.Info("cache snapshot loaded",
+		"path", s.path,
+		"snapshot_created_at", header.CreatedAt,
+		"kept", kept,
+		"refreshed", refreshed,
+		"dropped", dropped,
+		"added", len(fresh),
+	)
+	return len(orders) + len(fresh), nil
+}
+
+func (s *CacheSnapshot) read() (snapshotHeader, []snapshotEntry, error) {
+	var header snapshotHeader
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return header, nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return header, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return header, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+	}
+	defer zr.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(zr))
+	if err := dec.Decode(&header); err != nil {
+		return header, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+	}
+	if header.Format != snapshotFormat {
+		return header, nil, fmt.Errorf("%w: unsupported format %d", ErrSnapshotCorrupt, header.Format)
+	}
+
+	entries := make([]snapshotEntry, 0, min(header.Count, s.capacity))
+	for len(entries) < s.capacity {
+		var e snapshotEntry
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return header, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+		}
+		entries = append(entries, e)
+	}
+
+	return header, entries, nil
+}
+
+// seal готовит запись снимка: PII шифруются, а без ключа вырезаются
+func (s *CacheSnapshot) seal(order entities.Order) (snapshotEntry, error) {
+	entry := snapshotEntry{UpdatedAt: order.UpdatedAt, Order: order}
+	if s.cipher == nil {
+		entry.Order.Delivery, _ = order.Delivery.TransformPII(func(string, string) (string, error) {
+			entry.PIIOmitted = true
+			return "", nil
+		})
+		return entry, nil
+	}
+
+	delivery, err := order.Delivery.TransformPII(func(_, value string) (string, error) {
+		return s.cipher.Encrypt(value)
+	})
+	if err != nil {
+		return snapshotEntry{}, err
+	}
+	entry.Order.Delivery = delivery
+	return entry, nil
+}
+
+// open возвращает заказ из записи, если его можно отдать без обращения к БД:
+// версия совпадает с текущей и PII удалось расшифровать
+func (s *CacheSnapshot) open(e snapshotEntry, current time.Time) (entities.Order, bool) {
+	if e.UpdatedAt.IsZero() || !e.UpdatedAt.Equal(current) || e.PIIOmitted {
+		return entities.Order{}, false
+	}
+
+	order := e.Order
+	order.UpdatedAt = e.UpdatedAt
+	if s.cipher == nil {
+		return order, true
+	}
+
+	delivery, err := order.Delivery.TransformPII(func(_, value string) (string, error) {
+		return s.cipher.Decrypt(value)
+	})
+	if err != nil {
+		s.logger.Warn("failed to decrypt cache snapshot entry, reloading from DB", "order_uid", order.OrderUID, "error", err)
+		return entities.Order{}, false
+	}
+	order.Delivery = delivery
+	return order, true
+}
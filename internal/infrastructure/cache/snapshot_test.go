@@ -0,0 +1,298 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/snapshot_test.go
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+func snapshotOrder(id string) entities.Order {
+	return entities.Order{
+		OrderUID:    id,
+		TrackNumber: "TRACK" + id,
+		CustomerID:  "customer",
+		DateCreated: entities.NewTimestamp(time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)),
+		Delivery:    entities.Delivery{Name: "John Doe", Phone: "+123456", Email: "test@example.com"},
+		Payment:     entities.Payment{Transaction: id, Currency: "USD", Amount: 100, GoodsTotal: 100},
+		Items:       []entities.Item{{ChrtID: 1, TrackNumber: "TRACK" + id, Price: 100, TotalPrice: 100, Name: "item"}},
+	}
+}
+
+// testCipher - обратимое "шифрование" для проверки, что PII не попадают в файл открытым текстом
+type testCipher struct{}
+
+func (testCipher) Encrypt(plaintext string) (string, error) {
+	return "enc:" + reverse(plaintext), nil
+}
+
+func (testCipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, "enc:") {
+		return "", errors.New("not encrypted")
+	}
+	return reverse(strings.TrimPrefix(value, "enc:")), nil
+}
+
+func (testCipher) NeedsRotation(string) bool           { return false }
+func (testCipher) Rotate(value string) (string, error) { return value, nil }
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func readSnapshotFile(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, zr); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func cachedIDs(c interface{ GetAll(int) []entities.Order }) []string {
+	var ids []string
+	for _, o := range c.GetAll(100) {
+		ids = append(ids, o.OrderUID)
+	}
+	return ids
+}
+
+func TestCacheSnapshot_RoundTripKeepsLRUOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot.gz")
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	src := NewOrderLRUCache(&MockLogger{}, 10)
+	for i, id := range []string{"a", "b", "c"} {
+		src.Set(id, store.put(snapshotOrder(id), base.Add(time.Duration(i)*time.Second)))
+	}
+	src.Get("a")
+
+	saved, err := NewCacheSnapshot(src, store, store, testCipher{}, &MockLogger{}, path, 10, 2, time.Minute).Save(context.Background())
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if saved != 3 {
+		t.Fatalf("expected 3 saved orders, got %d", saved)
+	}
+
+	dst := NewOrderLRUCache(&MockLogger{}, 10)
+	loaded, err := NewCacheSnapshot(dst, store, store, testCipher{}, &MockLogger{}, path, 10, 2, time.Minute).Load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded != 3 {
+		t.Fatalf("expected 3 loaded orders, got %d", loaded)
+	}
+	if got, want := cachedIDs(dst), cachedIDs(src); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected LRU order %v, got %v", want, got)
+	}
+	if len(store.fetched) != 0 {
+		t.Errorf("expected no DB reads for unchanged orders, got %v", store.fetched)
+	}
+
+	got, _ := dst.Get("b")
+	if want := store.orders["b"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("order changed after round trip:\nwant %+v\ngot  %+v", want, got)
+	}
+}
+
+func TestCacheSnapshot_LoadVerifiesAgainstDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot.gz")
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	src := NewOrderLRUCache(&MockLogger{}, 10)
+	for _, id := range []string{"kept", "changed", "deleted"} {
+		src.Set(id, store.put(snapshotOrder(id), base))
+	}
+	if _, err := NewCacheSnapshot(src, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Save(context.Background()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// пока сервис стоял, заказы менялись другими экземплярами
+	changed := snapshotOrder("changed")
+	changed.TrackNumber = "NEW"
+	store.put(changed, base.Add(time.Minute))
+	store.remove("deleted")
+	store.put(snapshotOrder("new"), base.Add(2*time.Minute))
+
+	dst := NewOrderLRUCache(&MockLogger{}, 10)
+	loaded, err := NewCacheSnapshot(dst, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded != 3 {
+		t.Fatalf("expected 3 loaded orders, got %d", loaded)
+	}
+
+	if _, ok := dst.Get("deleted"); ok {
+		t.Error("expected deleted order to be dropped")
+	}
+	if got, _ := dst.Get("changed"); got.TrackNumber != "NEW" {
+		t.Errorf("expected changed order to be refreshed, got track %q", got.TrackNumber)
+	}
+	if _, ok := dst.Get("new"); !ok {
+		t.Error("expected order created after snapshot to be loaded")
+	}
+	if want := []string{"changed", "new"}; !reflect.DeepEqual(store.fetched, want) {
+		t.Errorf("expected DB reads %v, got %v", want, store.fetched)
+	}
+}
+
+func TestCacheSnapshot_LoadRespectsCapacity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot.gz")
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	src := NewOrderLRUCache(&MockLogger{}, 10)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		src.Set(id, store.put(snapshotOrder(id), base))
+	}
+	if _, err := NewCacheSnapshot(src, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Save(context.Background()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	store.put(snapshotOrder("e"), base.Add(time.Minute))
+
+	dst := NewOrderLRUCache(&MockLogger{}, 2)
+	loaded, err := NewCacheSnapshot(dst, store, store, testCipher{}, &MockLogger{}, path, 2, 10, time.Minute).Load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded != 2 {
+		t.Fatalf("expected 2 loaded orders, got %d", loaded)
+	}
+	if got, want := cachedIDs(dst), []string{"d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected hottest orders %v, got %v", want, got)
+	}
+}
+
+func TestCacheSnapshot_LoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := newFakeOrderStore()
+
+	missing := NewCacheSnapshot(NewOrderLRUCache(&MockLogger{}, 10), store, store, testCipher{}, &MockLogger{}, filepath.Join(dir, "missing.gz"), 10, 10, time.Minute)
+	if _, err := missing.Load(context.Background()); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("expected ErrSnapshotNotFound, got %v", err)
+	}
+
+	path := filepath.Join(dir, "corrupt.gz")
+	if err := os.WriteFile(path, []byte("not a gzip stream"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := NewCacheSnapshot(NewOrderLRUCache(&MockLogger{}, 10), store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute)
+	if _, err := corrupt.Load(context.Background()); !errors.Is(err, ErrSnapshotCorrupt) {
+		t.Errorf("expected ErrSnapshotCorrupt, got %v", err)
+	}
+}
+
+func TestCacheSnapshot_StaleCachedCopyNotKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot.gz")
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	src := NewOrderLRUCache(&MockLogger{}, 10)
+	// stale прочитан в кэш до того, как другой экземпляр его изменил, а кэш не узнал об этом
+	src.Set("stale", store.put(snapshotOrder("stale"), base))
+	changed := snapshotOrder("stale")
+	changed.TrackNumber = "NEW"
+	store.put(changed, base.Add(time.Minute))
+	// written попал в кэш из запроса на запись, версия его копии неизвестна
+	store.put(snapshotOrder("written"), base)
+	src.Set("written", snapshotOrder("written"))
+
+	if _, err := NewCacheSnapshot(src, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Save(context.Background()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	dst := NewOrderLRUCache(&MockLogger{}, 10)
+	if _, err := NewCacheSnapshot(dst, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Load(context.Background()); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if got, _ := dst.Get("stale"); got.TrackNumber != "NEW" {
+		t.Errorf("expected stale copy to be refreshed from DB, got track %q", got.TrackNumber)
+	}
+	if want := []string{"written", "stale"}; !reflect.DeepEqual(store.fetched, want) {
+		t.Errorf("expected DB reads %v, got %v", want, store.fetched)
+	}
+}
+
+func TestCacheSnapshot_PIIEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot.gz")
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	src := NewOrderLRUCache(&MockLogger{}, 10)
+	src.Set("a", store.put(snapshotOrder("a"), base))
+
+	if _, err := NewCacheSnapshot(src, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Save(context.Background()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for _, pii := range []string{"John Doe", "+123456", "test@example.com"} {
+		if strings.Contains(readSnapshotFile(t, path), pii) {
+			t.Errorf("snapshot contains plaintext %q", pii)
+		}
+	}
+
+	dst := NewOrderLRUCache(&MockLogger{}, 10)
+	if _, err := NewCacheSnapshot(dst, store, store, testCipher{}, &MockLogger{}, path, 10, 10, time.Minute).Load(context.Background()); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got, _ := dst.Get("a"); got.Delivery != snapshotOrder("a").Delivery {
+		t.Errorf("expected decrypted delivery, got %+v", got.Delivery)
+	}
+	if len(store.fetched) != 0 {
+		t.Errorf("expected no DB reads, got %v", store.fetched)
+	}
+}
+
+func TestCacheSnapshot_PIIOmittedWithoutCipher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot.gz")
+	store := newFakeOrderStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	src := NewOrderLRUCache(&MockLogger{}, 10)
+	src.Set("a", store.put(snapshotOrder("a"), base))
+
+	if _, err := NewCacheSnapshot(src, store, store, nil, &MockLogger{}, path, 10, 10, time.Minute).Save(context.Background()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if content := readSnapshotFile(t, path); strings.Contains(content, "John Doe") || strings.Contains(content, "+123456") {
+		t.Error("snapshot contains plaintext PII")
+	}
+
+	dst := NewOrderLRUCache(&MockLogger{}, 10)
+	if _, err := NewCacheSnapshot(dst, store, store, nil, &MockLogger{}, path, 10, 10, time.Minute).Load(context.Background()); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got, _ := dst.Get("a"); got.Delivery.Name != "John Doe" {
+		t.Errorf("expected order with PII to be reloaded from DB, got %+v", got.Delivery)
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(store.fetched, want) {
+		t.Errorf("expected DB reads %v, got %v", want, store.fetched)
+	}
+}
@@ -0,0 +1,26 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/stats.go
+package cache
+
+import "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+
+// counters меняются под мьютексом кэша, поэтому атомики не нужны;
+// Clear их не сбрасывает - статистика копится с момента старта
+type counters struct {
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func (c counters) stats(policy string, size, capacity int, bytes, maxBytes int64) entities.CacheStats {
+	return entities.CacheStats{
+		Policy:    policy,
+		Size:      size,
+		Capacity:  capacity,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		HitRatio:  entities.HitRatio(c.hits, c.misses),
+		Bytes:     bytes,
+		MaxBytes:  maxBytes,
+	}
+}
@@ -0,0 +1,139 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/cache/store_test.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+var errStoreUnavailable = errors.New("store unavailable")
+
+// fakeOrderStore - БД заказов с updated_at для проверки снимков
+type fakeOrderStore struct {
+	orders   map[string]entities.Order
+	versions map[string]time.Time
+	fetched  []string
+	// pageFailures - сколько ближайших вызовов GetOrdersAfter вернут ошибку,
+	// после того как pageOK вызовов пройдут успешно
+	pageFailures int
+	pageOK       int
+	pageCalls    int
+}
+
+func newFakeOrderStore() *fakeOrderStore {
+	return &fakeOrderStore{orders: map[string]entities.Order{}, versions: map[string]time.Time{}}
+}
+
+// put сохраняет заказ и возвращает его таким, каким его прочитал бы репозиторий
+func (s *fakeOrderStore) put(order entities.Order, updatedAt time.Time) entities.Order {
+	order.UpdatedAt = updatedAt
+	s.orders[order.OrderUID] = order
+	s.versions[order.OrderUID] = updatedAt
+	return order
+}
+
+func (s *fakeOrderStore) remove(id string) {
+	delete(s.orders, id)
+	delete(s.versions, id)
+}
+
+func (s *fakeOrderStore) SaveOrder(ctx context.Context, order entities.Order) error { return nil }
+func (s *fakeOrderStore) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	s.fetched = append(s.fetched, id)
+	order, ok := s.orders[id]
+	if !ok {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+func (s *fakeOrderStore) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	return nil, nil
+}
+func (s *fakeOrderStore) GetOrdersCount(ctx context.Context) (int, error) { return len(s.orders), nil }
+
+// sorted возвращает заказы по возрастанию (date_created, order_uid)
+func (s *fakeOrderStore) sorted() []entities.Order {
+	orders := make([]entities.Order, 0, len(s.orders))
+	for _, o := range s.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return cursorLess(entities.CursorOf(orders[i]), entities.CursorOf(orders[j]))
+	})
+	return orders
+}
+
+func cursorLess(a, b entities.OrderCursor) bool {
+	if !a.DateCreated.Equal(b.DateCreated) {
+		return a.DateCreated.Before(b.DateCreated)
+	}
+	return a.OrderUID < b.OrderUID
+}
+
+func (s *fakeOrderStore) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	orders := s.sorted()
+	if len(orders) <= n {
+		return entities.OrderCursor{}, nil
+	}
+	return entities.CursorOf(orders[len(orders)-n-1]), nil
+}
+
+func (s *fakeOrderStore) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	s.pageCalls++
+	if s.pageOK > 0 {
+		s.pageOK--
+	} else if s.pageFailures > 0 {
+		s.pageFailures--
+		return nil, errStoreUnavailable
+	}
+
+	var page []entities.Order
+	for _, o := range s.sorted() {
+		if len(page) == limit {
+			break
+		}
+		if after.IsZero() || cursorLess(after, entities.CursorOf(o)) {
+			page = append(page, o)
+		}
+	}
+	return page, nil
+}
+
+func (s *fakeOrderStore) DeleteOrder(ctx context.Context, id string) error { return nil }
+func (s *fakeOrderStore) ClearOrders(ctx context.Context) error            { return nil }
+func (s *fakeOrderStore) Shutdown(ctx context.Context) error               { return nil }
+
+func (s *fakeOrderStore) GetVersions(ctx context.Context, ids []string) (map[string]time.Time, error) {
+	versions := map[string]time.Time{}
+	for _, id := range ids {
+		if v, ok := s.versions[id]; ok {
+			versions[id] = v
+		}
+	}
+	return versions, nil
+}
+
+func (s *fakeOrderStore) GetUpdatedSince(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	var ids []string
+	for id, v := range s.versions {
+		if v.After(since) && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *fakeOrderStore) GetLatestVersion(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, v := range s.versions {
+		if v.After(latest) {
+			latest = v
+		}
+	}
+	return latest, nil
+}
@@ -2,20 +2,79 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
+var ErrConfigRead = errors.New("failed to read config")
+
 type RestorationConfig struct {
+	Timeout       time.Duration `mapstructure:"timeout"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	MaxRetries    int           `mapstructure:"max_retries"`
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+}
+
+// SnapshotConfig: пустой path выключает снимок кэша при остановке
+type SnapshotConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// InvalidationConfig: backend none | postgres | kafka; channel - канал NOTIFY или топик Kafka;
+// пустой instance_id генерируется при старте
+type InvalidationConfig struct {
+	Backend    string `mapstructure:"backend"`
+	Channel    string `mapstructure:"channel"`
+	InstanceID string `mapstructure:"instance_id"`
+}
+
+// RedisConfig: capacity и ttl равные 0 снимают ограничение; timeout - на одну операцию
+type RedisConfig struct {
+	Addr      string        `mapstructure:"addr"`
+	Password  string        `mapstructure:"password"`
+	DB        int           `mapstructure:"db"`
+	KeyPrefix string        `mapstructure:"key_prefix"`
+	Capacity  int           `mapstructure:"capacity"`
+	TTL       time.Duration `mapstructure:"ttl"`
 	Timeout   time.Duration `mapstructure:"timeout"`
-	BatchSize int           `mapstructure:"batch_size"`
 }
 
+// CacheConsistencyConfig: mode cache | strict; verify_interval равный 0 выключает фоновую сверку,
+// repair false - расхождения только попадают в лог и отчет
+type CacheConsistencyConfig struct {
+	Mode           string        `mapstructure:"mode"`
+	VerifyInterval time.Duration `mapstructure:"verify_interval"`
+	VerifySample   int           `mapstructure:"verify_sample"`
+	Repair         bool          `mapstructure:"repair"`
+}
+
+// CacheConfig: backend memory | redis | tiered (локальный кэш перед Redis);
+// ttl и max_bytes равные 0 отключают соответствующее ограничение
 type CacheConfig struct {
-	Capacity    int               `mapstructure:"capacity"`
-	GetAllLimit int               `mapstructure:"get_all_limit"`
-	Restoration RestorationConfig `mapstructure:"restoration"`
+	Backend       string                 `mapstructure:"backend"`
+	Capacity      int                    `mapstructure:"capacity"`
+	Shards        int                    `mapstructure:"shards"`
+	Policy        string                 `mapstructure:"policy"`
+	GetAllLimit   int                    `mapstructure:"get_all_limit"`
+	TTL           time.Duration          `mapstructure:"ttl"`
+	SweepInterval time.Duration          `mapstructure:"sweep_interval"`
+	MaxBytes      int64                  `mapstructure:"max_bytes"`
+	NegativeTTL   time.Duration          `mapstructure:"negative_ttl"`
+	Restoration   RestorationConfig      `mapstructure:"restoration"`
+	Snapshot      SnapshotConfig         `mapstructure:"snapshot"`
+	Invalidation  InvalidationConfig     `mapstructure:"invalidation"`
+	Redis         RedisConfig            `mapstructure:"redis"`
+	Consistency   CacheConsistencyConfig `mapstructure:"consistency"`
+}
+
+// SQLiteConfig: path - файл БД; busy_timeout - сколько ждать блокировку записи
+type SQLiteConfig struct {
+	Path        string        `mapstructure:"path"`
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"`
 }
 
 type DatabaseConfig struct {
@@ -25,6 +84,47 @@ type DatabaseConfig struct {
 	ConnMaxLifetime        time.Duration `mapstructure:"conn_max_lifetime"`
 	StatementTimeout       time.Duration `mapstructure:"statement_timeout"`
 	IdleInTxSessionTimeout time.Duration `mapstructure:"idle_in_tx_session_timeout"`
+	// Layout - normalized (таблицы orders, delivery, payment, items) или document (JSONB)
+	Layout         string               `mapstructure:"layout"`
+	Replicas       ReplicaConfig        `mapstructure:"replicas"`
+	Archive        ArchiveConfig        `mapstructure:"archive"`
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	Retry          DatabaseRetryConfig  `mapstructure:"retry"`
+}
+
+// DatabaseRetryConfig: reads и writes переопределяют ненулевые поля общей политики
+// для чтений и для записи/удаления заказов
+type DatabaseRetryConfig struct {
+	RetryConfig `mapstructure:",squash"`
+	Reads       RetryConfig `mapstructure:"reads"`
+	Writes      RetryConfig `mapstructure:"writes"`
+}
+
+// CircuitBreakerConfig: после failure_threshold ошибок подряд вызовы отклоняются open_timeout,
+// затем half_open_requests пробных вызовов решают, замкнуть предохранитель или снова разомкнуть
+type CircuitBreakerConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`
+	HalfOpenRequests int           `mapstructure:"half_open_requests"`
+}
+
+// ArchiveConfig: месячные секции заказов, целиком старше max_age, уходят в архивные таблицы
+// (mode: table) или в NDJSON.gz в каталоге dir (mode: file); interval - период обслуживания секций
+type ArchiveConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Mode     string        `mapstructure:"mode"`
+	Dir      string        `mapstructure:"dir"`
+	MaxAge   time.Duration `mapstructure:"max_age"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// ReplicaConfig: чтения заказов идут на реплики по кругу; read_your_writes - сколько после записи
+// читать заказ с primary (после очистки заказов - все чтения), 0 - не закреплять
+type ReplicaConfig struct {
+	DSNs                []string      `mapstructure:"dsns"`
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"`
+	ReadYourWrites      time.Duration `mapstructure:"read_your_writes"`
 }
 
 type KafkaConfig struct {
@@ -41,16 +141,22 @@ type KafkaConfig struct {
 	BatchTimeout   time.Duration `mapstructure:"batch_timeout"`
 	BatchSize      int           `mapstructure:"batch_size"`
 	Retry          RetryConfig   `mapstructure:"retry"`
+	// DLQCircuitBreaker размыкается, когда запись в DLQ раз за разом падает
+	DLQCircuitBreaker CircuitBreakerConfig `mapstructure:"dlq_circuit_breaker"`
 }
 
+// ServerConfig: request_timeout - дедлайн запроса, в него укладываются и повторы к БД; 0 - без дедлайна
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
+	Port           string        `mapstructure:"port"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
 }
 
 type MigrationsConfig struct {
-	MigrationsPath string `mapstructure:"migrations_path"`
+	MigrationsPath string      `mapstructure:"migrations_path"`
+	Retry          RetryConfig `mapstructure:"retry"`
 }
 
+// RetryConfig - экспоненциальная политика повторов; нулевые поля - значения backoff по умолчанию
 type RetryConfig struct {
 	MaxElapsedTime      time.Duration `mapstructure:"max_elapsed_time"`
 	InitialInterval     time.Duration `mapstructure:"initial_interval"`
@@ -59,31 +165,86 @@ type RetryConfig struct {
 	MaxInterval         time.Duration `mapstructure:"max_interval"`
 }
 
+// ConsistencyConfig: правило -> действие (off, reject, warn, flag)
+type ConsistencyConfig struct {
+	Rules map[string]string `mapstructure:"rules"`
+}
+
+// CurrencyConfig: пустой rates_file выключает пересчет в отчетную валюту
+type CurrencyConfig struct {
+	ReportingCurrency string `mapstructure:"reporting_currency"`
+	RatesFile         string `mapstructure:"rates_file"`
+}
+
+// PIIConfig: пустой key_file выключает шифрование данных доставки
+type PIIConfig struct {
+	KeyFile           string `mapstructure:"key_file"`
+	RotateOnStart     bool   `mapstructure:"rotate_on_start"`
+	RotationBatchSize int    `mapstructure:"rotation_batch_size"`
+}
+
+// APIKeyConfig: id - имя ключа в журналах (например, кто удалил данные клиента)
+type APIKeyConfig struct {
+	ID    string `mapstructure:"id"`
+	Token string `mapstructure:"token"`
+	Role  string `mapstructure:"role"`
+}
+
+type AuthConfig struct {
+	DefaultRole string         `mapstructure:"default_role"`
+	APIKeys     []APIKeyConfig `mapstructure:"api_keys"`
+}
+
+// Config: storage postgres | sqlite | memory (без БД, заказы теряются при остановке)
 type Config struct {
-	Cache      CacheConfig      `mapstructure:"cache"`
-	Database   DatabaseConfig   `mapstructure:"database"`
-	Kafka      KafkaConfig      `mapstructure:"kafka"`
-	Server     ServerConfig     `mapstructure:"server"`
-	Migrations MigrationsConfig `mapstructure:"migrations"`
+	Storage     string            `mapstructure:"storage"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	SQLite      SQLiteConfig      `mapstructure:"sqlite"`
+	Kafka       KafkaConfig       `mapstructure:"kafka"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Migrations  MigrationsConfig  `mapstructure:"migrations"`
+	Consistency ConsistencyConfig `mapstructure:"consistency"`
+	Currency    CurrencyConfig    `mapstructure:"currency"`
+	PII         PIIConfig         `mapstructure:"pii"`
+	Auth        AuthConfig        `mapstructure:"auth"`
 }
 
+// LoadConfig читает файл поверх значений по умолчанию (defaults.go), применяет переменные окружения
+// и проверяет результат; ошибка проверки перечисляет все неверные ключи
 func LoadConfig(path string) (*Config, error) {
-	viper.SetConfigFile(path)
+	cfg, _, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
 
-	viper.AutomaticEnv()
+// load возвращает конфиг без проверки и viper с итоговыми значениями всех ключей
+func load(path string) (*Config, *viper.Viper, error) {
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	v.SetConfigFile(path)
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, err
+	// любой ключ переопределяется переменной окружения: kafka.retry.max_interval -> KAFKA_RETRY_MAX_INTERVAL
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrConfigRead, err)
 	}
 
-	viper.BindEnv("database.dsn", "POSTGRES_DSN")
-	viper.BindEnv("kafka.brokers", "KAFKA_BROKERS")
-	viper.BindEnv("log.mode", "LOG_MODE")
+	// прежнее имя переменной, действует, если DATABASE_DSN не задана
+	v.BindEnv("database.dsn", "POSTGRES_DSN")
 
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
-		return nil, err
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrConfigRead, err)
 	}
-
-	return &cfg, nil
+	return &cfg, v, nil
 }
@@ -0,0 +1,179 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config/config_test.go
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	path := writeConfig(t, "storage: memory\n")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "memory", cfg.Cache.Backend)
+	assert.Equal(t, 10000, cfg.Cache.Capacity)
+	assert.Equal(t, int64(256<<20), cfg.Cache.MaxBytes)
+	assert.Equal(t, "8081", cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.RequestTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Database.Retry.MaxElapsedTime)
+	assert.Zero(t, cfg.Database.Retry.Reads.MaxElapsedTime, "reads inherit the shared policy by default")
+	assert.Equal(t, 5*time.Minute, cfg.Migrations.Retry.MaxElapsedTime)
+	assert.Empty(t, cfg.Kafka.Brokers)
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	path := writeConfig(t, `
+storage: postgres
+database:
+  dsn: "postgres://file"
+  retry:
+    reads:
+      max_elapsed_time: 3s
+kafka:
+  topic: orders
+`)
+	t.Setenv("DATABASE_RETRY_READS_MAX_ELAPSED_TIME", "1s")
+	t.Setenv("CACHE_REDIS_ADDR", "redis-env:6379")
+	t.Setenv("KAFKA_BROKERS", "k1:9092,k2:9092")
+	t.Setenv("SERVER_REQUEST_TIMEOUT", "5s")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://file", cfg.Database.DSN)
+	assert.Equal(t, time.Second, cfg.Database.Retry.Reads.MaxElapsedTime)
+	assert.Equal(t, "redis-env:6379", cfg.Cache.Redis.Addr)
+	assert.Equal(t, []string{"k1:9092", "k2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, 5*time.Second, cfg.Server.RequestTimeout)
+}
+
+func TestLoadConfig_DSNEnvNames(t *testing.T) {
+	path := writeConfig(t, "storage: postgres\n")
+
+	t.Setenv("POSTGRES_DSN", "postgres://legacy")
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://legacy", cfg.Database.DSN)
+
+	t.Setenv("DATABASE_DSN", "postgres://new")
+	cfg, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://new", cfg.Database.DSN)
+}
+
+func TestLoadConfig_ReportsAllErrors(t *testing.T) {
+	path := writeConfig(t, `
+storage: postgres
+cache:
+  backend: memcached
+  restoration:
+    batch_size: -5
+kafka:
+  brokers: ["k1:9092", ""]
+  processing_time: 0
+  batch_size: -1
+  retry:
+    randomization_factor: 2
+server:
+  port: "http"
+auth:
+  api_keys:
+    - token: ""
+      role: root
+    - id: ops
+      token: "a"
+      role: admin
+    - id: ops
+      token: "b"
+      role: viewer
+`)
+
+	_, err := LoadConfig(path)
+	require.Error(t, err)
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+
+	keys := make([]string, 0, len(verrs))
+	for _, fe := range verrs {
+		keys = append(keys, fe.Key)
+	}
+	assert.ElementsMatch(t, []string{
+		"cache.backend",
+		"cache.restoration.batch_size",
+		"database.dsn",
+		"kafka.brokers[1]",
+		"kafka.processing_time",
+		"kafka.batch_size",
+		"kafka.retry.randomization_factor",
+		"server.port",
+		"auth.api_keys[0].id",
+		"auth.api_keys[0].token",
+		"auth.api_keys[0].role",
+		"auth.api_keys[2].id",
+	}, keys)
+	assert.Contains(t, err.Error(), "database.dsn: is required")
+}
+
+func TestLoadConfig_RepositoryConfig(t *testing.T) {
+	t.Setenv("POSTGRES_DSN", "postgres://app:secret@db:5432/orders")
+
+	_, err := LoadConfig(filepath.Join("..", "..", "..", "config.yml"))
+	assert.NoError(t, err)
+}
+
+func TestPrintConfig_RedactsSecrets(t *testing.T) {
+	path := writeConfig(t, `
+storage: postgres
+database:
+  dsn: "postgres://app:secret@db:5432/orders?sslmode=disable"
+  replicas:
+    dsns: ["host=replica user=app password=hunter2 dbname=orders"]
+cache:
+  redis:
+    password: "redis-secret"
+auth:
+  api_keys:
+    - id: ops
+      token: "api-secret"
+      role: operator
+`)
+
+	var buf bytes.Buffer
+	require.NoError(t, PrintConfig(path, &buf))
+
+	out := buf.String()
+	for _, secret := range []string{"secret@", "hunter2", "redis-secret", "api-secret"} {
+		assert.NotContains(t, out, secret)
+	}
+	assert.Contains(t, out, "postgres://app:xxxxx@db:5432/orders?sslmode=disable")
+	assert.Contains(t, out, "password=xxxxx")
+	assert.Contains(t, out, "role: operator")
+	assert.Contains(t, out, "request_timeout: 30s", "durations are printed in Go notation")
+}
+
+func TestPrintConfig_InvalidConfigStillPrinted(t *testing.T) {
+	path := writeConfig(t, "storage: postgres\n")
+
+	var buf bytes.Buffer
+	err := PrintConfig(path, &buf)
+
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Contains(t, buf.String(), "storage: postgres")
+}
@@ -0,0 +1,119 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config/defaults.go
+package config
+
+import "time"
+
+// defaults - значения ключей, не заданных ни в файле, ни в окружении. Ключ должен быть здесь,
+// чтобы его можно было переопределить переменной окружения (cache.redis.addr -> CACHE_REDIS_ADDR)
+var defaults = map[string]any{
+	"storage": "postgres",
+
+	"cache.backend":                     "memory",
+	"cache.capacity":                    10000,
+	"cache.shards":                      16,
+	"cache.policy":                      "lru",
+	"cache.get_all_limit":               1000,
+	"cache.ttl":                         time.Hour,
+	"cache.sweep_interval":              time.Minute,
+	"cache.max_bytes":                   256 << 20,
+	"cache.negative_ttl":                5 * time.Second,
+	"cache.restoration.timeout":         5 * time.Minute,
+	"cache.restoration.batch_size":      1000,
+	"cache.restoration.max_retries":     3,
+	"cache.restoration.retry_interval":  time.Second,
+	"cache.snapshot.path":               "",
+	"cache.invalidation.backend":        "none",
+	"cache.invalidation.channel":        "order_cache_invalidation",
+	"cache.invalidation.instance_id":    "",
+	"cache.redis.addr":                  "localhost:6379",
+	"cache.redis.password":              "",
+	"cache.redis.db":                    0,
+	"cache.redis.key_prefix":            "orderservice:cache:",
+	"cache.redis.capacity":              1000000,
+	"cache.redis.ttl":                   24 * time.Hour,
+	"cache.redis.timeout":               200 * time.Millisecond,
+	"cache.consistency.mode":            "cache",
+	"cache.consistency.verify_interval": time.Duration(0),
+	"cache.consistency.verify_sample":   100,
+	"cache.consistency.repair":          true,
+
+	"sqlite.path":         "data/orders.db",
+	"sqlite.busy_timeout": 5 * time.Second,
+
+	"database.dsn":                                "",
+	"database.max_open_conns":                     50,
+	"database.max_idle_conns":                     25,
+	"database.conn_max_lifetime":                  30 * time.Minute,
+	"database.statement_timeout":                  30 * time.Second,
+	"database.idle_in_tx_session_timeout":         10 * time.Second,
+	"database.layout":                             "normalized",
+	"database.replicas.dsns":                      []string{},
+	"database.replicas.health_check_interval":     5 * time.Second,
+	"database.replicas.read_your_writes":          2 * time.Second,
+	"database.archive.enabled":                    false,
+	"database.archive.mode":                       "table",
+	"database.archive.dir":                        "/var/lib/orderservice/archive",
+	"database.archive.max_age":                    365 * 24 * time.Hour,
+	"database.archive.interval":                   24 * time.Hour,
+	"database.circuit_breaker.enabled":            true,
+	"database.circuit_breaker.failure_threshold":  5,
+	"database.circuit_breaker.open_timeout":       30 * time.Second,
+	"database.circuit_breaker.half_open_requests": 1,
+	"database.retry.initial_interval":             100 * time.Millisecond,
+	"database.retry.multiplier":                   2.0,
+	"database.retry.max_interval":                 2 * time.Second,
+	"database.retry.max_elapsed_time":             10 * time.Second,
+	"database.retry.randomization_factor":         0.5,
+	// нули в reads и writes - наследовать общую политику database.retry
+	"database.retry.reads.initial_interval":      time.Duration(0),
+	"database.retry.reads.multiplier":            0.0,
+	"database.retry.reads.max_interval":          time.Duration(0),
+	"database.retry.reads.max_elapsed_time":      time.Duration(0),
+	"database.retry.reads.randomization_factor":  0.0,
+	"database.retry.writes.initial_interval":     time.Duration(0),
+	"database.retry.writes.multiplier":           0.0,
+	"database.retry.writes.max_interval":         time.Duration(0),
+	"database.retry.writes.max_elapsed_time":     time.Duration(0),
+	"database.retry.writes.randomization_factor": 0.0,
+
+	"kafka.brokers":                                []string{},
+	"kafka.topic":                                  "orders",
+	"kafka.group_id":                               "orderservice",
+	"kafka.dlq_topic":                              "orders-dlq",
+	"kafka.max_retries":                            5,
+	"kafka.processing_time":                        60 * time.Second,
+	"kafka.min_bytes":                              10000,
+	"kafka.max_bytes":                              10000000,
+	"kafka.max_wait":                               time.Second,
+	"kafka.commit_interval":                        time.Second,
+	"kafka.batch_timeout":                          100 * time.Millisecond,
+	"kafka.batch_size":                             1,
+	"kafka.retry.initial_interval":                 time.Second,
+	"kafka.retry.multiplier":                       2.0,
+	"kafka.retry.max_interval":                     30 * time.Second,
+	"kafka.retry.max_elapsed_time":                 5 * time.Minute,
+	"kafka.retry.randomization_factor":             0.5,
+	"kafka.dlq_circuit_breaker.enabled":            true,
+	"kafka.dlq_circuit_breaker.failure_threshold":  3,
+	"kafka.dlq_circuit_breaker.open_timeout":       30 * time.Second,
+	"kafka.dlq_circuit_breaker.half_open_requests": 1,
+
+	"server.port":            "8081",
+	"server.request_timeout": 30 * time.Second,
+
+	"currency.reporting_currency": "",
+	"currency.rates_file":         "",
+
+	"pii.key_file":            "",
+	"pii.rotate_on_start":     false,
+	"pii.rotation_batch_size": 500,
+
+	"auth.default_role": "viewer",
+
+	"migrations.migrations_path":            "/app/internal/infrastructure/database/migrations",
+	"migrations.retry.initial_interval":     time.Second,
+	"migrations.retry.multiplier":           2.0,
+	"migrations.retry.max_interval":         30 * time.Second,
+	"migrations.retry.max_elapsed_time":     5 * time.Minute,
+	"migrations.retry.randomization_factor": 0.5,
+}
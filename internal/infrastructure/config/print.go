@@ -0,0 +1,115 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config/print.go
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// redacted совпадает с заменой пароля в url.URL.Redacted
+const redacted = "xxxxx"
+
+// dsnPassword - пароль в DSN вида "host=... password=..."
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// PrintConfig пишет в w итоговую конфигурацию в YAML: файл поверх значений по умолчанию и окружение.
+// Пароли, токены API и пароли в DSN заменяются на xxxxx. Ошибки проверки возвращаются после вывода,
+// чтобы неверный конфиг тоже можно было посмотреть
+func PrintConfig(path string, w io.Writer) error {
+	cfg, v, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	out := viper.New()
+	out.SetConfigType("yaml")
+	for _, key := range v.AllKeys() {
+		out.Set(key, redact(key, v.Get(key)))
+	}
+	if err := out.WriteConfigTo(w); err != nil {
+		return fmt.Errorf("failed to print config: %w", err)
+	}
+	return cfg.Validate()
+}
+
+func redact(key string, value any) any {
+	switch key {
+	case "cache.redis.password":
+		if s, ok := value.(string); ok && s != "" {
+			return redacted
+		}
+	case "database.dsn":
+		if s, ok := value.(string); ok {
+			return redactDSN(s)
+		}
+	case "database.replicas.dsns":
+		return redactDSNs(value)
+	case "auth.api_keys":
+		return redactAPIKeys(value)
+	}
+	// длительности из значений по умолчанию иначе попадут в YAML наносекундами
+	if d, ok := value.(time.Duration); ok {
+		return d.String()
+	}
+	return value
+}
+
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
+
+func redactDSNs(value any) any {
+	switch dsns := value.(type) {
+	case string:
+		return redactDSN(dsns)
+	case []string:
+		out := make([]string, 0, len(dsns))
+		for _, dsn := range dsns {
+			out = append(out, redactDSN(dsn))
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(dsns))
+		for _, dsn := range dsns {
+			if s, ok := dsn.(string); ok {
+				out = append(out, redactDSN(s))
+				continue
+			}
+			out = append(out, dsn)
+		}
+		return out
+	}
+	return value
+}
+
+func redactAPIKeys(value any) any {
+	keys, ok := value.([]any)
+	if !ok {
+		return value
+	}
+	out := make([]any, 0, len(keys))
+	for _, k := range keys {
+		m, ok := k.(map[string]any)
+		if !ok {
+			out = append(out, k)
+			continue
+		}
+		copied := make(map[string]any, len(m))
+		for field, v := range m {
+			copied[field] = v
+		}
+		if _, ok := copied["token"]; ok {
+			copied["token"] = redacted
+		}
+		out = append(out, copied)
+	}
+	return out
+}
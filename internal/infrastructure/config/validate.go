@@ -0,0 +1,270 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/config/validate.go
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError - неверное или отсутствующее значение ключа конфигурации
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// ValidationErrors собирает все ошибки конфигурации сразу, а не только первую
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, fe := range v {
+		msgs = append(msgs, fe.Error())
+	}
+	return "invalid config:\n  " + strings.Join(msgs, "\n  ")
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(key, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(key, "is required")
+	}
+}
+
+// oneOf: пустое значение проверяет required, если ключ обязателен
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) nonNegative(key string, value int64) {
+	if value < 0 {
+		v.add(key, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validator) positive(key string, value int64) {
+	if value <= 0 {
+		v.add(key, "must be positive, got %d", value)
+	}
+}
+
+func (v *validator) nonNegativeDuration(key string, d time.Duration) {
+	if d < 0 {
+		v.add(key, "must not be negative, got %s", d)
+	}
+}
+
+func (v *validator) positiveDuration(key string, d time.Duration) {
+	if d <= 0 {
+		v.add(key, "must be positive, got %s", d)
+	}
+}
+
+func (v *validator) retry(prefix string, r RetryConfig) {
+	v.nonNegativeDuration(prefix+".max_elapsed_time", r.MaxElapsedTime)
+	v.nonNegativeDuration(prefix+".initial_interval", r.InitialInterval)
+	v.nonNegativeDuration(prefix+".max_interval", r.MaxInterval)
+	if r.RandomizationFactor < 0 || r.RandomizationFactor > 1 {
+		v.add(prefix+".randomization_factor", "must be between 0 and 1, got %g", r.RandomizationFactor)
+	}
+	if r.Multiplier != 0 && r.Multiplier < 1 {
+		v.add(prefix+".multiplier", "must be at least 1, got %g", r.Multiplier)
+	}
+}
+
+func (v *validator) breaker(prefix string, b CircuitBreakerConfig) {
+	v.nonNegative(prefix+".failure_threshold", int64(b.FailureThreshold))
+	v.nonNegativeDuration(prefix+".open_timeout", b.OpenTimeout)
+	v.nonNegative(prefix+".half_open_requests", int64(b.HalfOpenRequests))
+}
+
+// Validate проверяет значения, которые иначе сломались бы только во время работы
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.oneOf("storage", c.Storage, "postgres", "sqlite", "memory")
+	usesPostgres := c.Storage == "" || c.Storage == "postgres"
+
+	c.validateCache(v, usesPostgres)
+	c.validateDatabase(v, usesPostgres)
+	c.validateKafka(v)
+
+	if c.Storage == "sqlite" {
+		v.required("sqlite.path", c.SQLite.Path)
+	}
+	v.nonNegativeDuration("sqlite.busy_timeout", c.SQLite.BusyTimeout)
+
+	v.required("server.port", c.Server.Port)
+	if c.Server.Port != "" {
+		if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+			v.add("server.port", "must be a port number between 1 and 65535, got %q", c.Server.Port)
+		}
+	}
+	v.nonNegativeDuration("server.request_timeout", c.Server.RequestTimeout)
+
+	if usesPostgres {
+		v.required("migrations.migrations_path", c.Migrations.MigrationsPath)
+	}
+	v.retry("migrations.retry", c.Migrations.Retry)
+
+	rules := make([]string, 0, len(c.Consistency.Rules))
+	for rule := range c.Consistency.Rules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		v.oneOf("consistency.rules."+rule, c.Consistency.Rules[rule], "off", "reject", "warn", "flag")
+	}
+
+	v.nonNegative("pii.rotation_batch_size", int64(c.PII.RotationBatchSize))
+
+	// роли сравниваются без учета регистра, как в auth.ParseRole
+	v.oneOf("auth.default_role", strings.ToLower(strings.TrimSpace(c.Auth.DefaultRole)), "viewer", "operator", "admin")
+	// id ключа записывается в журнал удалений данных клиента, поэтому должен однозначно его называть
+	keyIDs := make(map[string]int, len(c.Auth.APIKeys))
+	for i, key := range c.Auth.APIKeys {
+		prefix := fmt.Sprintf("auth.api_keys[%d]", i)
+		v.required(prefix+".id", key.ID)
+		if first, ok := keyIDs[key.ID]; ok && key.ID != "" {
+			v.add(prefix+".id", "duplicates auth.api_keys[%d].id %q", first, key.ID)
+		} else {
+			keyIDs[key.ID] = i
+		}
+		v.required(prefix+".token", key.Token)
+		v.required(prefix+".role", key.Role)
+		v.oneOf(prefix+".role", strings.ToLower(strings.TrimSpace(key.Role)), "viewer", "operator", "admin")
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+func (c *Config) validateCache(v *validator, usesPostgres bool) {
+	cache := c.Cache
+	v.oneOf("cache.backend", cache.Backend, "memory", "redis", "tiered")
+	v.oneOf("cache.policy", cache.Policy, "lru", "lfu", "arc", "w-tinylfu")
+	v.nonNegative("cache.capacity", int64(cache.Capacity))
+	v.nonNegative("cache.shards", int64(cache.Shards))
+	v.nonNegative("cache.get_all_limit", int64(cache.GetAllLimit))
+	v.nonNegative("cache.max_bytes", cache.MaxBytes)
+	v.nonNegativeDuration("cache.ttl", cache.TTL)
+	v.nonNegativeDuration("cache.sweep_interval", cache.SweepInterval)
+	v.nonNegativeDuration("cache.negative_ttl", cache.NegativeTTL)
+
+	v.nonNegativeDuration("cache.restoration.timeout", cache.Restoration.Timeout)
+	v.positive("cache.restoration.batch_size", int64(cache.Restoration.BatchSize))
+	v.nonNegative("cache.restoration.max_retries", int64(cache.Restoration.MaxRetries))
+	v.nonNegativeDuration("cache.restoration.retry_interval", cache.Restoration.RetryInterval)
+
+	inv := cache.Invalidation
+	v.oneOf("cache.invalidation.backend", inv.Backend, "none", "postgres", "kafka")
+	switch inv.Backend {
+	case "postgres":
+		if !usesPostgres {
+			v.add("cache.invalidation.backend", "postgres requires storage postgres")
+		}
+		v.required("cache.invalidation.channel", inv.Channel)
+	case "kafka":
+		if len(c.Kafka.Brokers) == 0 {
+			v.add("cache.invalidation.backend", "kafka requires kafka.brokers")
+		}
+		v.required("cache.invalidation.channel", inv.Channel)
+	}
+
+	if cache.Backend == "redis" || cache.Backend == "tiered" {
+		v.required("cache.redis.addr", cache.Redis.Addr)
+	}
+	v.nonNegative("cache.redis.db", int64(cache.Redis.DB))
+	v.nonNegative("cache.redis.capacity", int64(cache.Redis.Capacity))
+	v.nonNegativeDuration("cache.redis.ttl", cache.Redis.TTL)
+	v.nonNegativeDuration("cache.redis.timeout", cache.Redis.Timeout)
+
+	v.oneOf("cache.consistency.mode", cache.Consistency.Mode, "cache", "strict")
+	v.nonNegativeDuration("cache.consistency.verify_interval", cache.Consistency.VerifyInterval)
+	v.nonNegative("cache.consistency.verify_sample", int64(cache.Consistency.VerifySample))
+}
+
+func (c *Config) validateDatabase(v *validator, usesPostgres bool) {
+	db := c.Database
+	if usesPostgres {
+		v.required("database.dsn", db.DSN)
+	}
+	v.nonNegative("database.max_open_conns", int64(db.MaxOpenConns))
+	v.nonNegative("database.max_idle_conns", int64(db.MaxIdleConns))
+	if db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns {
+		v.add("database.max_idle_conns", "must not exceed max_open_conns (%d), got %d", db.MaxOpenConns, db.MaxIdleConns)
+	}
+	v.nonNegativeDuration("database.conn_max_lifetime", db.ConnMaxLifetime)
+	v.nonNegativeDuration("database.statement_timeout", db.StatementTimeout)
+	v.nonNegativeDuration("database.idle_in_tx_session_timeout", db.IdleInTxSessionTimeout)
+	v.oneOf("database.layout", db.Layout, "normalized", "document")
+
+	for i, dsn := range db.Replicas.DSNs {
+		v.required(fmt.Sprintf("database.replicas.dsns[%d]", i), dsn)
+	}
+	v.nonNegativeDuration("database.replicas.health_check_interval", db.Replicas.HealthCheckInterval)
+	v.nonNegativeDuration("database.replicas.read_your_writes", db.Replicas.ReadYourWrites)
+
+	v.oneOf("database.archive.mode", db.Archive.Mode, "table", "file")
+	if db.Archive.Enabled && db.Archive.Mode == "file" {
+		v.required("database.archive.dir", db.Archive.Dir)
+	}
+	v.nonNegativeDuration("database.archive.max_age", db.Archive.MaxAge)
+	v.nonNegativeDuration("database.archive.interval", db.Archive.Interval)
+
+	v.breaker("database.circuit_breaker", db.CircuitBreaker)
+	v.retry("database.retry", db.Retry.RetryConfig)
+	v.retry("database.retry.reads", db.Retry.Reads)
+	v.retry("database.retry.writes", db.Retry.Writes)
+}
+
+// validateKafka: пустой список брокеров выключает консьюмер, остальные ключи тогда не нужны
+func (c *Config) validateKafka(v *validator) {
+	k := c.Kafka
+	for i, broker := range k.Brokers {
+		v.required(fmt.Sprintf("kafka.brokers[%d]", i), broker)
+	}
+	if len(k.Brokers) == 0 {
+		return
+	}
+
+	v.required("kafka.topic", k.Topic)
+	v.required("kafka.group_id", k.GroupID)
+	v.required("kafka.dlq_topic", k.DLQTopic)
+	v.nonNegative("kafka.max_retries", int64(k.MaxRetries))
+	v.positiveDuration("kafka.processing_time", k.ProcessingTime)
+	v.nonNegative("kafka.min_bytes", int64(k.MinBytes))
+	v.nonNegative("kafka.max_bytes", int64(k.MaxBytes))
+	if k.MaxBytes > 0 && k.MinBytes > k.MaxBytes {
+		v.add("kafka.min_bytes", "must not exceed max_bytes (%d), got %d", k.MaxBytes, k.MinBytes)
+	}
+	v.nonNegativeDuration("kafka.max_wait", k.MaxWait)
+	v.nonNegativeDuration("kafka.commit_interval", k.CommitInterval)
+	v.nonNegativeDuration("kafka.batch_timeout", k.BatchTimeout)
+	v.nonNegative("kafka.batch_size", int64(k.BatchSize))
+	v.retry("kafka.retry", k.Retry)
+	v.breaker("kafka.dlq_circuit_breaker", k.DLQCircuitBreaker)
+}
@@ -0,0 +1,13 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/crypto/errors.go
+package crypto
+
+import "errors"
+
+var (
+	ErrKeyringRead      = errors.New("failed to read keyring file")
+	ErrKeyringFormat    = errors.New("invalid keyring file")
+	ErrUnknownKey       = errors.New("unknown encryption key")
+	ErrCiphertextFormat = errors.New("invalid ciphertext format")
+	ErrDecryptFailed    = errors.New("failed to decrypt value")
+	ErrEncryptFailed    = errors.New("failed to encrypt value")
+)
@@ -0,0 +1,239 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/crypto/keyring.go
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Envelope encryption: каждое значение шифруется собственным случайным ключом (DEK),
+// а DEK - ключом из keyring (KEK). Ротация KEK требует только перешифровать DEK.
+//
+// Формат значения: enc:v1:<kid>:<base64(wrapped DEK)>:<base64(nonce|ciphertext)>
+const ciphertextPrefix = "enc:v1:"
+
+const keySize = 32
+
+// формат файла: {"active": "2025-01", "keys": {"2024-06": "<base64>", "2025-01": "<base64>"}},
+// ключи - 32 байта в base64; старые ключи остаются для расшифровки
+type keyringFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyringRead, err)
+	}
+
+	var f keyringFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyringFormat, err)
+	}
+
+	keys := make(map[string][]byte, len(f.Keys))
+	for id, encoded := range f.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", ErrKeyringFormat, id, err)
+		}
+		keys[id] = key
+	}
+
+	return NewKeyring(f.Active, keys)
+}
+
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{
+		active: active,
+		keys:   make(map[string]cipher.AEAD, len(keys)),
+	}
+
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: key id %q must be non-empty and must not contain ':'", ErrKeyringFormat, id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("%w: key %q must be %d bytes", ErrKeyringFormat, id, keySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", ErrKeyringFormat, id, err)
+		}
+		k.keys[id] = aead
+	}
+
+	if _, ok := k.keys[active]; !ok {
+		return nil, fmt.Errorf("%w: active key %q is not in keyring", ErrKeyringFormat, active)
+	}
+
+	return k, nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrEncryptFailed, err)
+	}
+
+	wrapped, err := seal(k.keys[k.active], dek, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+
+	dataAEAD, err := newAEAD(dek)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrEncryptFailed, err)
+	}
+	body, err := seal(dataAEAD, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return ciphertextPrefix + k.active + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(body), nil
+}
+
+// Decrypt возвращает значение как есть, если оно не зашифровано:
+// строки, записанные до включения шифрования, остаются читаемыми
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	kid, wrapped, body, err := parseCiphertext(value)
+	if err != nil {
+		return "", err
+	}
+
+	dek, err := k.unwrap(kid, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	dataAEAD, err := newAEAD(dek)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+	}
+	plaintext, err := open(dataAEAD, body, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation - значение не зашифровано или зашифровано не активным ключом
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, ciphertextPrefix+k.active+":")
+}
+
+// Rotate перешифровывает DEK активным ключом, не трогая сами данные;
+// незашифрованное значение шифруется целиком
+func (k *Keyring) Rotate(value string) (string, error) {
+	if !k.NeedsRotation(value) {
+		return value, nil
+	}
+	if !IsEncrypted(value) {
+		return k.Encrypt(value)
+	}
+
+	kid, wrapped, body, err := parseCiphertext(value)
+	if err != nil {
+		return "", err
+	}
+
+	dek, err := k.unwrap(kid, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	rewrapped, err := seal(k.keys[k.active], dek, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+
+	return ciphertextPrefix + k.active + ":" +
+		base64.RawStdEncoding.EncodeToString(rewrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(body), nil
+}
+
+func (k *Keyring) unwrap(kid string, wrapped []byte) ([]byte, error) {
+	kek, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return open(kek, wrapped, []byte(kid))
+}
+
+func parseCiphertext(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrCiphertextFormat
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrCiphertextFormat, err)
+	}
+	body, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrCiphertextFormat, err)
+	}
+	return parts[0], wrapped, body, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEncryptFailed, err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrCiphertextFormat
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,95 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/crypto/keyring_test.go
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k, err := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	require.NoError(t, err)
+
+	enc, err := k.Encrypt("+79991231234")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(enc))
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:k1:"))
+	assert.NotContains(t, enc, "79991231234")
+
+	other, err := k.Encrypt("+79991231234")
+	require.NoError(t, err)
+	assert.NotEqual(t, enc, other, "each value must use its own DEK and nonce")
+
+	dec, err := k.Decrypt(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "+79991231234", dec)
+
+	plain, err := k.Decrypt("Test Testov")
+	require.NoError(t, err)
+	assert.Equal(t, "Test Testov", plain)
+}
+
+func TestKeyring_DecryptErrors(t *testing.T) {
+	k1, err := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	require.NoError(t, err)
+	k2, err := NewKeyring("k2", map[string][]byte{"k2": testKey(2)})
+	require.NoError(t, err)
+
+	enc, err := k1.Encrypt("test@gmail.com")
+	require.NoError(t, err)
+
+	_, err = k2.Decrypt(enc)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = k1.Decrypt("enc:v1:k1:broken")
+	assert.ErrorIs(t, err, ErrCiphertextFormat)
+
+	tampered := enc[:len(enc)-4] + "AAAA"
+	_, err = k1.Decrypt(tampered)
+	assert.Error(t, err)
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	old, err := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	require.NoError(t, err)
+	enc, err := old.Encrypt("Kiryat Mozkin 15")
+	require.NoError(t, err)
+
+	k, err := NewKeyring("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	require.NoError(t, err)
+
+	assert.True(t, k.NeedsRotation(enc))
+	assert.True(t, k.NeedsRotation("plaintext"))
+	assert.False(t, k.NeedsRotation(""))
+
+	rotated, err := k.Rotate(enc)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rotated, "enc:v1:k2:"))
+	assert.False(t, k.NeedsRotation(rotated))
+
+	dec, err := k.Decrypt(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, "Kiryat Mozkin 15", dec)
+
+	fromPlain, err := k.Rotate("plaintext")
+	require.NoError(t, err)
+	dec, err = k.Decrypt(fromPlain)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", dec)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	_, err := NewKeyring("missing", map[string][]byte{"k1": testKey(1)})
+	assert.ErrorIs(t, err, ErrKeyringFormat)
+
+	_, err = NewKeyring("k1", map[string][]byte{"k1": []byte("short")})
+	assert.ErrorIs(t, err, ErrKeyringFormat)
+}
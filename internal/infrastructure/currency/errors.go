@@ -0,0 +1,10 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/currency/errors.go
+package currency
+
+import "errors"
+
+var (
+	ErrRatesFileRead   = errors.New("failed to read currency rates file")
+	ErrRatesFileFormat = errors.New("invalid currency rates file")
+	ErrRateNotFound    = errors.New("currency rate not found")
+)
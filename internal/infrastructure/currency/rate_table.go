@@ -0,0 +1,111 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/currency/rate_table.go
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// формат файла: {"base": "USD", "rates": {"EUR": "0.92", "RUB": "92.5"}},
+// где rates[X] - сколько единиц X стоит одна единица base.
+// Курсы строками, чтобы не терять точность на float64
+type ratesFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+type RateTable struct {
+	base  entities.Currency
+	rates map[string]*big.Rat
+}
+
+func LoadRateTable(path string) (*RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRatesFileRead, err)
+	}
+
+	var f ratesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRatesFileFormat, err)
+	}
+
+	return NewRateTable(f.Base, f.Rates)
+}
+
+func NewRateTable(base string, rates map[string]string) (*RateTable, error) {
+	baseCurrency, err := entities.ParseCurrency(base)
+	if err != nil {
+		return nil, fmt.Errorf("%w: base: %v", ErrRatesFileFormat, err)
+	}
+
+	t := &RateTable{
+		base:  baseCurrency,
+		rates: map[string]*big.Rat{baseCurrency.Code(): big.NewRat(1, 1)},
+	}
+
+	for code, value := range rates {
+		c, err := entities.ParseCurrency(code)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRatesFileFormat, err)
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: rate for %s must be a positive number, got %q", ErrRatesFileFormat, code, value)
+		}
+		t.rates[c.Code()] = rate
+	}
+
+	return t, nil
+}
+
+func (t *RateTable) Base() entities.Currency {
+	return t.base
+}
+
+func (t *RateTable) Convert(m entities.Money, to entities.Currency) (entities.Money, error) {
+	from := m.Currency()
+	if from.Equal(to) {
+		return m, nil
+	}
+
+	fromRate, ok := t.rates[from.Code()]
+	if !ok {
+		return entities.Money{}, fmt.Errorf("%w: %s", ErrRateNotFound, from)
+	}
+	toRate, ok := t.rates[to.Code()]
+	if !ok {
+		return entities.Money{}, fmt.Errorf("%w: %s", ErrRateNotFound, to)
+	}
+
+	// minor(from) -> major(from) -> base -> major(to) -> minor(to)
+	v := new(big.Rat).SetInt64(m.Amount())
+	v.Quo(v, pow10(from.MinorUnits()))
+	v.Quo(v, fromRate)
+	v.Mul(v, toRate)
+	v.Mul(v, pow10(to.MinorUnits()))
+
+	return entities.NewMoney(roundHalfAwayFromZero(v), to), nil
+}
+
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+func roundHalfAwayFromZero(v *big.Rat) int64 {
+	num := new(big.Int).Abs(v.Num())
+	den := v.Denom()
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Mul(r, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
@@ -0,0 +1,72 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/currency/rate_table_test.go
+package currency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+func mustCurrency(t *testing.T, code string) entities.Currency {
+	c, err := entities.ParseCurrency(code)
+	require.NoError(t, err)
+	return c
+}
+
+func TestRateTable_Convert(t *testing.T) {
+	table, err := NewRateTable("USD", map[string]string{
+		"EUR": "0.9",
+		"RUB": "90",
+		"JPY": "150",
+	})
+	require.NoError(t, err)
+
+	usd, _ := entities.ParseMoney(1817, "USD")
+
+	eur, err := table.Convert(usd, mustCurrency(t, "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, "16.35 EUR", eur.String())
+
+	jpy, err := table.Convert(usd, mustCurrency(t, "JPY"))
+	require.NoError(t, err)
+	assert.Equal(t, "2726 JPY", jpy.String())
+
+	rub, _ := entities.ParseMoney(9000, "RUB")
+	back, err := table.Convert(rub, mustCurrency(t, "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.90 EUR", back.String())
+
+	same, err := table.Convert(usd, mustCurrency(t, "USD"))
+	require.NoError(t, err)
+	assert.True(t, same.Equal(usd))
+}
+
+func TestRateTable_MissingRate(t *testing.T) {
+	table, err := NewRateTable("USD", map[string]string{"EUR": "0.9"})
+	require.NoError(t, err)
+
+	kzt, _ := entities.ParseMoney(100, "KZT")
+	_, err = table.Convert(kzt, mustCurrency(t, "USD"))
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestLoadRateTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"base":"EUR","rates":{"USD":"1.1"}}`), 0o600))
+
+	table, err := LoadRateTable(path)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", table.Base().Code())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"base":"EUR","rates":{"USD":"-1"}}`), 0o600))
+	_, err = LoadRateTable(path)
+	assert.ErrorIs(t, err, ErrRatesFileFormat)
+
+	_, err = LoadRateTable(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, ErrRatesFileRead)
+}
@@ -0,0 +1,73 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/archive_lookup_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// ArchiveLookupOrderRepository ищет в архиве заказы, которых нет в рабочих таблицах;
+// списки, счетчики и запись архив не затрагивают
+type ArchiveLookupOrderRepository struct {
+	repo    domainrepo.OrderRepository
+	archive domainrepo.OrderArchive
+	logger  domainrepo.Logger
+}
+
+func NewArchiveLookupOrderRepository(repo domainrepo.OrderRepository, archive domainrepo.OrderArchive, logger domainrepo.Logger) *ArchiveLookupOrderRepository {
+	return &ArchiveLookupOrderRepository{
+		repo:    repo,
+		archive: archive,
+		logger:  logger,
+	}
+}
+
+func (r *ArchiveLookupOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	return r.repo.SaveOrder(ctx, order)
+}
+
+func (r *ArchiveLookupOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	order, err := r.repo.GetOrder(ctx, id)
+	if !errors.Is(err, domain.ErrOrderNotFound) {
+		return order, err
+	}
+
+	order, err = r.archive.GetArchivedOrder(ctx, id)
+	if err != nil {
+		return entities.Order{}, err
+	}
+	r.logger.Debug("order found in archive", "order_uid", id)
+	return order, nil
+}
+
+func (r *ArchiveLookupOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	return r.repo.GetAllOrders(ctx, limit, offset)
+}
+
+func (r *ArchiveLookupOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
+	return r.repo.GetOrdersCount(ctx)
+}
+
+func (r *ArchiveLookupOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	return r.repo.GetRecentCursor(ctx, n)
+}
+
+func (r *ArchiveLookupOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	return r.repo.GetOrdersAfter(ctx, after, limit)
+}
+
+func (r *ArchiveLookupOrderRepository) DeleteOrder(ctx context.Context, id string) error {
+	return r.repo.DeleteOrder(ctx, id)
+}
+
+func (r *ArchiveLookupOrderRepository) ClearOrders(ctx context.Context) error {
+	return r.repo.ClearOrders(ctx)
+}
+
+func (r *ArchiveLookupOrderRepository) Shutdown(ctx context.Context) error {
+	return r.repo.Shutdown(ctx)
+}
@@ -0,0 +1,167 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/archive_lookup_repository_test.go
+package repository
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
+)
+
+// fakeOrderArchive - архив в памяти: заказы отдаются с Archived, как у PostgresOrderArchive
+type fakeOrderArchive struct {
+	orders map[string]entities.Order
+	err    error
+	reads  int
+}
+
+func (a *fakeOrderArchive) ArchiveBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (a *fakeOrderArchive) GetArchivedOrder(ctx context.Context, id string) (entities.Order, error) {
+	a.reads++
+	if a.err != nil {
+		return entities.Order{}, a.err
+	}
+	order, ok := a.orders[id]
+	if !ok {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+	order.Archived = true
+	return order, nil
+}
+
+func TestArchiveLookupOrderRepository_Contract(t *testing.T) {
+	repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+		live := newMemoryRepo(t)
+		return NewArchiveLookupOrderRepository(live, &fakeOrderArchive{}, live.logger)
+	})
+}
+
+func TestArchiveLookupOrderRepository_FallsBackToArchive(t *testing.T) {
+	ctx := context.Background()
+	live := newMemoryRepo(t)
+	archived := repositorytest.NewOrder("old", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))
+	archive := &fakeOrderArchive{orders: map[string]entities.Order{"old": archived}}
+	repo := NewArchiveLookupOrderRepository(live, archive, live.logger)
+
+	fresh := repositorytest.NewOrder("fresh", time.Now())
+	require.NoError(t, repo.SaveOrder(ctx, fresh))
+
+	got, err := repo.GetOrder(ctx, "fresh")
+	require.NoError(t, err)
+	assert.False(t, got.Archived)
+	assert.Zero(t, archive.reads, "live orders must not touch the archive")
+
+	got, err = repo.GetOrder(ctx, "old")
+	require.NoError(t, err)
+	assert.True(t, got.Archived)
+	repositorytest.AssertOrderEqual(t, archived, got)
+
+	_, err = repo.GetOrder(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func TestArchiveLookupOrderRepository_ArchiveError(t *testing.T) {
+	live := newMemoryRepo(t)
+	archive := &fakeOrderArchive{err: errors.New("archive unavailable")}
+	repo := NewArchiveLookupOrderRepository(live, archive, live.logger)
+
+	_, err := repo.GetOrder(context.Background(), "missing")
+	assert.EqualError(t, err, "archive unavailable")
+}
+
+func TestFindArchivedOrder(t *testing.T) {
+	orders := []entities.Order{
+		repositorytest.NewOrder("a", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+		repositorytest.NewOrder("b", time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}
+	orders[1].Items = repositorytest.Items(orders[1].TrackNumber, 1, 50)
+	path := writeArchiveFile(t, orders)
+
+	got, err := findArchivedOrder(path, "b")
+	require.NoError(t, err)
+	repositorytest.AssertOrderEqual(t, orders[1], got)
+
+	_, err = findArchivedOrder(path, "c")
+	assert.Error(t, err)
+
+	_, err = findArchivedOrder(filepath.Join(t.TempDir(), "missing.ndjson.gz"), "a")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPartitionNames(t *testing.T) {
+	month := monthStart(time.Date(2024, 3, 31, 23, 0, 0, 0, time.FixedZone("MSK", 3*3600)))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), month)
+	assert.Equal(t, "items_p2024_03", partitionName("items", month))
+	assert.Equal(t, `"orders_p2024_03"`, monthPartitions(month).orders)
+}
+
+func TestEraseCustomerInFile(t *testing.T) {
+	created := time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC)
+	target := repositorytest.NewOrder("erase-1", created)
+	other := repositorytest.NewOrder("keep-1", created)
+	path := writeArchiveFile(t, []entities.Order{target, other})
+
+	var seen []string
+	err := eraseCustomerInFile(path, target.CustomerID, func(order entities.Order) {
+		seen = append(seen, order.OrderUID)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{target.OrderUID, other.OrderUID}, seen)
+
+	got, err := findArchivedOrder(path, target.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, target.Delivery.Erased(), got.Delivery)
+	assert.Equal(t, target.Delivery.City, got.Delivery.City)
+	assert.Equal(t, target.Payment, got.Payment)
+
+	got, err = findArchivedOrder(path, other.OrderUID)
+	require.NoError(t, err)
+	repositorytest.AssertOrderEqual(t, other, got)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file must not be left behind")
+}
+
+func TestEraseCustomerInFile_UnchangedFileKept(t *testing.T) {
+	order := repositorytest.NewOrder("keep-1", time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC))
+	path := writeArchiveFile(t, []entities.Order{order})
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+
+	require.NoError(t, eraseCustomerInFile(path, "someone-else", func(entities.Order) {}))
+
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(before, after), "file without the customer's orders must not be rewritten")
+}
+
+func writeArchiveFile(t *testing.T, orders []entities.Order) string {
+	path := filepath.Join(t.TempDir(), "orders-2023-01.ndjson.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	for _, order := range orders {
+		require.NoError(t, enc.Encode(order))
+	}
+	require.NoError(t, zw.Close())
+	return path
+}
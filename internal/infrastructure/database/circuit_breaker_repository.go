@@ -0,0 +1,96 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/circuit_breaker_repository.go
+package repository
+
+import (
+	"context"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker"
+)
+
+// CircuitBreakerOrderRepository пропускает вызовы к хранилищу через предохранитель:
+// пока он разомкнут, методы сразу возвращают domain.ErrCircuitOpen. Shutdown идет в обход
+type CircuitBreakerOrderRepository struct {
+	repo    domainrepo.OrderRepository
+	breaker *breaker.Breaker
+}
+
+func NewCircuitBreakerOrderRepository(repo domainrepo.OrderRepository, b *breaker.Breaker) *CircuitBreakerOrderRepository {
+	return &CircuitBreakerOrderRepository{
+		repo:    repo,
+		breaker: b,
+	}
+}
+
+func (r *CircuitBreakerOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	return r.breaker.Execute(func() error {
+		return r.repo.SaveOrder(ctx, order)
+	})
+}
+
+func (r *CircuitBreakerOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	var order entities.Order
+	err := r.breaker.Execute(func() error {
+		var err error
+		order, err = r.repo.GetOrder(ctx, id)
+		return err
+	})
+	return order, err
+}
+
+func (r *CircuitBreakerOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	var orders []entities.Order
+	err := r.breaker.Execute(func() error {
+		var err error
+		orders, err = r.repo.GetAllOrders(ctx, limit, offset)
+		return err
+	})
+	return orders, err
+}
+
+func (r *CircuitBreakerOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
+	var count int
+	err := r.breaker.Execute(func() error {
+		var err error
+		count, err = r.repo.GetOrdersCount(ctx)
+		return err
+	})
+	return count, err
+}
+
+func (r *CircuitBreakerOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	var cursor entities.OrderCursor
+	err := r.breaker.Execute(func() error {
+		var err error
+		cursor, err = r.repo.GetRecentCursor(ctx, n)
+		return err
+	})
+	return cursor, err
+}
+
+func (r *CircuitBreakerOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	var orders []entities.Order
+	err := r.breaker.Execute(func() error {
+		var err error
+		orders, err = r.repo.GetOrdersAfter(ctx, after, limit)
+		return err
+	})
+	return orders, err
+}
+
+func (r *CircuitBreakerOrderRepository) DeleteOrder(ctx context.Context, id string) error {
+	return r.breaker.Execute(func() error {
+		return r.repo.DeleteOrder(ctx, id)
+	})
+}
+
+func (r *CircuitBreakerOrderRepository) ClearOrders(ctx context.Context) error {
+	return r.breaker.Execute(func() error {
+		return r.repo.ClearOrders(ctx)
+	})
+}
+
+func (r *CircuitBreakerOrderRepository) Shutdown(ctx context.Context) error {
+	return r.repo.Shutdown(ctx)
+}
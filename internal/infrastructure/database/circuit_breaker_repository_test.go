@@ -0,0 +1,64 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/circuit_breaker_repository_test.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker"
+)
+
+func TestCircuitBreakerOrderRepository_Contract(t *testing.T) {
+	repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+		live := newMemoryRepo(t)
+		// как в factory.NewStorageBreaker: конфликты данных не считаются сбоями хранилища
+		b := breaker.New("storage", breaker.Config{FailureThreshold: 1}, live.logger, domain.ErrOrderNotFound, domain.ErrInvalidOrder)
+		return NewCircuitBreakerOrderRepository(live, b)
+	})
+}
+
+func TestCircuitBreakerOrderRepository_NotFoundKeepsClosed(t *testing.T) {
+	live := newMemoryRepo(t)
+	b := breaker.New("storage", breaker.Config{FailureThreshold: 1}, live.logger, domain.ErrOrderNotFound)
+	repo := NewCircuitBreakerOrderRepository(live, b)
+
+	_, err := repo.GetOrder(context.Background(), "missing")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.Equal(t, entities.CircuitClosed, b.Stats().State)
+}
+
+func TestCircuitBreakerOrderRepository_StopsRetries(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	live := newMemoryRepo(t)
+	b := breaker.New("storage", breaker.Config{FailureThreshold: 3, OpenTimeout: time.Minute}, live.logger)
+
+	repo := NewRetryingOrderRepository(NewCircuitBreakerOrderRepository(mockRepo, b), live.logger, &RetryConfig{
+		MaxElapsedTime:  time.Minute,
+		InitialInterval: time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     time.Millisecond,
+	})
+
+	testOrder := entities.Order{OrderUID: "test123"}
+	mockRepo.On("SaveOrder", mock.Anything, testOrder).Return(errors.New("connection refused"))
+
+	start := time.Now()
+	err := repo.SaveOrder(context.Background(), testOrder)
+	assert.ErrorIs(t, err, domain.ErrCircuitOpen)
+	assert.Less(t, time.Since(start), time.Second, "retries must stop once the breaker opens")
+	mockRepo.AssertNumberOfCalls(t, "SaveOrder", 3)
+
+	// следующие вызовы отклоняются, не доходя до хранилища
+	_, err = repo.GetOrder(context.Background(), "test123")
+	assert.ErrorIs(t, err, domain.ErrCircuitOpen)
+	mockRepo.AssertNotCalled(t, "GetOrder", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,136 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/delivery_key_rotator.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// DeliveryKeyRotator перешифровывает PII доставки активным ключом:
+// строки со старым ключом и строки, записанные до включения шифрования
+type DeliveryKeyRotator struct {
+	db        *sqlx.DB
+	cipher    domainrepo.FieldCipher
+	logger    domainrepo.Logger
+	batchSize int
+	// selectQuery и updateQuery читают и пишут PII доставки: таблица delivery или документы
+	selectQuery string
+	updateQuery string
+}
+
+func NewDeliveryKeyRotator(db *sqlx.DB, cipher domainrepo.FieldCipher, logger domainrepo.Logger, batchSize int) *DeliveryKeyRotator {
+	return newDeliveryKeyRotator(db, cipher, logger, batchSize, `
+		SELECT order_uid, name, phone, zip, city, address, region, COALESCE(email, '') AS email
+		FROM delivery
+		WHERE order_uid > $1
+		ORDER BY order_uid
+		LIMIT $2
+	`, "UPDATE delivery SET name = $2, phone = $3, address = $4, email = $5 WHERE order_uid = $1")
+}
+
+// NewDocumentDeliveryKeyRotator перешифровывает доставку внутри документов order_documents
+func NewDocumentDeliveryKeyRotator(db *sqlx.DB, cipher domainrepo.FieldCipher, logger domainrepo.Logger, batchSize int) *DeliveryKeyRotator {
+	return newDeliveryKeyRotator(db, cipher, logger, batchSize, `
+		SELECT
+			order_uid,
+			COALESCE(document -> 'delivery' ->> 'name', '') AS name,
+			COALESCE(document -> 'delivery' ->> 'phone', '') AS phone,
+			COALESCE(document -> 'delivery' ->> 'address', '') AS address,
+			COALESCE(document -> 'delivery' ->> 'email', '') AS email
+		FROM order_documents
+		WHERE order_uid > $1
+		ORDER BY order_uid
+		LIMIT $2
+	`, `
+		UPDATE order_documents
+		SET document = jsonb_set(document, '{delivery}', COALESCE(document -> 'delivery', '{}'::jsonb) ||
+			jsonb_build_object('name', $2::text, 'phone', $3::text, 'address', $4::text, 'email', $5::text))
+		WHERE order_uid = $1
+	`)
+}
+
+func newDeliveryKeyRotator(db *sqlx.DB, cipher domainrepo.FieldCipher, logger domainrepo.Logger, batchSize int, selectQuery, updateQuery string) *DeliveryKeyRotator {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &DeliveryKeyRotator{
+		db:          db,
+		cipher:      cipher,
+		logger:      logger,
+		batchSize:   batchSize,
+		selectQuery: selectQuery,
+		updateQuery: updateQuery,
+	}
+}
+
+type deliveryRow struct {
+	OrderUID string `db:"order_uid"`
+	entities.Delivery
+}
+
+func (r *DeliveryKeyRotator) Rotate(ctx context.Context) (int, error) {
+	var rotated int
+	lastUID := ""
+
+	for {
+		var rows []deliveryRow
+		if err := r.db.SelectContext(ctx, &rows, r.selectQuery, lastUID, r.batchSize); err != nil {
+			r.logger.Error("failed to read delivery batch for key rotation", "error", err, "after", lastUID)
+			return rotated, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			ok, err := r.rotateRow(ctx, row)
+			if err != nil {
+				return rotated, err
+			}
+			if ok {
+				rotated++
+			}
+		}
+
+		lastUID = rows[len(rows)-1].OrderUID
+		r.logger.Debug("delivery key rotation batch processed", "rotated", rotated, "last_order_uid", lastUID)
+	}
+
+	r.logger.Info("delivery key rotation completed", "rotated", rotated)
+	return rotated, nil
+}
+
+func (r *DeliveryKeyRotator) rotateRow(ctx context.Context, row deliveryRow) (bool, error) {
+	needs := false
+	for _, f := range []string{row.Name, row.Phone, row.Address, row.Email} {
+		if r.cipher.NeedsRotation(f) {
+			needs = true
+			break
+		}
+	}
+	if !needs {
+		return false, nil
+	}
+
+	delivery, err := row.Delivery.TransformPII(func(field, value string) (string, error) {
+		return r.cipher.Rotate(value)
+	})
+	if err != nil {
+		r.logger.Error("failed to rotate delivery key", "error", err, "order_uid", row.OrderUID)
+		return false, fmt.Errorf("%w: %v", ErrUpdateFailed, err)
+	}
+
+	_, err = r.db.ExecContext(ctx, r.updateQuery,
+		row.OrderUID, delivery.Name, delivery.Phone, delivery.Address, delivery.Email,
+	)
+	if err != nil {
+		r.logger.Error("failed to update rotated delivery", "error", err, "order_uid", row.OrderUID)
+		return false, fmt.Errorf("%w: %v", ErrUpdateFailed, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,112 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/encrypting_repository.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// EncryptingOrderRepository шифрует PII-поля доставки перед записью
+// и расшифровывает после чтения; остальные поля проходят без изменений
+type EncryptingOrderRepository struct {
+	repo   domainrepo.OrderRepository
+	cipher domainrepo.FieldCipher
+	logger domainrepo.Logger
+}
+
+func NewEncryptingOrderRepository(repo domainrepo.OrderRepository, cipher domainrepo.FieldCipher, logger domainrepo.Logger) *EncryptingOrderRepository {
+	return &EncryptingOrderRepository{
+		repo:   repo,
+		cipher: cipher,
+		logger: logger,
+	}
+}
+
+func (r *EncryptingOrderRepository) encrypt(order entities.Order) (entities.Order, error) {
+	delivery, err := order.Delivery.TransformPII(func(field, value string) (string, error) {
+		return r.cipher.Encrypt(value)
+	})
+	if err != nil {
+		r.logger.Error("failed to encrypt delivery", "order_uid", order.OrderUID, "error", err)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+	order.Delivery = delivery
+	return order, nil
+}
+
+func (r *EncryptingOrderRepository) decrypt(order entities.Order) (entities.Order, error) {
+	delivery, err := order.Delivery.TransformPII(func(field, value string) (string, error) {
+		return r.cipher.Decrypt(value)
+	})
+	if err != nil {
+		r.logger.Error("failed to decrypt delivery", "order_uid", order.OrderUID, "error", err)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	order.Delivery = delivery
+	return order, nil
+}
+
+func (r *EncryptingOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	encrypted, err := r.encrypt(order)
+	if err != nil {
+		return err
+	}
+	return r.repo.SaveOrder(ctx, encrypted)
+}
+
+func (r *EncryptingOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	order, err := r.repo.GetOrder(ctx, id)
+	if err != nil {
+		return entities.Order{}, err
+	}
+	return r.decrypt(order)
+}
+
+func (r *EncryptingOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	orders, err := r.repo.GetAllOrders(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		if orders[i], err = r.decrypt(orders[i]); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+func (r *EncryptingOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	return r.repo.GetRecentCursor(ctx, n)
+}
+
+func (r *EncryptingOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	orders, err := r.repo.GetOrdersAfter(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		if orders[i], err = r.decrypt(orders[i]); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+func (r *EncryptingOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
+	return r.repo.GetOrdersCount(ctx)
+}
+
+func (r *EncryptingOrderRepository) DeleteOrder(ctx context.Context, id string) error {
+	return r.repo.DeleteOrder(ctx, id)
+}
+
+func (r *EncryptingOrderRepository) ClearOrders(ctx context.Context) error {
+	return r.repo.ClearOrders(ctx)
+}
+
+func (r *EncryptingOrderRepository) Shutdown(ctx context.Context) error {
+	return r.repo.Shutdown(ctx)
+}
@@ -1,7 +1,13 @@
 // github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/errors.go
 package repository
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
 
 var (
 	ErrDatabaseConnectionFailed = errors.New("failed to connect to database")
@@ -15,4 +21,28 @@ var (
 	ErrOrderSaveFailed          = errors.New("failed to save order")
 	ErrOrderDeleteFailed        = errors.New("failed to delete order")
 	ErrOrderClearFailed         = errors.New("failed to clear orders")
+	ErrCacheEventPublish        = errors.New("failed to publish cache event")
+	ErrCacheBusListen           = errors.New("failed to listen for cache events")
+	ErrArchiveFailed            = errors.New("failed to archive orders")
 )
+
+// orderConflict - заказ противоречит уже сохраненным данным. Это ошибка данных, а не сбой
+// хранилища: API отвечает 422, консьюмер отправляет сообщение в DLQ, повторов нет
+func orderConflict(field string, err error) error {
+	return fmt.Errorf("%w: %w", domain.ErrInvalidOrder, entities.ValidationErrors{
+		{Field: field, Message: err.Error(), Err: err},
+	})
+}
+
+// checkItemKeys - ключ товаров (chrt_id, order_uid) из схемы. Проверяется до записи во всех
+// хранилищах, чтобы повтор chrt_id был конфликтом данных, а не сбоем вставки, который повторяют
+func checkItemKeys(order entities.Order) error {
+	seen := make(map[int]struct{}, len(order.Items))
+	for i, item := range order.Items {
+		if _, ok := seen[item.ChrtID]; ok {
+			return orderConflict(fmt.Sprintf("items[%d].chrt_id", i), entities.ErrDuplicateChrtID)
+		}
+		seen[item.ChrtID] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/memory_repository.go
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// MemoryOrderRepository хранит заказы в памяти процесса с той же семантикой, что и Postgres:
+// upsert с заменой товаров, удаление заказа вместе с товарами, списки по order_uid
+// и keyset-порядок по (date_created, order_uid). Заказы копируются на входе и выходе,
+// поэтому вызывающий не может изменить хранимые данные. Списки сортируются при каждом
+// запросе - хранилище рассчитано на тесты и демо, а не на большие объемы.
+// Ограничения схемы тоже соблюдаются: track_number уникален, chrt_id не повторяется в заказе
+type MemoryOrderRepository struct {
+	mu     sync.RWMutex
+	orders map[string]entities.Order
+	tracks map[string]string // track_number -> order_uid
+	logger domainrepo.Logger
+}
+
+func NewMemoryOrderRepository(logger domainrepo.Logger) *MemoryOrderRepository {
+	return &MemoryOrderRepository{
+		orders: make(map[string]entities.Order),
+		tracks: make(map[string]string),
+		logger: logger,
+	}
+}
+
+func (r *MemoryOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+	if err := checkItemKeys(order); err != nil {
+		r.logger.Warn("order items repeat chrt_id", "order_uid", order.OrderUID)
+		return err
+	}
+
+	stored := copyOrder(order)
+	// как TIMESTAMP в Postgres: UTC с точностью до микросекунд
+	stored.DateCreated = entities.NewTimestamp(order.DateCreated.Time)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if owner, ok := r.tracks[order.TrackNumber]; ok && owner != order.OrderUID {
+		r.logger.Warn("track number is already used", "order_uid", order.OrderUID, "track_number", order.TrackNumber)
+		return orderConflict("track_number", entities.ErrTrackNumberTaken)
+	}
+
+	if old, ok := r.orders[order.OrderUID]; ok {
+		delete(r.tracks, old.TrackNumber)
+	}
+	r.orders[order.OrderUID] = stored
+	r.tracks[order.TrackNumber] = order.OrderUID
+	return nil
+}
+
+func (r *MemoryOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+	return copyOrder(order), nil
+}
+
+func (r *MemoryOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("%w: negative limit or offset", ErrQueryFailed)
+	}
+
+	orders := r.sorted(func(a, b entities.Order) int {
+		return cmp.Compare(a.OrderUID, b.OrderUID)
+	})
+	return page(orders, offset, limit), nil
+}
+
+func (r *MemoryOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.orders), nil
+}
+
+func (r *MemoryOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.OrderCursor{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	orders := r.sorted(func(a, b entities.Order) int {
+		return compareCursors(entities.CursorOf(b), entities.CursorOf(a))
+	})
+	if n < 0 || n >= len(orders) {
+		return entities.OrderCursor{}, nil
+	}
+	return entities.CursorOf(orders[n]), nil
+}
+
+func (r *MemoryOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: negative limit", ErrQueryFailed)
+	}
+
+	orders := r.sorted(func(a, b entities.Order) int {
+		return compareCursors(entities.CursorOf(a), entities.CursorOf(b))
+	})
+	start := 0
+	if !after.IsZero() {
+		start, _ = slices.BinarySearchFunc(orders, after, func(o entities.Order, c entities.OrderCursor) int {
+			// курсор включается в "меньшие", чтобы поиск вернул первый заказ строго после него
+			if compareCursors(entities.CursorOf(o), c) <= 0 {
+				return -1
+			}
+			return 1
+		})
+	}
+	return page(orders, start, limit), nil
+}
+
+func (r *MemoryOrderRepository) DeleteOrder(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrOrderDeleteFailed, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return domain.ErrOrderNotFound
+	}
+	delete(r.orders, id)
+	delete(r.tracks, order.TrackNumber)
+	return nil
+}
+
+func (r *MemoryOrderRepository) ClearOrders(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrOrderClearFailed, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.orders)
+	clear(r.tracks)
+	return nil
+}
+
+func (r *MemoryOrderRepository) Shutdown(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	r.logger.Info("memory repository stopped, orders discarded", "orders", len(r.orders))
+	return nil
+}
+
+// sorted возвращает копии всех заказов в порядке compare
+func (r *MemoryOrderRepository) sorted(compare func(a, b entities.Order) int) []entities.Order {
+	r.mu.RLock()
+	orders := make([]entities.Order, 0, len(r.orders))
+	for _, order := range r.orders {
+		orders = append(orders, copyOrder(order))
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(orders, compare)
+	return orders
+}
+
+func compareCursors(a, b entities.OrderCursor) int {
+	if c := a.DateCreated.Compare(b.DateCreated); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.OrderUID, b.OrderUID)
+}
+
+func page(orders []entities.Order, offset, limit int) []entities.Order {
+	if offset >= len(orders) {
+		return []entities.Order{}
+	}
+	return orders[offset:min(len(orders), offset+limit)]
+}
+
+func copyOrder(order entities.Order) entities.Order {
+	order.Items = slices.Clone(order.Items)
+	return order
+}
@@ -0,0 +1,70 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/memory_repository_test.go
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger"
+)
+
+func newMemoryRepo(t *testing.T) *MemoryOrderRepository {
+	l, err := logger.NewLogger(logger.DEV)
+	require.NoError(t, err)
+	return NewMemoryOrderRepository(l)
+}
+
+func TestMemoryOrderRepository_Contract(t *testing.T) {
+	repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+		return newMemoryRepo(t)
+	})
+}
+
+func TestMemoryOrderRepository_IsolatesStoredOrders(t *testing.T) {
+	repo := newMemoryRepo(t)
+	ctx := context.Background()
+
+	order := repositorytest.NewOrder("a", time.Now())
+	require.NoError(t, repo.SaveOrder(ctx, order))
+	order.Items[0].Name = "changed by caller"
+
+	got, err := repo.GetOrder(ctx, "a")
+	require.NoError(t, err)
+	got.Items[0].Name = "changed by reader"
+
+	again, err := repo.GetOrder(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "item-1", again.Items[0].Name)
+}
+
+func TestMemoryOrderRepository_NormalizesDateCreated(t *testing.T) {
+	repo := newMemoryRepo(t)
+	ctx := context.Background()
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.FixedZone("MSK", 3*3600))
+	order := repositorytest.NewOrder("a", created)
+	order.DateCreated = entities.Timestamp{Time: created}
+	require.NoError(t, repo.SaveOrder(ctx, order))
+
+	got, err := repo.GetOrder(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, got.DateCreated.Location())
+	assert.Equal(t, 123456000, got.DateCreated.Nanosecond())
+}
+
+func TestMemoryOrderRepository_CanceledContext(t *testing.T) {
+	repo := newMemoryRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, repo.SaveOrder(ctx, repositorytest.NewOrder("a", time.Now())), ErrOrderSaveFailed)
+	_, err := repo.GetOrder(ctx, "a")
+	assert.ErrorIs(t, err, ErrQueryFailed)
+}
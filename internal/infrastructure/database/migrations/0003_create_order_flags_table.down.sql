@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0003_create_order_flags_table.down.sql
+DROP INDEX IF EXISTS idx_order_flags_created_at;
+DROP TABLE IF EXISTS order_flags;
@@ -0,0 +1,12 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0003_create_order_flags_table.up.sql
+CREATE TABLE
+  order_flags (
+    order_uid TEXT NOT NULL REFERENCES orders (order_uid) ON DELETE CASCADE,
+    rule TEXT NOT NULL,
+    field TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_uid, rule, field)
+  );
+
+CREATE INDEX IF NOT EXISTS idx_order_flags_created_at ON order_flags (created_at);
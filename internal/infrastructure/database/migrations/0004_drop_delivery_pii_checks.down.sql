@@ -0,0 +1,7 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0004_drop_delivery_pii_checks.down.sql
+-- откат возможен только после расшифровки данных доставки
+ALTER TABLE delivery ADD CONSTRAINT delivery_phone_check CHECK (phone LIKE '+%');
+ALTER TABLE delivery ADD CONSTRAINT delivery_email_check CHECK (
+  email = ''
+  OR email LIKE '%_@__%.__%'
+);
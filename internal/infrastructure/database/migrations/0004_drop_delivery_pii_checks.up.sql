@@ -0,0 +1,4 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0004_drop_delivery_pii_checks.up.sql
+-- phone и email хранятся зашифрованными, проверка формата выполняется в домене
+ALTER TABLE delivery DROP CONSTRAINT IF EXISTS delivery_phone_check;
+ALTER TABLE delivery DROP CONSTRAINT IF EXISTS delivery_email_check;
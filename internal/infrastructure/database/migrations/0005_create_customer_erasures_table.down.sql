@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0005_create_customer_erasures_table.down.sql
+DROP INDEX IF EXISTS idx_orders_customer_id;
+DROP TABLE IF EXISTS customer_erasures;
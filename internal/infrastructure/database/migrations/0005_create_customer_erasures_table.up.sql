@@ -0,0 +1,13 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0005_create_customer_erasures_table.up.sql
+CREATE TABLE
+  customer_erasures (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    order_uids TEXT[] NOT NULL,
+    requested_by TEXT NOT NULL,
+    erased_at TIMESTAMP NOT NULL DEFAULT NOW()
+  );
+
+CREATE INDEX IF NOT EXISTS idx_customer_erasures_customer_id ON customer_erasures (customer_id);
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders (customer_id);
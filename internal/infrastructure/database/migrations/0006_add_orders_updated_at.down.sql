@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0006_add_orders_updated_at.down.sql
+DROP INDEX IF EXISTS idx_orders_updated_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,5 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0006_add_orders_updated_at.up.sql
+ALTER TABLE orders
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders (updated_at);
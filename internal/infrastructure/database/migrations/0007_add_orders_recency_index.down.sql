@@ -0,0 +1,2 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0007_add_orders_recency_index.down.sql
+DROP INDEX IF EXISTS idx_orders_date_created_uid;
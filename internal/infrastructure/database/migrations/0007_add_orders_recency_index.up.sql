@@ -0,0 +1,2 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0007_add_orders_recency_index.up.sql
+CREATE INDEX IF NOT EXISTS idx_orders_date_created_uid ON orders (date_created, order_uid);
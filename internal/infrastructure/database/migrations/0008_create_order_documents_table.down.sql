@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0008_create_order_documents_table.down.sql
+DROP FUNCTION IF EXISTS backfill_order_documents();
+DROP TABLE IF EXISTS order_documents;
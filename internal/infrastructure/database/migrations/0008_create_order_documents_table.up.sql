@@ -0,0 +1,103 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0008_create_order_documents_table.up.sql
+-- Заказ целиком в одном JSONB-документе (database.layout: document).
+-- Поля для поиска вынесены в генерируемые колонки; date_created пишет репозиторий,
+-- так как приведение текста к TIMESTAMP не IMMUTABLE и в генерируемой колонке запрещено
+CREATE TABLE
+  order_documents (
+    order_uid TEXT PRIMARY KEY NOT NULL,
+    document JSONB NOT NULL,
+    track_number TEXT GENERATED ALWAYS AS (document ->> 'track_number') STORED,
+    customer_id TEXT GENERATED ALWAYS AS (document ->> 'customer_id') STORED,
+    transaction TEXT GENERATED ALWAYS AS (document -> 'payment' ->> 'transaction') STORED,
+    date_created TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+  );
+
+CREATE INDEX IF NOT EXISTS idx_order_documents_track_number ON order_documents (track_number);
+
+CREATE INDEX IF NOT EXISTS idx_order_documents_customer_id ON order_documents (customer_id);
+
+CREATE INDEX IF NOT EXISTS idx_order_documents_transaction ON order_documents (transaction);
+
+CREATE INDEX IF NOT EXISTS idx_order_documents_date_created_uid ON order_documents (date_created, order_uid);
+
+CREATE INDEX IF NOT EXISTS idx_order_documents_updated_at ON order_documents (updated_at);
+
+-- поиск по вложенным полям, например document @> '{"items": [{"nm_id": 2389212}]}'
+CREATE INDEX IF NOT EXISTS idx_order_documents_document ON order_documents USING GIN (document jsonb_path_ops);
+
+-- backfill_order_documents переносит заказы из нормализованных таблиц в документы и возвращает
+-- число перенесенных. Документ заменяется, только если заказ в таблицах новее, поэтому
+-- функцию можно запускать повторно перед переключением layout
+CREATE OR REPLACE FUNCTION backfill_order_documents() RETURNS BIGINT AS $$
+  WITH upserted AS (
+    INSERT INTO order_documents (order_uid, document, date_created, updated_at)
+    SELECT
+      o.order_uid,
+      jsonb_build_object(
+        'order_uid', o.order_uid,
+        'track_number', o.track_number,
+        'entry', o.entry,
+        'delivery', jsonb_build_object(
+          'name', COALESCE(d.name, ''),
+          'phone', COALESCE(d.phone, ''),
+          'zip', COALESCE(d.zip, ''),
+          'city', COALESCE(d.city, ''),
+          'address', COALESCE(d.address, ''),
+          'region', COALESCE(d.region, ''),
+          'email', COALESCE(d.email, '')
+        ),
+        'payment', jsonb_build_object(
+          'transaction', COALESCE(p.transaction, ''),
+          'request_id', COALESCE(p.request_id, ''),
+          'currency', COALESCE(p.currency, ''),
+          'provider', COALESCE(p.provider, ''),
+          'amount', COALESCE(p.amount, 0),
+          'payment_dt', COALESCE(p.payment_dt, 0),
+          'bank', COALESCE(p.bank, ''),
+          'delivery_cost', COALESCE(p.delivery_cost, 0),
+          'goods_total', COALESCE(p.goods_total, 0),
+          'custom_fee', COALESCE(p.custom_fee, 0)
+        ),
+        'items', COALESCE((
+          SELECT jsonb_agg(jsonb_build_object(
+            'chrt_id', i.chrt_id,
+            'track_number', i.track_number,
+            'price', i.price,
+            'rid', i.rid,
+            'name', i.name,
+            'sale', i.sale,
+            'size', i.size,
+            'total_price', i.total_price,
+            'nm_id', i.nm_id,
+            'brand', i.brand,
+            'status', i.status
+          ) ORDER BY i.chrt_id)
+          FROM items i
+          WHERE i.order_uid = o.order_uid
+        ), '[]'::jsonb),
+        'locale', o.locale,
+        'internal_signature', COALESCE(o.internal_signature, ''),
+        'customer_id', o.customer_id,
+        'delivery_service', o.delivery_service,
+        'shardkey', COALESCE(o.shardkey, ''),
+        'sm_id', o.sm_id,
+        'date_created', to_char(o.date_created, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'oof_shard', COALESCE(o.oof_shard, '')
+      ),
+      o.date_created,
+      o.updated_at
+    FROM orders o
+    LEFT JOIN delivery d ON o.order_uid = d.order_uid
+    LEFT JOIN payment p ON o.order_uid = p.order_uid
+    ON CONFLICT (order_uid) DO UPDATE SET
+      document = EXCLUDED.document,
+      date_created = EXCLUDED.date_created,
+      updated_at = EXCLUDED.updated_at
+    WHERE order_documents.updated_at < EXCLUDED.updated_at
+    RETURNING 1
+  )
+  SELECT COUNT(*) FROM upserted;
+$$ LANGUAGE sql;
+
+SELECT backfill_order_documents();
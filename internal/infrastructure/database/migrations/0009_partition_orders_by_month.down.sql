@@ -0,0 +1,119 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0009_partition_orders_by_month.down.sql
+-- Заказы из архивных таблиц возвращаются в рабочие; выгруженные в файлы остаются только в файлах
+-- заказ, сохраненный заново после архивации, есть и в рабочих таблицах: берется рабочая версия
+CREATE TEMP TABLE orders_copy AS
+SELECT * FROM orders
+UNION ALL
+SELECT * FROM orders_archive a WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_uid = a.order_uid);
+
+CREATE TEMP TABLE delivery_copy AS
+SELECT * FROM delivery
+UNION ALL
+SELECT * FROM delivery_archive a WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_uid = a.order_uid);
+
+CREATE TEMP TABLE payment_copy AS
+SELECT * FROM payment
+UNION ALL
+SELECT * FROM payment_archive a WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_uid = a.order_uid);
+
+CREATE TEMP TABLE items_copy AS
+SELECT * FROM items
+UNION ALL
+SELECT * FROM items_archive a WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_uid = a.order_uid);
+
+DROP TRIGGER IF EXISTS orders_delete_dependents ON orders;
+DROP TABLE items, payment, delivery, orders CASCADE;
+DROP TABLE items_archive, payment_archive, delivery_archive, orders_archive CASCADE;
+DROP TABLE IF EXISTS archived_order_files;
+DROP FUNCTION IF EXISTS create_order_partition(DATE);
+DROP FUNCTION IF EXISTS delete_order_dependents();
+
+CREATE TABLE
+  orders (
+    order_uid TEXT PRIMARY KEY NOT NULL,
+    track_number TEXT UNIQUE NOT NULL,
+    entry TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    internal_signature TEXT,
+    customer_id TEXT NOT NULL,
+    delivery_service TEXT NOT NULL,
+    shardkey TEXT,
+    sm_id INTEGER NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    oof_shard TEXT,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+  );
+
+CREATE TABLE
+  delivery (
+    order_uid TEXT PRIMARY KEY NOT NULL REFERENCES orders (order_uid) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    phone TEXT NOT NULL,
+    zip TEXT NOT NULL,
+    city TEXT NOT NULL,
+    address TEXT NOT NULL,
+    region TEXT NOT NULL,
+    email TEXT
+  );
+
+CREATE TABLE
+  payment (
+    order_uid TEXT PRIMARY KEY NOT NULL REFERENCES orders (order_uid) ON DELETE CASCADE,
+    transaction TEXT NOT NULL,
+    request_id TEXT,
+    currency TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    payment_dt BIGINT NOT NULL,
+    bank TEXT,
+    delivery_cost INTEGER NOT NULL CHECK (delivery_cost >= 0),
+    goods_total INTEGER NOT NULL CHECK (goods_total >= 0),
+    custom_fee INTEGER NOT NULL CHECK (custom_fee >= 0)
+  );
+
+CREATE TABLE
+  items (
+    chrt_id INTEGER NOT NULL,
+    order_uid TEXT NOT NULL REFERENCES orders (order_uid) ON DELETE CASCADE,
+    track_number TEXT NOT NULL,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    rid TEXT NOT NULL,
+    name TEXT NOT NULL,
+    sale INTEGER NOT NULL,
+    size TEXT NOT NULL,
+    total_price INTEGER NOT NULL CHECK (total_price >= 0),
+    nm_id INTEGER NOT NULL,
+    brand TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    PRIMARY KEY (chrt_id, order_uid)
+  );
+
+INSERT INTO orders
+SELECT order_uid, track_number, entry, locale, internal_signature, customer_id,
+       delivery_service, shardkey, sm_id, date_created, oof_shard, updated_at
+FROM orders_copy;
+
+INSERT INTO delivery
+SELECT order_uid, name, phone, zip, city, address, region, email FROM delivery_copy;
+
+INSERT INTO payment
+SELECT order_uid, transaction, request_id, currency, provider, amount,
+       payment_dt, bank, delivery_cost, goods_total, custom_fee
+FROM payment_copy;
+
+INSERT INTO items
+SELECT chrt_id, order_uid, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+FROM items_copy;
+
+CREATE INDEX IF NOT EXISTS idx_orders_track_number ON orders (track_number);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders (customer_id);
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders (updated_at);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders (date_created);
+CREATE INDEX IF NOT EXISTS idx_orders_date_created_uid ON orders (date_created, order_uid);
+CREATE INDEX IF NOT EXISTS idx_items_order_uid ON items (order_uid);
+
+DELETE FROM order_flags f WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_uid = f.order_uid);
+ALTER TABLE order_flags
+ADD CONSTRAINT order_flags_order_uid_fkey FOREIGN KEY (order_uid) REFERENCES orders (order_uid) ON DELETE CASCADE;
+
+DROP TABLE orders_copy, delivery_copy, payment_copy, items_copy;
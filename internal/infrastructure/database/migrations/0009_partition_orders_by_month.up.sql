@@ -0,0 +1,194 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0009_partition_orders_by_month.up.sql
+-- orders, delivery, payment и items секционируются по месяцу date_created: секция за месяц
+-- называется <таблица>_pYYYY_MM, заказы вне созданных месяцев попадают в <таблица>_default.
+-- Ключ секционированной таблицы обязан включать date_created, поэтому внешние ключи по order_uid
+-- заменены триггером каскадного удаления, а уникальность order_uid держит репозиторий
+CREATE TEMP TABLE orders_copy AS
+SELECT order_uid, track_number, entry, locale, internal_signature, customer_id,
+       delivery_service, shardkey, sm_id, date_created, oof_shard, updated_at
+FROM orders;
+
+CREATE TEMP TABLE delivery_copy AS
+SELECT d.order_uid, o.date_created, d.name, d.phone, d.zip, d.city, d.address, d.region, d.email
+FROM delivery d
+JOIN orders o ON o.order_uid = d.order_uid;
+
+CREATE TEMP TABLE payment_copy AS
+SELECT p.order_uid, o.date_created, p.transaction, p.request_id, p.currency, p.provider, p.amount,
+       p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+FROM payment p
+JOIN orders o ON o.order_uid = p.order_uid;
+
+CREATE TEMP TABLE items_copy AS
+SELECT i.chrt_id, i.order_uid, o.date_created, i.track_number, i.price, i.rid, i.name, i.sale,
+       i.size, i.total_price, i.nm_id, i.brand, i.status
+FROM items i
+JOIN orders o ON o.order_uid = i.order_uid;
+
+-- CASCADE снимает и внешний ключ order_flags -> orders
+DROP TABLE items, payment, delivery, orders CASCADE;
+
+CREATE TABLE
+  orders (
+    order_uid TEXT NOT NULL,
+    track_number TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    internal_signature TEXT,
+    customer_id TEXT NOT NULL,
+    delivery_service TEXT NOT NULL,
+    shardkey TEXT,
+    sm_id INTEGER NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    oof_shard TEXT,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_uid, date_created)
+  )
+PARTITION BY RANGE (date_created);
+
+CREATE TABLE
+  delivery (
+    order_uid TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    phone TEXT NOT NULL,
+    zip TEXT NOT NULL,
+    city TEXT NOT NULL,
+    address TEXT NOT NULL,
+    region TEXT NOT NULL,
+    email TEXT,
+    PRIMARY KEY (order_uid, date_created)
+  )
+PARTITION BY RANGE (date_created);
+
+CREATE TABLE
+  payment (
+    order_uid TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    transaction TEXT NOT NULL,
+    request_id TEXT,
+    currency TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    payment_dt BIGINT NOT NULL,
+    bank TEXT,
+    delivery_cost INTEGER NOT NULL CHECK (delivery_cost >= 0),
+    goods_total INTEGER NOT NULL CHECK (goods_total >= 0),
+    custom_fee INTEGER NOT NULL CHECK (custom_fee >= 0),
+    PRIMARY KEY (order_uid, date_created)
+  )
+PARTITION BY RANGE (date_created);
+
+CREATE TABLE
+  items (
+    chrt_id INTEGER NOT NULL,
+    order_uid TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    track_number TEXT NOT NULL,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    rid TEXT NOT NULL,
+    name TEXT NOT NULL,
+    sale INTEGER NOT NULL,
+    size TEXT NOT NULL,
+    total_price INTEGER NOT NULL CHECK (total_price >= 0),
+    nm_id INTEGER NOT NULL,
+    brand TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    PRIMARY KEY (chrt_id, order_uid, date_created)
+  )
+PARTITION BY RANGE (date_created);
+
+CREATE TABLE orders_default PARTITION OF orders DEFAULT;
+CREATE TABLE delivery_default PARTITION OF delivery DEFAULT;
+CREATE TABLE payment_default PARTITION OF payment DEFAULT;
+CREATE TABLE items_default PARTITION OF items DEFAULT;
+
+CREATE INDEX IF NOT EXISTS idx_orders_track_number ON orders (track_number);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders (customer_id);
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders (updated_at);
+CREATE INDEX IF NOT EXISTS idx_orders_date_created_uid ON orders (date_created, order_uid);
+CREATE INDEX IF NOT EXISTS idx_items_order_uid ON items (order_uid);
+
+-- Архив: секции старше database.archive.max_age переносятся сюда целиком (mode: table)
+CREATE TABLE orders_archive (LIKE orders INCLUDING DEFAULTS INCLUDING CONSTRAINTS, PRIMARY KEY (order_uid, date_created))
+PARTITION BY RANGE (date_created);
+CREATE TABLE delivery_archive (LIKE delivery INCLUDING DEFAULTS INCLUDING CONSTRAINTS, PRIMARY KEY (order_uid, date_created))
+PARTITION BY RANGE (date_created);
+CREATE TABLE payment_archive (LIKE payment INCLUDING DEFAULTS INCLUDING CONSTRAINTS, PRIMARY KEY (order_uid, date_created))
+PARTITION BY RANGE (date_created);
+CREATE TABLE items_archive (LIKE items INCLUDING DEFAULTS INCLUDING CONSTRAINTS, PRIMARY KEY (chrt_id, order_uid, date_created))
+PARTITION BY RANGE (date_created);
+
+CREATE INDEX IF NOT EXISTS idx_items_archive_order_uid ON items_archive (order_uid);
+CREATE INDEX IF NOT EXISTS idx_orders_archive_customer_id ON orders_archive (customer_id);
+
+-- mode: file - секция выгружается в NDJSON.gz и удаляется, здесь остается только путь к файлу
+CREATE TABLE
+  archived_order_files (
+    order_uid TEXT PRIMARY KEY NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    file TEXT NOT NULL
+  );
+
+-- delete_order_dependents заменяет ON DELETE CASCADE. Перенос строк между секциями
+-- (create_order_partition) выставляет orders.moving_partitions, и триггер их не трогает
+CREATE OR REPLACE FUNCTION delete_order_dependents() RETURNS TRIGGER AS $$
+BEGIN
+  IF current_setting('orders.moving_partitions', true) = 'on' THEN
+    RETURN OLD;
+  END IF;
+
+  DELETE FROM delivery WHERE order_uid = OLD.order_uid AND date_created = OLD.date_created;
+  DELETE FROM payment WHERE order_uid = OLD.order_uid AND date_created = OLD.date_created;
+  DELETE FROM items WHERE order_uid = OLD.order_uid AND date_created = OLD.date_created;
+  DELETE FROM order_flags WHERE order_uid = OLD.order_uid;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_delete_dependents
+AFTER DELETE ON orders
+FOR EACH ROW EXECUTE FUNCTION delete_order_dependents();
+
+-- create_order_partition создает секции месяца p_month для всех четырех таблиц и переносит
+-- в них строки этого месяца из секций по умолчанию. Уже существующие секции пропускаются
+CREATE OR REPLACE FUNCTION create_order_partition(p_month DATE) RETURNS VOID AS $$
+DECLARE
+  from_ts TIMESTAMP := date_trunc('month', p_month);
+  to_ts TIMESTAMP := date_trunc('month', p_month) + INTERVAL '1 month';
+  suffix TEXT := to_char(p_month, '"_p"YYYY_MM');
+  t TEXT;
+BEGIN
+  PERFORM set_config('orders.moving_partitions', 'on', true);
+
+  FOREACH t IN ARRAY ARRAY['orders', 'delivery', 'payment', 'items'] LOOP
+    IF to_regclass(t || suffix) IS NOT NULL THEN
+      CONTINUE;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', t || suffix, t);
+    EXECUTE format(
+      'WITH moved AS (DELETE FROM %I WHERE date_created >= $1 AND date_created < $2 RETURNING *) INSERT INTO %I SELECT * FROM moved',
+      t || '_default', t || suffix
+    ) USING from_ts, to_ts;
+    EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', t, t || suffix, from_ts, to_ts);
+  END LOOP;
+
+  PERFORM set_config('orders.moving_partitions', 'off', true);
+END;
+$$ LANGUAGE plpgsql;
+
+-- секции на все месяцы с заказами и два месяца вперед
+SELECT create_order_partition(m::date)
+FROM generate_series(
+  date_trunc('month', COALESCE((SELECT MIN(date_created) FROM orders_copy), NOW())),
+  date_trunc('month', NOW()) + INTERVAL '2 month',
+  INTERVAL '1 month'
+) AS m;
+
+INSERT INTO orders SELECT * FROM orders_copy;
+INSERT INTO delivery SELECT * FROM delivery_copy;
+INSERT INTO payment SELECT * FROM payment_copy;
+INSERT INTO items SELECT * FROM items_copy;
+
+DROP TABLE orders_copy, delivery_copy, payment_copy, items_copy;
@@ -0,0 +1,2 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0010_add_orders_archive_track_number_index.down.sql
+DROP INDEX IF EXISTS idx_orders_archive_track_number;
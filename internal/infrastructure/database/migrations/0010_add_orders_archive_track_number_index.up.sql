@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0010_add_orders_archive_track_number_index.up.sql
+-- уникальность track_number после 0009 проверяет репозиторий, в том числе по архивным таблицам
+CREATE INDEX IF NOT EXISTS idx_orders_archive_track_number ON orders_archive (track_number);
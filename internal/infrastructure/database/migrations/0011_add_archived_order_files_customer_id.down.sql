@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0011_add_archived_order_files_customer_id.down.sql
+DROP INDEX IF EXISTS idx_archived_order_files_customer_id;
+ALTER TABLE archived_order_files DROP COLUMN IF EXISTS customer_id;
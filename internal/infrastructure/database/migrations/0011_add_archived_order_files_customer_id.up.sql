@@ -0,0 +1,5 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0011_add_archived_order_files_customer_id.up.sql
+-- по customer_id удаление и выгрузка данных клиента находят его заказы в файлах архива;
+-- у заказов, выгруженных до этой миграции, он пуст и заполняется при первом удалении данных
+ALTER TABLE archived_order_files ADD COLUMN IF NOT EXISTS customer_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_archived_order_files_customer_id ON archived_order_files (customer_id);
@@ -0,0 +1,3 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0012_add_order_documents_delete_flags_trigger.down.sql
+DROP TRIGGER IF EXISTS order_documents_delete_flags ON order_documents;
+DROP FUNCTION IF EXISTS delete_order_document_flags();
@@ -0,0 +1,13 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/migrations/0012_add_order_documents_delete_flags_trigger.up.sql
+-- в layout document флаги лежат в той же order_flags и снимаются при удалении документа,
+-- как delete_order_dependents снимает их при удалении строки orders
+CREATE OR REPLACE FUNCTION delete_order_document_flags() RETURNS TRIGGER AS $$
+BEGIN
+  DELETE FROM order_flags WHERE order_uid = OLD.order_uid;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_documents_delete_flags
+AFTER DELETE ON order_documents
+FOR EACH ROW EXECUTE FUNCTION delete_order_document_flags();
@@ -0,0 +1,113 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_cache_bus.go
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// PostgresCacheBus рассылает события инвалидации через LISTEN/NOTIFY.
+// NOTIFY не хранит сообщения: после переподключения слушателя часть событий
+// могла потеряться, поэтому локальный кэш в этом случае очищается целиком
+type PostgresCacheBus struct {
+	db       *sqlx.DB
+	listener *pq.Listener
+	channel  string
+	logger   domainrepo.Logger
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func NewPostgresCacheBus(db *sqlx.DB, dsn, channel string, logger domainrepo.Logger) (*PostgresCacheBus, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn("cache invalidation listener event", "event", int(ev), "error", err)
+		}
+	})
+
+	return &PostgresCacheBus{
+		db:       db,
+		listener: listener,
+		channel:  channel,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (b *PostgresCacheBus) Publish(ctx context.Context, event entities.CacheEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCacheEventPublish, err)
+	}
+	if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", b.channel, string(payload)); err != nil {
+		return fmt.Errorf("%w: %v", ErrCacheEventPublish, err)
+	}
+	return nil
+}
+
+func (b *PostgresCacheBus) Start(handler func(entities.CacheEvent)) error {
+	if err := b.listener.Listen(b.channel); err != nil {
+		return fmt.Errorf("%w: %v", ErrCacheBusListen, err)
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case n, ok := <-b.listener.Notify:
+				if !ok {
+					return
+				}
+				b.dispatch(n, handler)
+			case <-b.done:
+				return
+			}
+		}
+	}()
+
+	b.logger.Info("cache invalidation listener started", "backend", "postgres", "channel", b.channel)
+	return nil
+}
+
+func (b *PostgresCacheBus) dispatch(n *pq.Notification, handler func(entities.CacheEvent)) {
+	if n == nil {
+		// pq присылает nil после восстановления соединения
+		b.logger.Warn("cache invalidation listener reconnected, clearing local cache")
+		handler(entities.CacheEvent{Type: entities.CacheEventClear})
+		return
+	}
+
+	var event entities.CacheEvent
+	if err := json.Unmarshal([]byte(n.Extra), &event); err != nil {
+		b.logger.Warn("invalid cache invalidation payload", "error", err)
+		return
+	}
+	handler(event)
+}
+
+func (b *PostgresCacheBus) Shutdown(ctx context.Context) error {
+	var err error
+	b.once.Do(func() {
+		close(b.done)
+		err = b.listener.Close()
+		b.wg.Wait()
+	})
+	return err
+}
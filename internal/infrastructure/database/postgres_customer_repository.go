@@ -0,0 +1,162 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_customer_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+type PostgresCustomerDataRepository struct {
+	db     *sqlx.DB
+	logger domainrepo.Logger
+}
+
+func NewPostgresCustomerDataRepository(db *sqlx.DB, logger domainrepo.Logger) (*PostgresCustomerDataRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &PostgresCustomerDataRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *PostgresCustomerDataRepository) GetCustomerOrderIDs(ctx context.Context, customerID string) ([]string, error) {
+	var ids []string
+	query := `
+		SELECT order_uid FROM (
+			SELECT order_uid, date_created FROM orders WHERE customer_id = $1
+			UNION
+			SELECT order_uid, date_created FROM orders_archive WHERE customer_id = $1
+			UNION
+			SELECT order_uid, date_created FROM archived_order_files WHERE customer_id = $1
+		) o
+		ORDER BY date_created, order_uid
+	`
+	if err := r.db.SelectContext(ctx, &ids, query, customerID); err != nil {
+		r.logger.Error("failed to get customer orders", "error", err, "customer_id", customerID)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return ids, nil
+}
+
+func (r *PostgresCustomerDataRepository) EraseCustomer(ctx context.Context, erasure entities.Erasure) (entities.Erasure, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	// файлы архива (mode: file) обрабатываются первыми: их замок берется раньше замков таблиц,
+	// как при выгрузке месяца, иначе выгрузка и удаление могут ждать друг друга. Файлы
+	// переписываются до коммита: если транзакция откатится, повтор пройдет по ним без изменений
+	fromFiles, err := eraseArchivedCustomerTx(ctx, tx, r.logger, erasure.CustomerID)
+	if err != nil {
+		r.logger.Error("failed to anonymize archive files", "error", err, "customer_id", erasure.CustomerID)
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrUpdateFailed, err)
+	}
+
+	// архивные таблицы анонимизируются вместе с рабочими
+	query := `
+		WITH live AS (
+			UPDATE delivery d
+			SET name = $2, phone = $2, address = $2, email = $2
+			FROM orders o
+			WHERE o.order_uid = d.order_uid AND o.date_created = d.date_created AND o.customer_id = $1
+			RETURNING d.order_uid
+		), archived AS (
+			UPDATE delivery_archive d
+			SET name = $2, phone = $2, address = $2, email = $2
+			FROM orders_archive o
+			WHERE o.order_uid = d.order_uid AND o.date_created = d.date_created AND o.customer_id = $1
+			RETURNING d.order_uid
+		)
+		SELECT order_uid FROM live
+		UNION
+		SELECT order_uid FROM archived
+	`
+
+	var ids []string
+	if err := tx.SelectContext(ctx, &ids, query, erasure.CustomerID, entities.ErasedValue); err != nil {
+		r.logger.Error("failed to anonymize customer delivery", "error", err, "customer_id", erasure.CustomerID)
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrUpdateFailed, err)
+	}
+
+	erasure.OrderUIDs = mergeOrderUIDs(ids, fromFiles)
+	if erasure.OrderUIDs == nil {
+		erasure.OrderUIDs = []string{}
+	}
+
+	// снимки кэша сверяются по updated_at, поэтому анонимизация считается изменением заказа
+	if _, err := tx.ExecContext(ctx, "UPDATE orders SET updated_at = NOW() WHERE customer_id = $1", erasure.CustomerID); err != nil {
+		r.logger.Error("failed to touch erased orders", "error", err, "customer_id", erasure.CustomerID)
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrUpdateFailed, err)
+	}
+
+	erasure, err = r.logErasureTx(ctx, tx, erasure)
+	if err != nil {
+		return entities.Erasure{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+
+	return erasure, nil
+}
+
+// logErasureTx пишет запрос в журнал удалений в транзакции самой анонимизации
+func (r *PostgresCustomerDataRepository) logErasureTx(ctx context.Context, tx *sqlx.Tx, erasure entities.Erasure) (entities.Erasure, error) {
+	err := tx.QueryRowxContext(ctx, `
+		INSERT INTO customer_erasures (customer_id, order_uids, requested_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, erased_at
+	`, erasure.CustomerID, pq.Array(erasure.OrderUIDs), erasure.RequestedBy).Scan(&erasure.ID, &erasure.ErasedAt)
+	if err != nil {
+		r.logger.Error("failed to write erasure log", "error", err, "customer_id", erasure.CustomerID)
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrInsertFailed, err)
+	}
+	return erasure, nil
+}
+
+func (r *PostgresCustomerDataRepository) GetErasures(ctx context.Context, customerID string) ([]entities.Erasure, error) {
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT id, customer_id, order_uids, requested_by, erased_at
+		FROM customer_erasures
+		WHERE customer_id = $1
+		ORDER BY erased_at, id
+	`, customerID)
+	if err != nil {
+		r.logger.Error("failed to get erasure log", "error", err, "customer_id", customerID)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	erasures := []entities.Erasure{}
+	for rows.Next() {
+		var e entities.Erasure
+		if err := rows.Scan(&e.ID, &e.CustomerID, pq.Array(&e.OrderUIDs), &e.RequestedBy, &e.ErasedAt); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+		erasures = append(erasures, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	return erasures, nil
+}
+
+func mergeOrderUIDs(a, b []string) []string {
+	merged := slices.Concat(a, b)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
@@ -0,0 +1,81 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_document_customer_repository.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// PostgresDocumentCustomerDataRepository - данные клиента в layout document: заказы ищутся
+// по генерируемой колонке customer_id, доставка анонимизируется внутри JSONB.
+// Журнал удалений общий с нормализованным layout
+type PostgresDocumentCustomerDataRepository struct {
+	*PostgresCustomerDataRepository
+}
+
+func NewPostgresDocumentCustomerDataRepository(db *sqlx.DB, logger domainrepo.Logger) (*PostgresDocumentCustomerDataRepository, error) {
+	base, err := NewPostgresCustomerDataRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresDocumentCustomerDataRepository{PostgresCustomerDataRepository: base}, nil
+}
+
+func (r *PostgresDocumentCustomerDataRepository) GetCustomerOrderIDs(ctx context.Context, customerID string) ([]string, error) {
+	var ids []string
+	query := `
+		SELECT order_uid FROM order_documents
+		WHERE customer_id = $1
+		ORDER BY date_created, order_uid
+	`
+	if err := r.db.SelectContext(ctx, &ids, query, customerID); err != nil {
+		r.logger.Error("failed to get customer orders", "error", err, "customer_id", customerID)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return ids, nil
+}
+
+func (r *PostgresDocumentCustomerDataRepository) EraseCustomer(ctx context.Context, erasure entities.Erasure) (entities.Erasure, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	// остальные поля доставки и документа не меняются; updated_at сдвигается для снимков кэша
+	query := `
+		UPDATE order_documents
+		SET document = jsonb_set(document, '{delivery}', COALESCE(document -> 'delivery', '{}'::jsonb) ||
+				jsonb_build_object('name', $2::text, 'phone', $2::text, 'address', $2::text, 'email', $2::text)),
+			updated_at = NOW()
+		WHERE customer_id = $1
+		RETURNING order_uid
+	`
+
+	var ids []string
+	if err := tx.SelectContext(ctx, &ids, query, erasure.CustomerID, entities.ErasedValue); err != nil {
+		r.logger.Error("failed to anonymize customer documents", "error", err, "customer_id", erasure.CustomerID)
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrUpdateFailed, err)
+	}
+
+	erasure.OrderUIDs = mergeOrderUIDs(ids, nil)
+	if erasure.OrderUIDs == nil {
+		erasure.OrderUIDs = []string{}
+	}
+
+	erasure, err = r.logErasureTx(ctx, tx, erasure)
+	if err != nil {
+		return entities.Erasure{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.Erasure{}, fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+
+	return erasure, nil
+}
@@ -0,0 +1,267 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_document_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// PostgresDocumentOrderRepository хранит заказ одним JSONB-документом в order_documents:
+// чтение - одна строка без JOIN, запись - один upsert без удаления товаров.
+// Поиск идет по генерируемым колонкам и GIN-индексу, см. миграцию 0008
+type PostgresDocumentOrderRepository struct {
+	db               *sqlx.DB
+	logger           domainrepo.Logger
+	statementTimeout time.Duration
+	replicas         *ReplicaRouter
+}
+
+func NewPostgresDocumentOrderRepository(db *sqlx.DB, logger domainrepo.Logger, statementTimeout time.Duration, opts ...PostgresOption) (*PostgresDocumentOrderRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	o := newPostgresOptions(opts)
+	return &PostgresDocumentOrderRepository{
+		db:               db,
+		logger:           logger,
+		statementTimeout: statementTimeout,
+		replicas:         o.replicas,
+	}, nil
+}
+
+func (r *PostgresDocumentOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	if err := checkItemKeys(order); err != nil {
+		r.logger.Warn("order items repeat chrt_id", "order_uid", order.OrderUID)
+		return err
+	}
+
+	if r.statementTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.statementTimeout)
+		defer cancel()
+	}
+
+	// в документе время то же, что в колонке date_created, иначе заказ не будет Equal после чтения
+	order.DateCreated = entities.NewTimestamp(order.DateCreated.Time)
+	document, err := json.Marshal(order)
+	if err != nil {
+		r.logger.Error("failed to encode order document", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	query := `
+		INSERT INTO order_documents (order_uid, document, date_created)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (order_uid) DO UPDATE SET
+			document = EXCLUDED.document,
+			date_created = EXCLUDED.date_created,
+			updated_at = NOW()
+	`
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	if err := r.checkTrackNumberTx(ctx, tx, order); err != nil {
+		return err
+	}
+
+	// строкой, а не []byte: lib/pq передает []byte как bytea, и JSONB его не примет
+	if _, err := tx.ExecContext(ctx, query, order.OrderUID, string(document), order.DateCreated); err != nil {
+		r.logger.Error("failed to save order document", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	r.replicas.written(order.OrderUID)
+	return nil
+}
+
+// checkTrackNumberTx держит уникальность track_number, как checkOrderConflictsTx в таблицах:
+// генерируемая колонка проиндексирована без UNIQUE, поэтому номер проверяется под тем же замком
+func (r *PostgresDocumentOrderRepository) checkTrackNumberTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('track_number'), hashtext($1))", order.TrackNumber); err != nil {
+		r.logger.Error("failed to lock track number", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	var taken bool
+	query := "SELECT EXISTS (SELECT 1 FROM order_documents WHERE track_number = $1 AND order_uid <> $2)"
+	if err := tx.QueryRowContext(ctx, query, order.TrackNumber, order.OrderUID).Scan(&taken); err != nil {
+		r.logger.Error("failed to check track number", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+	if taken {
+		r.logger.Warn("track number is already used", "order_uid", order.OrderUID, "track_number", order.TrackNumber)
+		return orderConflict("track_number", entities.ErrTrackNumberTaken)
+	}
+	return nil
+}
+
+func (r *PostgresDocumentOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	var document []byte
+	var updatedAt time.Time
+	db := r.replicas.reader(ctx, r.db, id)
+	err := db.QueryRowContext(ctx, "SELECT document, updated_at FROM order_documents WHERE order_uid = $1", id).Scan(&document, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		r.replicas.failed(ctx, db, err)
+		r.logger.Error("failed to get order document", "error", err, "order_uid", id)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	var order entities.Order
+	if err := json.Unmarshal(document, &order); err != nil {
+		r.logger.Error("failed to decode order document", "error", err, "order_uid", id)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	order.UpdatedAt = updatedAt
+	return order, nil
+}
+
+func (r *PostgresDocumentOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	query := `
+		SELECT document, updated_at FROM order_documents
+		ORDER BY order_uid
+		LIMIT $1 OFFSET $2
+	`
+	return r.queryDocuments(ctx, query, limit, offset)
+}
+
+func (r *PostgresDocumentOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
+	var count int
+	db := r.replicas.reader(ctx, r.db, "")
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM order_documents").Scan(&count); err != nil {
+		r.replicas.failed(ctx, db, err)
+		r.logger.Error("failed to get orders count", "error", err)
+		return 0, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return count, nil
+}
+
+func (r *PostgresDocumentOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	query := `
+		SELECT date_created, order_uid
+		FROM order_documents
+		ORDER BY date_created DESC, order_uid DESC
+		LIMIT 1 OFFSET $1
+	`
+
+	var cursor entities.OrderCursor
+	db := r.replicas.reader(ctx, r.db, "")
+	err := db.QueryRowContext(ctx, query, n).Scan(&cursor.DateCreated, &cursor.OrderUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.OrderCursor{}, nil
+	}
+	if err != nil {
+		r.replicas.failed(ctx, db, err)
+		r.logger.Error("failed to get recent orders cursor", "error", err, "n", n)
+		return entities.OrderCursor{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return cursor, nil
+}
+
+func (r *PostgresDocumentOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	if after.IsZero() {
+		query := `
+		SELECT document, updated_at FROM order_documents
+		ORDER BY date_created, order_uid
+		LIMIT $1
+	`
+		return r.queryDocuments(ctx, query, limit)
+	}
+
+	query := `
+		SELECT document, updated_at FROM order_documents
+		WHERE (date_created, order_uid) > ($1::timestamp, $2::text)
+		ORDER BY date_created, order_uid
+		LIMIT $3
+	`
+	return r.queryDocuments(ctx, query, after.DateCreated, after.OrderUID, limit)
+}
+
+func (r *PostgresDocumentOrderRepository) queryDocuments(ctx context.Context, query string, args ...interface{}) ([]entities.Order, error) {
+	db := r.replicas.reader(ctx, r.db, "")
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.replicas.failed(ctx, db, err)
+		r.logger.Error("failed to get order documents", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	orders := []entities.Order{}
+	for rows.Next() {
+		var document []byte
+		var updatedAt time.Time
+		if err := rows.Scan(&document, &updatedAt); err != nil {
+			r.logger.Error("failed to scan order document", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+
+		var order entities.Order
+		if err := json.Unmarshal(document, &order); err != nil {
+			r.logger.Error("failed to decode order document", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+		order.UpdatedAt = updatedAt
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	return orders, nil
+}
+
+func (r *PostgresDocumentOrderRepository) DeleteOrder(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM order_documents WHERE order_uid = $1", id)
+	if err != nil {
+		r.logger.Error("failed to delete order document", "error", err, "order_uid", id)
+		return fmt.Errorf("%w: %v", ErrOrderDeleteFailed, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOrderDeleteFailed, err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+
+	r.replicas.written(id)
+	return nil
+}
+
+func (r *PostgresDocumentOrderRepository) ClearOrders(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM order_documents"); err != nil {
+		r.logger.Error("failed to clear order documents", "error", err)
+		return fmt.Errorf("%w: %v", ErrOrderClearFailed, err)
+	}
+	r.replicas.written("")
+	return nil
+}
+
+func (r *PostgresDocumentOrderRepository) Shutdown(ctx context.Context) error {
+	if err := r.db.Close(); err != nil {
+		r.logger.Error("failed to close database connection", "error", err)
+		return fmt.Errorf("%w: %v", ErrDatabaseConnectionFailed, err)
+	}
+	return nil
+}
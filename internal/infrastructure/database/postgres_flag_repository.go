@@ -0,0 +1,79 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_flag_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+type PostgresOrderFlagRepository struct {
+	db     *sqlx.DB
+	logger domainrepo.Logger
+}
+
+func NewPostgresOrderFlagRepository(db *sqlx.DB, logger domainrepo.Logger) (*PostgresOrderFlagRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &PostgresOrderFlagRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *PostgresOrderFlagRepository) ReplaceFlags(ctx context.Context, orderUID string, flags []entities.OrderFlag) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM order_flags WHERE order_uid = $1", orderUID); err != nil {
+		r.logger.Error("failed to delete order flags", "error", err, "order_uid", orderUID)
+		return fmt.Errorf("%w: %v", ErrDeleteFailed, err)
+	}
+
+	query := `
+		INSERT INTO order_flags (order_uid, rule, field, message)
+		VALUES (:order_uid, :rule, :field, :message)
+		ON CONFLICT (order_uid, rule, field) DO UPDATE SET
+				message = EXCLUDED.message
+	`
+
+	for _, flag := range flags {
+		flag.OrderUID = orderUID
+		if _, err := tx.NamedExecContext(ctx, query, flag); err != nil {
+			r.logger.Error("failed to save order flag", "error", err, "order_uid", orderUID)
+			return fmt.Errorf("%w: %v", ErrInsertFailed, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresOrderFlagRepository) GetFlags(ctx context.Context, limit, offset int) ([]entities.OrderFlag, error) {
+	query := `
+		SELECT order_uid, rule, field, message, created_at
+		FROM order_flags
+		ORDER BY created_at DESC, order_uid, rule, field
+		LIMIT $1 OFFSET $2
+	`
+
+	var flags []entities.OrderFlag
+	if err := r.db.SelectContext(ctx, &flags, query, limit, offset); err != nil {
+		r.logger.Error("failed to get order flags", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,93 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_layout_bench_test.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger"
+)
+
+const (
+	benchOrders = 1000
+	benchItems  = 5
+)
+
+// BenchmarkPostgresLayouts сравнивает задержки записи и чтения нормализованных таблиц и JSONB-документов:
+//
+//	go test -run '^$' -bench PostgresLayouts ./internal/infrastructure/database/
+func BenchmarkPostgresLayouts(b *testing.B) {
+	if testing.Short() {
+		b.Skip("Skipping integration benchmark in short mode")
+	}
+
+	db := setupTestDB(b)
+	l, err := logger.NewLogger(logger.PROD)
+	require.NoError(b, err)
+
+	normalized, err := NewPostgresOrderRepository(db, l, 30*time.Second)
+	require.NoError(b, err)
+	documents, err := NewPostgresDocumentOrderRepository(db, l, 30*time.Second)
+	require.NoError(b, err)
+
+	layouts := []struct {
+		name string
+		repo domainrepo.OrderRepository
+	}{
+		{"normalized", normalized},
+		{"document", documents},
+	}
+
+	ctx := context.Background()
+	for _, layout := range layouts {
+		repo := layout.repo
+		require.NoError(b, repo.ClearOrders(ctx))
+		for i := 0; i < benchOrders; i++ {
+			require.NoError(b, repo.SaveOrder(ctx, benchOrder(i)))
+		}
+
+		b.Run(layout.name+"/Save", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := repo.SaveOrder(ctx, benchOrder(i%benchOrders)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(layout.name+"/Get", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetOrder(ctx, benchUID(i%benchOrders)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(layout.name+"/List50", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetAllOrders(ctx, 50, (i*50)%benchOrders); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func benchUID(i int) string {
+	return fmt.Sprintf("bench-%04d", i)
+}
+
+func benchOrder(i int) entities.Order {
+	order := repositorytest.NewOrder(benchUID(i), time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC))
+	order.Items = repositorytest.Items(order.TrackNumber, 1, benchItems)
+	return order
+}
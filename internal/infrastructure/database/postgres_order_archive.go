@@ -0,0 +1,475 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_order_archive.go
+package repository
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+const (
+	// ArchiveModeTable - секции переносятся в таблицы *_archive той же БД
+	ArchiveModeTable = "table"
+	// ArchiveModeFile - секции выгружаются в NDJSON.gz на локальный диск и удаляются
+	ArchiveModeFile = "file"
+
+	// lockArchiveFiles сериализует выгрузку месяцев в файлы и удаление данных клиента:
+	// иначе выгрузка могла бы унести в новый файл еще не анонимизированные строки
+	lockArchiveFiles = "SELECT pg_advisory_xact_lock(hashtext('archived_order_files'))"
+
+	// partitionsAhead - на сколько месяцев вперед создаются секции
+	partitionsAhead = 2
+	partitionMonth  = "2006_01"
+)
+
+// orderPartitionTables - таблицы, секционированные по месяцу date_created (миграция 0009)
+var orderPartitionTables = []string{"orders", "delivery", "payment", "items"}
+
+// PostgresOrderArchive обслуживает помесячные секции заказов: создает их наперед, переносит
+// секции старых месяцев в архив и находит там заказы по ID. Секция уходит в архив целиком,
+// поэтому заказы месяца пропадают из рабочих таблиц, списков и счетчиков одновременно
+type PostgresOrderArchive struct {
+	db       *sqlx.DB
+	logger   domainrepo.Logger
+	mode     string
+	dir      string
+	archived *PostgresOrderRepository
+}
+
+// NewPostgresOrderArchive: dir - каталог файлов архива, нужен только в режиме file
+func NewPostgresOrderArchive(db *sqlx.DB, logger domainrepo.Logger, mode, dir string) (*PostgresOrderArchive, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	switch mode {
+	case ArchiveModeTable:
+	case ArchiveModeFile:
+		if dir == "" {
+			return nil, errors.New("archive dir is required for file mode")
+		}
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+		}
+	default:
+		return nil, fmt.Errorf("archive: unknown mode %q", mode)
+	}
+
+	return &PostgresOrderArchive{
+		db:     db,
+		logger: logger,
+		mode:   mode,
+		dir:    dir,
+		archived: &PostgresOrderRepository{
+			db:     db,
+			logger: logger,
+			tables: archivedOrderTables,
+		},
+	}, nil
+}
+
+// EnsurePartitions создает секции на месяц now и partitionsAhead месяцев вперед;
+// заказы без своей секции лежат в *_default и в архив не попадают
+func (a *PostgresOrderArchive) EnsurePartitions(ctx context.Context, now time.Time) error {
+	month := monthStart(now)
+	for i := 0; i <= partitionsAhead; i++ {
+		next := month.AddDate(0, i, 0).Format(time.DateOnly)
+		if _, err := a.db.ExecContext(ctx, "SELECT create_order_partition($1::date)", next); err != nil {
+			a.logger.Error("failed to create orders partition", "error", err, "month", next)
+			return fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+	}
+	return nil
+}
+
+func (a *PostgresOrderArchive) ArchiveBefore(ctx context.Context, before time.Time) (int, error) {
+	months, err := a.partitionMonths(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, month := range months {
+		if month.AddDate(0, 1, 0).After(before) {
+			break
+		}
+
+		if a.mode == ArchiveModeFile {
+			err = a.exportMonth(ctx, month)
+		} else {
+			err = a.moveMonth(ctx, month)
+		}
+		if err != nil {
+			return archived, err
+		}
+
+		archived++
+		a.logger.Info("orders partition archived", "month", month.Format(partitionMonth), "mode", a.mode)
+	}
+	return archived, nil
+}
+
+// partitionMonths возвращает месяцы рабочих секций orders по возрастанию
+func (a *PostgresOrderArchive) partitionMonths(ctx context.Context) ([]time.Time, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'orders'::regclass
+		ORDER BY c.relname
+	`
+
+	var names []string
+	if err := a.db.SelectContext(ctx, &names, query); err != nil {
+		a.logger.Error("failed to list orders partitions", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	months := make([]time.Time, 0, len(names))
+	for _, name := range names {
+		suffix, ok := strings.CutPrefix(name, "orders_p")
+		if !ok {
+			continue
+		}
+		month, err := time.Parse(partitionMonth, suffix)
+		if err != nil {
+			continue
+		}
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+// moveMonth переключает секции месяца из рабочих таблиц в архивные; данные не копируются
+func (a *PostgresOrderArchive) moveMonth(ctx context.Context, month time.Time) error {
+	tx, err := a.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	from := pq.QuoteLiteral(month.Format(time.DateOnly))
+	to := pq.QuoteLiteral(month.AddDate(0, 1, 0).Format(time.DateOnly))
+	for _, table := range orderPartitionTables {
+		part := pq.QuoteIdentifier(partitionName(table, month))
+		statements := []string{
+			fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", table, part),
+			fmt.Sprintf("ALTER TABLE %s_archive ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)", table, part, from, to),
+		}
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				a.logger.Error("failed to move partition to archive", "error", err, "table", table, "month", month.Format(partitionMonth))
+				return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	return nil
+}
+
+// exportMonth выгружает секции месяца в файл и удаляет их. Секции отсоединяются в начале
+// транзакции: записи этого месяца ждут ее конца, а после удаления секций ложатся в *_default
+// и остаются рабочими. При любой ошибке откат возвращает секции на место
+func (a *PostgresOrderArchive) exportMonth(ctx context.Context, month time.Time) error {
+	tx, err := a.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, lockArchiveFiles); err != nil {
+		a.logger.Error("failed to lock archive files", "error", err)
+		return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+	}
+
+	parts := monthPartitions(month)
+	for _, table := range orderPartitionTables {
+		part := pq.QuoteIdentifier(partitionName(table, month))
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", table, part)); err != nil {
+			a.logger.Error("failed to detach partition", "error", err, "table", table, "month", month.Format(partitionMonth))
+			return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+		}
+	}
+
+	// время в имени: повторная выгрузка того же месяца не затрет прежний файл
+	name := fmt.Sprintf("orders-%s-%d.ndjson.gz", month.Format("2006-01"), time.Now().Unix())
+	path := filepath.Join(a.dir, name)
+	if err := a.writeOrders(ctx, tx, parts, path); err != nil {
+		a.logger.Error("failed to export orders partition", "error", err, "month", month.Format(partitionMonth), "file", path)
+		return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+	}
+
+	index := fmt.Sprintf(`
+		INSERT INTO archived_order_files (order_uid, date_created, customer_id, file)
+		SELECT order_uid, date_created, customer_id, $1 FROM %s
+		ON CONFLICT (order_uid) DO UPDATE SET
+				date_created = EXCLUDED.date_created,
+				customer_id = EXCLUDED.customer_id,
+				file = EXCLUDED.file
+	`, parts.orders)
+	if _, err := tx.ExecContext(ctx, index, path); err != nil {
+		a.logger.Error("failed to index archived orders", "error", err, "file", path)
+		return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+	}
+
+	drop := fmt.Sprintf("DROP TABLE %s, %s, %s, %s", parts.items, parts.payment, parts.delivery, parts.orders)
+	if _, err := tx.ExecContext(ctx, drop); err != nil {
+		a.logger.Error("failed to drop exported partitions", "error", err, "month", month.Format(partitionMonth))
+		return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	return nil
+}
+
+// writeOrders пишет заказы секций построчно в JSON через временный файл,
+// чтобы в path не остался обрезанный архив
+func (a *PostgresOrderArchive) writeOrders(ctx context.Context, tx *sqlx.Tx, parts orderTables, path string) error {
+	rows, err := tx.QueryContext(ctx, orderRowsSelect(parts)+`
+		ORDER BY o.order_uid, i.chrt_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	if err := scanOrderRows(rows, func(order entities.Order) error {
+		return enc.Encode(order)
+	}); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// eraseArchivedCustomerTx анонимизирует доставку заказов клиента в файлах архива (mode: file)
+// так же, как EraseCustomer в таблицах, и возвращает ID этих заказов. Файл переписывается
+// через временный и заменяется целиком. Файлы, выгруженные до миграции 0011, просматриваются
+// все, а customer_id их заказов заполняется, чтобы следующие удаления их не читали
+func eraseArchivedCustomerTx(ctx context.Context, tx *sqlx.Tx, logger domainrepo.Logger, customerID string) ([]string, error) {
+	if _, err := tx.ExecContext(ctx, lockArchiveFiles); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	query := "SELECT DISTINCT file FROM archived_order_files WHERE customer_id = $1 OR customer_id IS NULL ORDER BY file"
+	if err := tx.SelectContext(ctx, &files, query, customerID); err != nil {
+		return nil, err
+	}
+
+	var erased, uids, customers []string
+	for _, path := range files {
+		err := eraseCustomerInFile(path, customerID, func(order entities.Order) {
+			if order.CustomerID == customerID {
+				erased = append(erased, order.OrderUID)
+			}
+			uids = append(uids, order.OrderUID)
+			customers = append(customers, order.CustomerID)
+		})
+		if errors.Is(err, os.ErrNotExist) {
+			// нет файла - нет и данных в нем; индекс остается, чтобы пропажа была видна при чтении
+			logger.Warn("archive file is missing, nothing to erase", "file", path, "customer_id", customerID)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if len(uids) > 0 {
+		backfill := `
+			UPDATE archived_order_files f
+			SET customer_id = v.customer_id
+			FROM unnest($1::text[], $2::text[]) AS v(order_uid, customer_id)
+			WHERE f.order_uid = v.order_uid AND f.customer_id IS NULL
+		`
+		if _, err := tx.ExecContext(ctx, backfill, pq.Array(uids), pq.Array(customers)); err != nil {
+			return nil, err
+		}
+	}
+	return erased, nil
+}
+
+// eraseCustomerInFile вызывает seen для каждого заказа файла и заменяет PII доставки
+// заказов клиента на ErasedValue. Файл перезаписывается, только если что-то изменилось
+func eraseCustomerInFile(path, customerID string, seen func(entities.Order)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	tmp := path + ".erase.tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer out.Close()
+
+	zw := gzip.NewWriter(out)
+	enc := json.NewEncoder(zw)
+	dec := json.NewDecoder(zr)
+	changed := false
+	for {
+		var order entities.Order
+		if err := dec.Decode(&order); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		seen(order)
+		if order.CustomerID == customerID && order.Delivery != order.Delivery.Erased() {
+			order.Delivery = order.Delivery.Erased()
+			changed = true
+		}
+		if err := enc.Encode(order); err != nil {
+			return err
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (a *PostgresOrderArchive) GetArchivedOrder(ctx context.Context, id string) (entities.Order, error) {
+	var (
+		order entities.Order
+		err   error
+	)
+	if a.mode == ArchiveModeFile {
+		order, err = a.readArchivedOrder(ctx, id)
+	} else {
+		order, err = a.archived.GetOrder(ctx, id)
+	}
+	if err != nil {
+		return entities.Order{}, err
+	}
+
+	order.Archived = true
+	return order, nil
+}
+
+// readArchivedOrder находит файл заказа по archived_order_files и читает его до нужной строки
+func (a *PostgresOrderArchive) readArchivedOrder(ctx context.Context, id string) (entities.Order, error) {
+	var path string
+	err := a.db.QueryRowContext(ctx, "SELECT file FROM archived_order_files WHERE order_uid = $1", id).Scan(&path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		a.logger.Error("failed to look up archived order", "error", err, "order_uid", id)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	order, err := findArchivedOrder(path, id)
+	if err != nil {
+		a.logger.Error("failed to read archived order", "error", err, "order_uid", id, "file", path)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return order, nil
+}
+
+func findArchivedOrder(path, id string) (entities.Order, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return entities.Order{}, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return entities.Order{}, err
+	}
+	defer zr.Close()
+
+	dec := json.NewDecoder(zr)
+	for {
+		var order entities.Order
+		if err := dec.Decode(&order); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entities.Order{}, fmt.Errorf("order %s is indexed but missing in the file", id)
+			}
+			return entities.Order{}, err
+		}
+		if order.OrderUID == id {
+			return order, nil
+		}
+	}
+}
+
+func partitionName(table string, month time.Time) string {
+	return table + "_p" + month.Format(partitionMonth)
+}
+
+// monthPartitions - экранированные имена секций месяца для подстановки в запросы
+func monthPartitions(month time.Time) orderTables {
+	return orderTables{
+		orders:   pq.QuoteIdentifier(partitionName("orders", month)),
+		delivery: pq.QuoteIdentifier(partitionName("delivery", month)),
+		payment:  pq.QuoteIdentifier(partitionName("payment", month)),
+		items:    pq.QuoteIdentifier(partitionName("items", month)),
+	}
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
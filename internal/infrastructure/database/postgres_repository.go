@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -19,20 +20,41 @@ type PostgresOrderRepository struct {
 	db               *sqlx.DB
 	logger           domainrepo.Logger
 	statementTimeout time.Duration
+	// replicas - чтения с реплик; nil - все через db
+	replicas *ReplicaRouter
+	// tables - откуда GetOrder читает заказ: рабочие таблицы или архивные (PostgresOrderArchive)
+	tables orderTables
 }
 
-func NewPostgresOrderRepository(db *sqlx.DB, logger domainrepo.Logger, statementTimeout time.Duration) (*PostgresOrderRepository, error) {
+type orderTables struct {
+	orders, delivery, payment, items string
+}
+
+var (
+	liveOrderTables     = orderTables{"orders", "delivery", "payment", "items"}
+	archivedOrderTables = orderTables{"orders_archive", "delivery_archive", "payment_archive", "items_archive"}
+)
+
+func NewPostgresOrderRepository(db *sqlx.DB, logger domainrepo.Logger, statementTimeout time.Duration, opts ...PostgresOption) (*PostgresOrderRepository, error) {
 	if db == nil {
 		return nil, errors.New("db is nil")
 	}
+	o := newPostgresOptions(opts)
 	return &PostgresOrderRepository{
 		db:               db,
 		logger:           logger,
 		statementTimeout: statementTimeout,
+		replicas:         o.replicas,
+		tables:           liveOrderTables,
 	}, nil
 }
 
 func (r *PostgresOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	if err := checkItemKeys(order); err != nil {
+		r.logger.Warn("order items repeat chrt_id", "order_uid", order.OrderUID)
+		return err
+	}
+
 	if r.statementTimeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, r.statementTimeout)
@@ -45,6 +67,10 @@ func (r *PostgresOrderRepository) SaveOrder(ctx context.Context, order entities.
 	}
 	defer tx.Rollback()
 
+	if err := r.lockOrderTx(ctx, tx, order); err != nil {
+		return err
+	}
+
 	if err := r.saveOrderTx(ctx, tx, order); err != nil {
 		return err
 	}
@@ -65,6 +91,65 @@ func (r *PostgresOrderRepository) SaveOrder(ctx context.Context, order entities.
 		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
 	}
 
+	r.replicas.written(order.OrderUID)
+	return nil
+}
+
+// lockOrderTx сериализует записи одного заказа и убирает его версию с другим date_created.
+// Таблицы секционированы по date_created, и ключ (order_uid, date_created) не мешает двум
+// версиям заказа лечь в разные секции: уникальность order_uid и track_number держится здесь
+func (r *PostgresOrderRepository) lockOrderTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", order.OrderUID); err != nil {
+		r.logger.Error("failed to lock order", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	if err := r.checkOrderConflictsTx(ctx, tx, order); err != nil {
+		return err
+	}
+
+	// доставку, оплату и товары старой версии удаляет триггер orders_delete_dependents
+	query := "DELETE FROM orders WHERE order_uid = $1 AND date_created <> $2"
+	if _, err := tx.ExecContext(ctx, query, order.OrderUID, order.DateCreated); err != nil {
+		r.logger.Error("failed to move order between partitions", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	return nil
+}
+
+// checkOrderConflictsTx заменяет UNIQUE (track_number) из 0001, которое секционированная таблица
+// держать не может, и не дает сохранить заново заказ из архива: иначе он был бы и в рабочих
+// таблицах, и в архиве. Замок на номер не дает двум заказам с одним номером пройти проверку
+// одновременно. Заказы, выгруженные в файлы, проверяются только по order_uid - номеров их
+// архив не хранит
+func (r *PostgresOrderRepository) checkOrderConflictsTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('track_number'), hashtext($1))", order.TrackNumber); err != nil {
+		r.logger.Error("failed to lock track number", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	var archived, taken bool
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM orders_archive WHERE order_uid = $1)
+				OR EXISTS (SELECT 1 FROM archived_order_files WHERE order_uid = $1),
+			EXISTS (SELECT 1 FROM orders WHERE track_number = $2 AND order_uid <> $1)
+				OR EXISTS (SELECT 1 FROM orders_archive WHERE track_number = $2 AND order_uid <> $1)
+	`
+	if err := tx.QueryRowContext(ctx, query, order.OrderUID, order.TrackNumber).Scan(&archived, &taken); err != nil {
+		r.logger.Error("failed to check order conflicts", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	if archived {
+		r.logger.Warn("archived order cannot be saved again", "order_uid", order.OrderUID)
+		return orderConflict("order_uid", entities.ErrOrderArchived)
+	}
+	if taken {
+		r.logger.Warn("track number is already used", "order_uid", order.OrderUID, "track_number", order.TrackNumber)
+		return orderConflict("track_number", entities.ErrTrackNumberTaken)
+	}
 	return nil
 }
 
@@ -76,7 +161,7 @@ func (r *PostgresOrderRepository) saveOrderTx(ctx context.Context, tx *sqlx.Tx,
 		) VALUES (
 				:order_uid, :track_number, :entry, :locale, :internal_signature,
 				:customer_id, :delivery_service, :shardkey, :sm_id, :date_created, :oof_shard
-		) ON CONFLICT (order_uid) DO UPDATE SET
+		) ON CONFLICT (order_uid, date_created) DO UPDATE SET
 				track_number = EXCLUDED.track_number,
 				entry = EXCLUDED.entry,
 				locale = EXCLUDED.locale,
@@ -85,8 +170,8 @@ func (r *PostgresOrderRepository) saveOrderTx(ctx context.Context, tx *sqlx.Tx,
 				delivery_service = EXCLUDED.delivery_service,
 				shardkey = EXCLUDED.shardkey,
 				sm_id = EXCLUDED.sm_id,
-				date_created = EXCLUDED.date_created,
-				oof_shard = EXCLUDED.oof_shard
+				oof_shard = EXCLUDED.oof_shard,
+				updated_at = NOW()
     `
 
 	_, err := tx.NamedExecContext(ctx, query, order)
@@ -101,10 +186,10 @@ func (r *PostgresOrderRepository) saveOrderTx(ctx context.Context, tx *sqlx.Tx,
 func (r *PostgresOrderRepository) saveDeliveryTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
 	query := `
 		INSERT INTO delivery (
-				order_uid, name, phone, zip, city, address, region, email
+				order_uid, date_created, name, phone, zip, city, address, region, email
 		) VALUES (
-				:order_uid, :name, :phone, :zip, :city, :address, :region, :email
-		) ON CONFLICT (order_uid) DO UPDATE SET
+				:order_uid, :date_created, :name, :phone, :zip, :city, :address, :region, :email
+		) ON CONFLICT (order_uid, date_created) DO UPDATE SET
 				name = EXCLUDED.name,
 				phone = EXCLUDED.phone,
 				zip = EXCLUDED.zip,
@@ -115,14 +200,15 @@ func (r *PostgresOrderRepository) saveDeliveryTx(ctx context.Context, tx *sqlx.T
     `
 
 	deliveryMap := map[string]interface{}{
-		"order_uid": order.OrderUID,
-		"name":      order.Delivery.Name,
-		"phone":     order.Delivery.Phone,
-		"zip":       order.Delivery.Zip,
-		"city":      order.Delivery.City,
-		"address":   order.Delivery.Address,
-		"region":    order.Delivery.Region,
-		"email":     order.Delivery.Email,
+		"order_uid":    order.OrderUID,
+		"date_created": order.DateCreated,
+		"name":         order.Delivery.Name,
+		"phone":        order.Delivery.Phone,
+		"zip":          order.Delivery.Zip,
+		"city":         order.Delivery.City,
+		"address":      order.Delivery.Address,
+		"region":       order.Delivery.Region,
+		"email":        order.Delivery.Email,
 	}
 
 	_, err := tx.NamedExecContext(ctx, query, deliveryMap)
@@ -137,12 +223,12 @@ func (r *PostgresOrderRepository) saveDeliveryTx(ctx context.Context, tx *sqlx.T
 func (r *PostgresOrderRepository) savePaymentTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
 	query := `
 		INSERT INTO payment (
-				order_uid, transaction, request_id, currency, provider,
+				order_uid, date_created, transaction, request_id, currency, provider,
 				amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
 		) VALUES (
-				:order_uid, :transaction, :request_id, :currency, :provider,
+				:order_uid, :date_created, :transaction, :request_id, :currency, :provider,
 				:amount, :payment_dt, :bank, :delivery_cost, :goods_total, :custom_fee
-		) ON CONFLICT (order_uid) DO UPDATE SET
+		) ON CONFLICT (order_uid, date_created) DO UPDATE SET
 				transaction = EXCLUDED.transaction,
 				request_id = EXCLUDED.request_id,
 				currency = EXCLUDED.currency,
@@ -157,6 +243,7 @@ func (r *PostgresOrderRepository) savePaymentTx(ctx context.Context, tx *sqlx.Tx
 
 	paymentMap := map[string]interface{}{
 		"order_uid":     order.OrderUID,
+		"date_created":  order.DateCreated,
 		"transaction":   order.Payment.Transaction,
 		"request_id":    order.Payment.RequestID,
 		"currency":      order.Payment.Currency,
@@ -188,10 +275,10 @@ func (r *PostgresOrderRepository) saveItemsTx(ctx context.Context, tx *sqlx.Tx,
 
 	query := `
 		INSERT INTO items (
-				chrt_id, order_uid, track_number, price, rid, name,
+				chrt_id, order_uid, date_created, track_number, price, rid, name,
 				sale, size, total_price, nm_id, brand, status
 		) VALUES (
-				:chrt_id, :order_uid, :track_number, :price, :rid, :name,
+				:chrt_id, :order_uid, :date_created, :track_number, :price, :rid, :name,
 				:sale, :size, :total_price, :nm_id, :brand, :status
 		)
     `
@@ -200,6 +287,7 @@ func (r *PostgresOrderRepository) saveItemsTx(ctx context.Context, tx *sqlx.Tx,
 		itemMap := map[string]interface{}{
 			"chrt_id":      item.ChrtID,
 			"order_uid":    order.OrderUID,
+			"date_created": order.DateCreated,
 			"track_number": item.TrackNumber,
 			"price":        item.Price,
 			"rid":          item.RID,
@@ -223,91 +311,176 @@ func (r *PostgresOrderRepository) saveItemsTx(ctx context.Context, tx *sqlx.Tx,
 }
 
 func (r *PostgresOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
-	query := `
-		SELECT 
-			o.*,
-			d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
-			p.transaction, p.request_id, p.currency, p.provider, p.amount,
-			p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee,
-			i.chrt_id, i.track_number, i.price, i.rid, i.name as item_name,
-			i.sale, i.size, i.total_price, i.nm_id, i.brand, i.status
-		FROM orders o
-		LEFT JOIN delivery d ON o.order_uid = d.order_uid
-		LEFT JOIN payment p ON o.order_uid = p.order_uid
-		LEFT JOIN items i ON o.order_uid = i.order_uid
+	query := orderRowsSelect(r.tables) + `
 		WHERE o.order_uid = $1
+		ORDER BY i.chrt_id
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, id)
+	db := r.replicas.reader(ctx, r.db, id)
+	rows, err := db.QueryContext(ctx, query, id)
 	if err != nil {
+		r.replicas.failed(ctx, db, err)
 		r.logger.Error("failed to get order", "error", err, "order_uid", id)
 		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
 	}
 	defer rows.Close()
 
 	var order entities.Order
-	var items []entities.Item
+	err = scanOrderRows(rows, func(o entities.Order) error {
+		order = o
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to scan order", "error", err, "order_uid", id)
+		return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	if order.OrderUID == "" {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+// orderRowsSelect - заказы с доставкой, оплатой и товарами, по строке на товар
+func orderRowsSelect(t orderTables) string {
+	return fmt.Sprintf(`
+		SELECT 
+			o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
+			o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard, o.updated_at,
+			d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
+			p.transaction, p.request_id, p.currency, p.provider, p.amount,
+			p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee,
+			i.chrt_id, i.track_number, i.price, i.rid, i.name as item_name,
+			i.sale, i.size, i.total_price, i.nm_id, i.brand, i.status
+		FROM %s o
+		LEFT JOIN %s d ON o.order_uid = d.order_uid AND o.date_created = d.date_created
+		LEFT JOIN %s p ON o.order_uid = p.order_uid AND o.date_created = p.date_created
+		LEFT JOIN %s i ON o.order_uid = i.order_uid AND o.date_created = i.date_created
+	`, t.orders, t.delivery, t.payment, t.items)
+}
+
+// scanOrderRows собирает заказы из строк orderRowsSelect, упорядоченных по order_uid,
+// и передает каждый в emit, как только дочитаны его товары
+func scanOrderRows(rows *sql.Rows, emit func(entities.Order) error) error {
+	var order entities.Order
 
 	for rows.Next() {
+		var next entities.Order
 		var item entities.Item
-		var delivery entities.Delivery
-		var payment entities.Payment
 
 		err := rows.Scan(
-			&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale,
-			&order.InternalSig, &order.CustomerID, &order.DeliveryService,
-			&order.ShardKey, &order.SMID, &order.DateCreated, &order.OOFShard,
-			&delivery.Name, &delivery.Phone, &delivery.Zip, &delivery.City,
-			&delivery.Address, &delivery.Region, &delivery.Email,
-			&payment.Transaction, &payment.RequestID, &payment.Currency,
-			&payment.Provider, &payment.Amount, &payment.PaymentDT, &payment.Bank,
-			&payment.DeliveryCost, &payment.GoodsTotal, &payment.CustomFee,
+			&next.OrderUID, &next.TrackNumber, &next.Entry, &next.Locale,
+			&next.InternalSig, &next.CustomerID, &next.DeliveryService,
+			&next.ShardKey, &next.SMID, &next.DateCreated, &next.OOFShard, &next.UpdatedAt,
+			&next.Delivery.Name, &next.Delivery.Phone, &next.Delivery.Zip, &next.Delivery.City,
+			&next.Delivery.Address, &next.Delivery.Region, &next.Delivery.Email,
+			&next.Payment.Transaction, &next.Payment.RequestID, &next.Payment.Currency,
+			&next.Payment.Provider, &next.Payment.Amount, &next.Payment.PaymentDT, &next.Payment.Bank,
+			&next.Payment.DeliveryCost, &next.Payment.GoodsTotal, &next.Payment.CustomFee,
 			&item.ChrtID, &item.TrackNumber, &item.Price, &item.RID, &item.Name,
 			&item.Sale, &item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
 		)
 		if err != nil {
-			r.logger.Error("failed to scan order", "error", err, "order_uid", id)
-			return entities.Order{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+			return err
 		}
 
-		order.Delivery = delivery
-		order.Payment = payment
-		items = append(items, item)
+		if next.OrderUID != order.OrderUID {
+			if order.OrderUID != "" {
+				if err := emit(order); err != nil {
+					return err
+				}
+			}
+			order = next
+		}
+		order.Items = append(order.Items, item)
 	}
-
-	if order.OrderUID == "" {
-		return entities.Order{}, domain.ErrOrderNotFound
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	order.Items = items
-	return order, nil
+	if order.OrderUID != "" {
+		return emit(order)
+	}
+	return nil
 }
 
-func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
-	mainQuery := `
+// ordersSelect - общая часть выборки заказов с доставкой и оплатой; items догружаются отдельно
+const ordersSelect = `
 		SELECT 
 				o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
-				o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
+				o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard, o.updated_at,
 				d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
 				p.transaction, p.request_id, p.currency, p.provider, p.amount,
 				p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
 		FROM orders o
-		LEFT JOIN delivery d ON o.order_uid = d.order_uid
-		LEFT JOIN payment p ON o.order_uid = p.order_uid
+		LEFT JOIN delivery d ON o.order_uid = d.order_uid AND o.date_created = d.date_created
+		LEFT JOIN payment p ON o.order_uid = p.order_uid AND o.date_created = p.date_created
+`
+
+func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	query := ordersSelect + `
 		ORDER BY o.order_uid
 		LIMIT $1 OFFSET $2
   `
+	return r.queryOrders(ctx, query, limit, offset)
+}
+
+func (r *PostgresOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	query := `
+		SELECT date_created, order_uid
+		FROM orders
+		ORDER BY date_created DESC, order_uid DESC
+		LIMIT 1 OFFSET $1
+	`
+
+	var cursor entities.OrderCursor
+	db := r.replicas.reader(ctx, r.db, "")
+	err := db.QueryRowContext(ctx, query, n).Scan(&cursor.DateCreated, &cursor.OrderUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.OrderCursor{}, nil
+	}
+	if err != nil {
+		r.replicas.failed(ctx, db, err)
+		r.logger.Error("failed to get recent orders cursor", "error", err, "n", n)
+		return entities.OrderCursor{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return cursor, nil
+}
+
+func (r *PostgresOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	if after.IsZero() {
+		query := ordersSelect + `
+		ORDER BY o.date_created, o.order_uid
+		LIMIT $1
+  `
+		return r.queryOrders(ctx, query, limit)
+	}
+
+	query := ordersSelect + `
+		WHERE (o.date_created, o.order_uid) > ($1::timestamp, $2::text)
+		ORDER BY o.date_created, o.order_uid
+		LIMIT $3
+  `
+	return r.queryOrders(ctx, query, after.DateCreated, after.OrderUID, limit)
+}
 
-	rows, err := r.db.QueryContext(ctx, mainQuery, limit, offset)
+// queryOrders выполняет выборку на основе ordersSelect и дописывает к заказам items;
+// оба запроса идут в одно соединение, чтобы заказы и товары пришли с одной реплики
+func (r *PostgresOrderRepository) queryOrders(ctx context.Context, query string, args ...interface{}) ([]entities.Order, error) {
+	db := r.replicas.reader(ctx, r.db, "")
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		r.logger.Error("failed to get all orders", "error", err)
+		r.replicas.failed(ctx, db, err)
+		r.logger.Error("failed to get orders", "error", err)
 		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
 	}
 	defer rows.Close()
 
 	var orders []entities.Order
 	var orderUIDs []string
-	ordersMap := make(map[string]*entities.Order)
+	// индексы, а не указатели: append может перенести orders в другой массив
+	ordersIdx := make(map[string]int)
 
 	for rows.Next() {
 		var o entities.Order
@@ -316,7 +489,7 @@ func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offse
 
 		err := rows.Scan(
 			&o.OrderUID, &o.TrackNumber, &o.Entry, &o.Locale, &o.InternalSig,
-			&o.CustomerID, &o.DeliveryService, &o.ShardKey, &o.SMID, &o.DateCreated, &o.OOFShard,
+			&o.CustomerID, &o.DeliveryService, &o.ShardKey, &o.SMID, &o.DateCreated, &o.OOFShard, &o.UpdatedAt,
 			&d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email,
 			&p.Transaction, &p.RequestID, &p.Currency, &p.Provider, &p.Amount,
 			&p.PaymentDT, &p.Bank, &p.DeliveryCost, &p.GoodsTotal, &p.CustomFee,
@@ -330,7 +503,7 @@ func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offse
 		o.Payment = p
 		orders = append(orders, o)
 		orderUIDs = append(orderUIDs, o.OrderUID)
-		ordersMap[o.OrderUID] = &orders[len(orders)-1]
+		ordersIdx[o.OrderUID] = len(orders) - 1
 	}
 
 	if len(orderUIDs) == 0 {
@@ -346,8 +519,9 @@ func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offse
 		ORDER BY order_uid, chrt_id
   `
 
-	itemRows, err := r.db.QueryContext(ctx, itemsQuery, pq.Array(orderUIDs))
+	itemRows, err := db.QueryContext(ctx, itemsQuery, pq.Array(orderUIDs))
 	if err != nil {
+		r.replicas.failed(ctx, db, err)
 		r.logger.Error("failed to get items for orders", "error", err)
 		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
 	}
@@ -366,8 +540,8 @@ func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offse
 			continue
 		}
 
-		if order, exists := ordersMap[orderUID]; exists {
-			order.Items = append(order.Items, item)
+		if i, exists := ordersIdx[orderUID]; exists {
+			orders[i].Items = append(orders[i].Items, item)
 		}
 	}
 
@@ -377,8 +551,10 @@ func (r *PostgresOrderRepository) GetAllOrders(ctx context.Context, limit, offse
 func (r *PostgresOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
 	query := "SELECT COUNT(*) FROM orders"
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	db := r.replicas.reader(ctx, r.db, "")
+	err := db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
+		r.replicas.failed(ctx, db, err)
 		r.logger.Error("failed to get orders count", "error", err)
 		return 0, fmt.Errorf("%w: %v", ErrQueryFailed, err)
 	}
@@ -402,6 +578,7 @@ func (r *PostgresOrderRepository) DeleteOrder(ctx context.Context, id string) er
 		return domain.ErrOrderNotFound
 	}
 
+	r.replicas.written(id)
 	return nil
 }
 
@@ -412,6 +589,7 @@ func (r *PostgresOrderRepository) ClearOrders(ctx context.Context) error {
 		r.logger.Error("failed to clear orders", "error", err)
 		return fmt.Errorf("%w: %v", ErrOrderClearFailed, err)
 	}
+	r.replicas.written("")
 	return nil
 }
 
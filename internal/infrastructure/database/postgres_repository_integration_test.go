@@ -3,6 +3,8 @@ package repository
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -15,10 +17,16 @@ import (
 
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger"
 )
 
-func setupTestDB(t *testing.T) *sqlx.DB {
+// migrationsGlob - схема теста строится теми же миграциями, что применяет сервис;
+// Glob отдает файлы по имени, то есть по номеру миграции
+const migrationsGlob = "migrations/*.up.sql"
+
+func setupTestDB(t testing.TB) *sqlx.DB {
 	ctx := context.Background()
 
 	req := testcontainers.ContainerRequest{
@@ -51,63 +59,15 @@ func setupTestDB(t *testing.T) *sqlx.DB {
 	db, err := sqlx.Connect("postgres", dsn)
 	require.NoError(t, err)
 
-	_, err = db.Exec(`
-		CREATE TABLE orders (
-			order_uid TEXT PRIMARY KEY NOT NULL,
-			track_number TEXT NOT NULL,
-			entry TEXT NOT NULL,
-			locale TEXT NOT NULL,
-			internal_signature TEXT,
-			customer_id TEXT NOT NULL,
-			delivery_service TEXT NOT NULL,
-			shardkey TEXT,
-			sm_id INTEGER NOT NULL,
-			date_created TIMESTAMP NOT NULL,
-			oof_shard TEXT
-		);
-		
-		CREATE TABLE delivery (
-			order_uid TEXT PRIMARY KEY NOT NULL,
-			name TEXT NOT NULL,
-			phone TEXT NOT NULL,
-			zip TEXT NOT NULL,
-			city TEXT NOT NULL,
-			address TEXT NOT NULL,
-			region TEXT NOT NULL,
-			email TEXT
-		);
-		
-		CREATE TABLE payment (
-			order_uid TEXT PRIMARY KEY NOT NULL,
-			transaction TEXT NOT NULL,
-			request_id TEXT,
-			currency TEXT NOT NULL,
-			provider TEXT NOT NULL,
-			amount INTEGER NOT NULL,
-			payment_dt BIGINT NOT NULL,
-			bank TEXT,
-			delivery_cost INTEGER NOT NULL,
-			goods_total INTEGER NOT NULL,
-			custom_fee INTEGER NOT NULL
-		);
-		
-		CREATE TABLE items (
-			chrt_id INTEGER NOT NULL,
-			order_uid TEXT NOT NULL,
-			track_number TEXT NOT NULL,
-			price INTEGER NOT NULL,
-			rid TEXT NOT NULL,
-			name TEXT NOT NULL,
-			sale INTEGER NOT NULL,
-			size TEXT NOT NULL,
-			total_price INTEGER NOT NULL,
-			nm_id INTEGER NOT NULL,
-			brand TEXT NOT NULL,
-			status INTEGER NOT NULL,
-			PRIMARY KEY (chrt_id, order_uid)
-		);
-	`)
+	files, err := filepath.Glob(migrationsGlob)
 	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		migration, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, err = db.Exec(string(migration))
+		require.NoError(t, err, file)
+	}
 
 	t.Cleanup(func() {
 		db.Close()
@@ -128,276 +88,250 @@ func TestPostgresOrderRepository_Integration(t *testing.T) {
 	repo, err := NewPostgresOrderRepository(db, logger, 30*time.Second)
 	require.NoError(t, err)
 
-	t.Run("Save and Get Order", func(t *testing.T) {
+	// контейнер один на весь тест, поэтому каждый подтест контракта начинает с очистки
+	t.Run("Contract", func(t *testing.T) {
+		repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+			require.NoError(t, repo.ClearOrders(context.Background()))
+			return repo
+		})
+	})
+
+	documents, err := NewPostgresDocumentOrderRepository(db, logger, 30*time.Second)
+	require.NoError(t, err)
+
+	t.Run("Document Contract", func(t *testing.T) {
+		repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+			require.NoError(t, documents.ClearOrders(context.Background()))
+			return documents
+		})
+	})
+
+	t.Run("Document Backfill", func(t *testing.T) {
 		ctx := context.Background()
+		require.NoError(t, repo.ClearOrders(ctx))
+		require.NoError(t, documents.ClearOrders(ctx))
 
-		order := entities.Order{
-			OrderUID:        "test-order-1",
-			TrackNumber:     "TEST123",
-			Entry:           "WBIL",
-			Locale:          "en",
-			InternalSig:     "",
-			CustomerID:      "test-customer",
-			DeliveryService: "meest",
-			ShardKey:        "9",
-			SMID:            99,
-			DateCreated:     "2021-11-26T06:22:19Z",
-			OOFShard:        "1",
-			Delivery: entities.Delivery{
-				Name:    "Test Testov",
-				Phone:   "+9720000000",
-				Zip:     "2639809",
-				City:    "Kiryat Mozkin",
-				Address: "Ploshad Mira 15",
-				Region:  "Kraiot",
-				Email:   "test@gmail.com",
-			},
-			Payment: entities.Payment{
-				Transaction:  "test-transaction-1",
-				RequestID:    "",
-				Currency:     "USD",
-				Provider:     "wbpay",
-				Amount:       1817,
-				PaymentDT:    1637907727,
-				Bank:         "alpha",
-				DeliveryCost: 1500,
-				GoodsTotal:   317,
-				CustomFee:    0,
-			},
-			Items: []entities.Item{
-				{
-					ChrtID:      9934930,
-					TrackNumber: "TEST123",
-					Price:       453,
-					RID:         "ab4219087a764ae0btest",
-					Name:        "Mascaras",
-					Sale:        30,
-					Size:        "0",
-					TotalPrice:  317,
-					NmID:        2389212,
-					Brand:       "Vivienne Sabo",
-					Status:      202,
-				},
-			},
-		}
+		order := repositorytest.NewOrder("backfill-1", time.Now())
+		order.Items = repositorytest.Items(order.TrackNumber, 1, 3)
+		require.NoError(t, repo.SaveOrder(ctx, order))
+
+		var copied int
+		require.NoError(t, db.GetContext(ctx, &copied, "SELECT backfill_order_documents()"))
+		assert.Equal(t, 1, copied)
 
-		err := repo.SaveOrder(ctx, order)
-		assert.NoError(t, err)
+		got, err := documents.GetOrder(ctx, order.OrderUID)
+		require.NoError(t, err)
+		repositorytest.AssertOrderEqual(t, order, got)
 
-		retrieved, err := repo.GetOrder(ctx, "test-order-1")
-		assert.NoError(t, err)
-		assert.Equal(t, order.OrderUID, retrieved.OrderUID)
-		assert.Equal(t, order.TrackNumber, retrieved.TrackNumber)
-		assert.Len(t, retrieved.Items, 1)
-		assert.Equal(t, order.Items[0].ChrtID, retrieved.Items[0].ChrtID)
+		// повторный запуск не трогает документы, которые не старее таблиц
+		require.NoError(t, db.GetContext(ctx, &copied, "SELECT backfill_order_documents()"))
+		assert.Zero(t, copied)
 
-		orders, err := repo.GetAllOrders(ctx, 100, 0)
-		assert.NoError(t, err)
-		assert.Len(t, orders, 1)
-		assert.Equal(t, order.OrderUID, orders[0].OrderUID)
+		var track string
+		require.NoError(t, db.GetContext(ctx, &track,
+			"SELECT track_number FROM order_documents WHERE document @> $1", `{"items": [{"chrt_id": 2}]}`))
+		assert.Equal(t, order.TrackNumber, track)
 	})
 
-	t.Run("Get Non-Existent Order", func(t *testing.T) {
+	t.Run("Document Erasure And Flags", func(t *testing.T) {
 		ctx := context.Background()
-
-		_, err := repo.GetOrder(ctx, "non-existent")
-		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		require.NoError(t, documents.ClearOrders(ctx))
+
+		order := repositorytest.NewOrder("doc-erase-1", time.Now())
+		require.NoError(t, documents.SaveOrder(ctx, order))
+
+		flags, err := NewPostgresOrderFlagRepository(db, logger)
+		require.NoError(t, err)
+		require.NoError(t, flags.ReplaceFlags(ctx, order.OrderUID, []entities.OrderFlag{{Rule: "goods_total", Field: "payment.goods_total", Message: "mismatch"}}))
+
+		customers, err := NewPostgresDocumentCustomerDataRepository(db, logger)
+		require.NoError(t, err)
+
+		ids, err := customers.GetCustomerOrderIDs(ctx, order.CustomerID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{order.OrderUID}, ids)
+
+		erasure, err := customers.EraseCustomer(ctx, entities.Erasure{CustomerID: order.CustomerID, RequestedBy: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{order.OrderUID}, erasure.OrderUIDs)
+
+		got, err := documents.GetOrder(ctx, order.OrderUID)
+		require.NoError(t, err)
+		// остальной документ не меняется
+		want := order
+		want.Delivery = order.Delivery.Erased()
+		repositorytest.AssertOrderEqual(t, want, got)
+
+		// удаление документа снимает его флаги
+		require.NoError(t, documents.DeleteOrder(ctx, order.OrderUID))
+		left, err := flags.GetFlags(ctx, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, left)
 	})
 
-	t.Run("Update Order", func(t *testing.T) {
+	t.Run("Order Versions", func(t *testing.T) {
 		ctx := context.Background()
+		require.NoError(t, repo.ClearOrders(ctx))
+
+		versions, err := NewPostgresOrderVersionRepository(db, logger)
+		require.NoError(t, err)
 
 		order := entities.Order{
-			OrderUID:        "test-order-2",
-			TrackNumber:     "TEST456",
+			OrderUID:        "test-order-5",
+			TrackNumber:     "TEST202",
 			Entry:           "WBIL",
 			Locale:          "en",
-			InternalSig:     "",
 			CustomerID:      "test-customer",
 			DeliveryService: "meest",
-			ShardKey:        "9",
 			SMID:            99,
-			DateCreated:     "2021-11-26T06:22:19Z",
-			OOFShard:        "1",
-			Delivery: entities.Delivery{
-				Name:    "Test Testov",
-				Phone:   "+9720000000",
-				Zip:     "2639809",
-				City:    "Kiryat Mozkin",
-				Address: "Ploshad Mira 15",
-				Region:  "Kraiot",
-				Email:   "test@gmail.com",
-			},
-			Payment: entities.Payment{
-				Transaction:  "test-transaction-2",
-				RequestID:    "",
-				Currency:     "USD",
-				Provider:     "wbpay",
-				Amount:       1817,
-				PaymentDT:    1637907727,
-				Bank:         "alpha",
-				DeliveryCost: 1500,
-				GoodsTotal:   317,
-				CustomFee:    0,
-			},
-			Items: []entities.Item{
-				{
-					ChrtID:      9934931,
-					TrackNumber: "TEST456",
-					Price:       453,
-					RID:         "ab4219087a764ae0btest",
-					Name:        "Mascaras",
-					Sale:        30,
-					Size:        "0",
-					TotalPrice:  317,
-					NmID:        2389212,
-					Brand:       "Vivienne Sabo",
-					Status:      202,
-				},
-			},
+			DateCreated:     entities.NewTimestamp(time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)),
+			Delivery:        entities.Delivery{Name: "Test Testov", Phone: "+9720000000", Email: "test@gmail.com"},
+			Payment:         entities.Payment{Transaction: "test-transaction-5", Currency: "USD", Provider: "wbpay"},
 		}
+		require.NoError(t, repo.SaveOrder(ctx, order))
+
+		got, err := versions.GetVersions(ctx, []string{order.OrderUID, "missing"})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		before := got[order.OrderUID]
 
-		err := repo.SaveOrder(ctx, order)
-		assert.NoError(t, err)
+		latest, err := versions.GetLatestVersion(ctx)
+		require.NoError(t, err)
+		assert.False(t, latest.Before(before))
 
-		order.Delivery.Name = "Updated Name"
-		order.Payment.Amount = 2000
-		order.Items[0].Price = 500
+		time.Sleep(10 * time.Millisecond)
+		order.TrackNumber = "TEST203"
+		require.NoError(t, repo.SaveOrder(ctx, order))
 
-		err = repo.SaveOrder(ctx, order)
-		assert.NoError(t, err)
+		got, err = versions.GetVersions(ctx, []string{order.OrderUID})
+		require.NoError(t, err)
+		assert.True(t, got[order.OrderUID].After(before))
 
-		retrieved, err := repo.GetOrder(ctx, "test-order-2")
-		assert.NoError(t, err)
-		assert.Equal(t, "Updated Name", retrieved.Delivery.Name)
-		assert.Equal(t, 2000, retrieved.Payment.Amount)
-		assert.Equal(t, 500, retrieved.Items[0].Price)
+		ids, err := versions.GetUpdatedSince(ctx, before, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{order.OrderUID}, ids)
 	})
 
-	t.Run("Delete Order", func(t *testing.T) {
+	t.Run("Partition Move", func(t *testing.T) {
 		ctx := context.Background()
-
-		order := entities.Order{
-			OrderUID:        "test-order-3",
-			TrackNumber:     "TEST789",
-			Entry:           "WBIL",
-			Locale:          "en",
-			InternalSig:     "",
-			CustomerID:      "test-customer",
-			DeliveryService: "meest",
-			ShardKey:        "9",
-			SMID:            99,
-			DateCreated:     "2021-11-26T06:22:19Z",
-			OOFShard:        "1",
-			Delivery: entities.Delivery{
-				Name:    "Test Testov",
-				Phone:   "+9720000000",
-				Zip:     "2639809",
-				City:    "Kiryat Mozkin",
-				Address: "Ploshad Mira 15",
-				Region:  "Kraiot",
-				Email:   "test@gmail.com",
-			},
-			Payment: entities.Payment{
-				Transaction:  "test-transaction-3",
-				RequestID:    "",
-				Currency:     "USD",
-				Provider:     "wbpay",
-				Amount:       1817,
-				PaymentDT:    1637907727,
-				Bank:         "alpha",
-				DeliveryCost: 1500,
-				GoodsTotal:   317,
-				CustomFee:    0,
-			},
-			Items: []entities.Item{
-				{
-					ChrtID:      9934932,
-					TrackNumber: "TEST789",
-					Price:       453,
-					RID:         "ab4219087a764ae0btest",
-					Name:        "Mascaras",
-					Sale:        30,
-					Size:        "0",
-					TotalPrice:  317,
-					NmID:        2389212,
-					Brand:       "Vivienne Sabo",
-					Status:      202,
-				},
-			},
+		require.NoError(t, repo.ClearOrders(ctx))
+
+		order := repositorytest.NewOrder("moved-1", time.Now())
+		order.Items = repositorytest.Items(order.TrackNumber, 1, 3)
+		require.NoError(t, repo.SaveOrder(ctx, order))
+
+		// новая дата переносит заказ в другую секцию вместе с доставкой, оплатой и товарами
+		order.DateCreated = entities.NewTimestamp(time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC))
+		order.Items = repositorytest.Items(order.TrackNumber, 10, 2)
+		require.NoError(t, repo.SaveOrder(ctx, order))
+
+		got, err := repo.GetOrder(ctx, order.OrderUID)
+		require.NoError(t, err)
+		repositorytest.AssertOrderEqual(t, order, got)
+
+		var rows int
+		for _, table := range orderPartitionTables {
+			require.NoError(t, db.GetContext(ctx, &rows, "SELECT COUNT(*) FROM "+table+" WHERE order_uid = $1", order.OrderUID))
+			want := 1
+			if table == "items" {
+				want = 2
+			}
+			assert.Equal(t, want, rows, table)
 		}
+	})
 
-		err := repo.SaveOrder(ctx, order)
-		assert.NoError(t, err)
+	t.Run("Track Number Unique", func(t *testing.T) {
+		ctx := context.Background()
+		require.NoError(t, repo.ClearOrders(ctx))
+
+		first := repositorytest.NewOrder("track-1", time.Now())
+		require.NoError(t, repo.SaveOrder(ctx, first))
 
-		err = repo.DeleteOrder(ctx, "test-order-3")
-		assert.NoError(t, err)
+		// номер занят заказом из другой секции: UNIQUE на секционированной таблице этого не видит
+		second := repositorytest.NewOrder("track-2", time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC))
+		second.TrackNumber = first.TrackNumber
+		second.Items = repositorytest.Items(second.TrackNumber, 1, 1)
+		err := repo.SaveOrder(ctx, second)
+		assert.ErrorIs(t, err, domain.ErrInvalidOrder)
+		assert.ErrorIs(t, err, entities.ErrTrackNumberTaken)
 
-		_, err = repo.GetOrder(ctx, "test-order-3")
+		_, err = repo.GetOrder(ctx, second.OrderUID)
 		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+		// повторное сохранение заказа со своим же номером - не конфликт
+		require.NoError(t, repo.SaveOrder(ctx, first))
 	})
 
-	t.Run("Clear Orders", func(t *testing.T) {
+	// monthsAgo у режимов разный: секция, ушедшая в архивную таблицу, второй раз не создается
+	archiveOrders := func(t *testing.T, archive *PostgresOrderArchive, monthsAgo int) (old, fresh entities.Order) {
 		ctx := context.Background()
+		require.NoError(t, repo.ClearOrders(ctx))
 
-		order := entities.Order{
-			OrderUID:        "test-order-4",
-			TrackNumber:     "TEST101",
-			Entry:           "WBIL",
-			Locale:          "en",
-			InternalSig:     "",
-			CustomerID:      "test-customer",
-			DeliveryService: "meest",
-			ShardKey:        "9",
-			SMID:            99,
-			DateCreated:     "2021-11-26T06:22:19Z",
-			OOFShard:        "1",
-			Delivery: entities.Delivery{
-				Name:    "Test Testov",
-				Phone:   "+9720000000",
-				Zip:     "2639809",
-				City:    "Kiryat Mozkin",
-				Address: "Ploshad Mira 15",
-				Region:  "Kraiot",
-				Email:   "test@gmail.com",
-			},
-			Payment: entities.Payment{
-				Transaction:  "test-transaction-4",
-				RequestID:    "",
-				Currency:     "USD",
-				Provider:     "wbpay",
-				Amount:       1817,
-				PaymentDT:    1637907727,
-				Bank:         "alpha",
-				DeliveryCost: 1500,
-				GoodsTotal:   317,
-				CustomFee:    0,
-			},
-			Items: []entities.Item{
-				{
-					ChrtID:      9934933,
-					TrackNumber: "TEST101",
-					Price:       453,
-					RID:         "ab4219087a764ae0btest",
-					Name:        "Mascaras",
-					Sale:        30,
-					Size:        "0",
-					TotalPrice:  317,
-					NmID:        2389212,
-					Brand:       "Vivienne Sabo",
-					Status:      202,
-				},
-			},
-		}
+		oldMonth := monthStart(time.Now()).AddDate(0, -monthsAgo, 0)
+		_, err := db.ExecContext(ctx, "SELECT create_order_partition($1::date)", oldMonth.Format(time.DateOnly))
+		require.NoError(t, err)
+		require.NoError(t, archive.EnsurePartitions(ctx, time.Now()))
 
-		err := repo.SaveOrder(ctx, order)
-		assert.NoError(t, err)
+		old = repositorytest.NewOrder("archived-"+archive.mode, oldMonth.Add(36*time.Hour))
+		old.Items = repositorytest.Items(old.TrackNumber, 1, 3)
+		fresh = repositorytest.NewOrder("fresh-"+archive.mode, time.Now())
+		require.NoError(t, repo.SaveOrder(ctx, old))
+		require.NoError(t, repo.SaveOrder(ctx, fresh))
 
-		err = repo.ClearOrders(ctx)
-		assert.NoError(t, err)
+		archived, err := archive.ArchiveBefore(ctx, time.Now().AddDate(0, -12, 0))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, archived, 1)
 
-		orders, err := repo.GetAllOrders(ctx, 100, 0)
-		assert.NoError(t, err)
-		assert.Empty(t, orders)
-	})
+		_, err = repo.GetOrder(ctx, old.OrderUID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		count, err := repo.GetOrdersCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		return old, fresh
+	}
+
+	for i, mode := range []string{ArchiveModeTable, ArchiveModeFile} {
+		t.Run("Archive "+mode, func(t *testing.T) {
+			ctx := context.Background()
+			archive, err := NewPostgresOrderArchive(db, logger, mode, t.TempDir())
+			require.NoError(t, err)
+
+			old, fresh := archiveOrders(t, archive, 14+i)
+			lookup := NewArchiveLookupOrderRepository(repo, archive, logger)
+
+			got, err := lookup.GetOrder(ctx, old.OrderUID)
+			require.NoError(t, err)
+			assert.True(t, got.Archived)
+			repositorytest.AssertOrderEqual(t, old, got)
+
+			got, err = lookup.GetOrder(ctx, fresh.OrderUID)
+			require.NoError(t, err)
+			assert.False(t, got.Archived)
+
+			_, err = lookup.GetOrder(ctx, "missing")
+			assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+			// удаление данных клиента доходит и до архивных таблиц, и до файлов архива
+			customers, err := NewPostgresCustomerDataRepository(db, logger)
+			require.NoError(t, err)
+			ids, err := customers.GetCustomerOrderIDs(ctx, old.CustomerID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{old.OrderUID}, ids)
+
+			erasure, err := customers.EraseCustomer(ctx, entities.Erasure{CustomerID: old.CustomerID, RequestedBy: "test"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{old.OrderUID}, erasure.OrderUIDs)
+
+			got, err = lookup.GetOrder(ctx, old.OrderUID)
+			require.NoError(t, err)
+			assert.Equal(t, old.Delivery.Erased(), got.Delivery)
+
+			// заказ из архива не сохраняется заново, иначе он был бы и в архиве, и в рабочих таблицах
+			old.DateCreated = entities.NewTimestamp(time.Now())
+			err = repo.SaveOrder(ctx, old)
+			assert.ErrorIs(t, err, entities.ErrOrderArchived)
+			_, err = repo.GetOrder(ctx, old.OrderUID)
+			assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		})
+	}
 }
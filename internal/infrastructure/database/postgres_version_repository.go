@@ -0,0 +1,91 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/postgres_version_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+type PostgresOrderVersionRepository struct {
+	db     *sqlx.DB
+	logger domainrepo.Logger
+	// table - таблица с order_uid и updated_at: orders или order_documents
+	table string
+}
+
+func NewPostgresOrderVersionRepository(db *sqlx.DB, logger domainrepo.Logger) (*PostgresOrderVersionRepository, error) {
+	return newPostgresVersionRepository(db, logger, "orders")
+}
+
+// NewPostgresDocumentVersionRepository читает версии заказов, хранимых документами
+func NewPostgresDocumentVersionRepository(db *sqlx.DB, logger domainrepo.Logger) (*PostgresOrderVersionRepository, error) {
+	return newPostgresVersionRepository(db, logger, "order_documents")
+}
+
+func newPostgresVersionRepository(db *sqlx.DB, logger domainrepo.Logger, table string) (*PostgresOrderVersionRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &PostgresOrderVersionRepository{
+		db:     db,
+		logger: logger,
+		table:  table,
+	}, nil
+}
+
+func (r *PostgresOrderVersionRepository) GetVersions(ctx context.Context, ids []string) (map[string]time.Time, error) {
+	versions := make(map[string]time.Time, len(ids))
+	if len(ids) == 0 {
+		return versions, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT order_uid, updated_at FROM "+r.table+" WHERE order_uid = ANY($1)", pq.Array(ids))
+	if err != nil {
+		r.logger.Error("failed to get order versions", "error", err, "count", len(ids))
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        string
+			updatedAt time.Time
+		)
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+		versions[id] = updatedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	return versions, nil
+}
+
+func (r *PostgresOrderVersionRepository) GetUpdatedSince(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	var ids []string
+	query := "SELECT order_uid FROM " + r.table + " WHERE updated_at > $1 ORDER BY updated_at DESC, order_uid LIMIT $2"
+	if err := r.db.SelectContext(ctx, &ids, query, since, limit); err != nil {
+		r.logger.Error("failed to get updated orders", "error", err, "since", since)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return ids, nil
+}
+
+func (r *PostgresOrderVersionRepository) GetLatestVersion(ctx context.Context) (time.Time, error) {
+	var latest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, "SELECT MAX(updated_at) FROM "+r.table).Scan(&latest); err != nil {
+		r.logger.Error("failed to get latest order version", "error", err)
+		return time.Time{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return latest.Time, nil
+}
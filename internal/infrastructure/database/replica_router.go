@@ -0,0 +1,232 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/replica_router.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+const (
+	defaultReplicaPingTimeout = 2 * time.Second
+	// pinSweepThreshold - после скольких закрепленных заказов чистить истекшие записи
+	pinSweepThreshold = 1024
+)
+
+// ReplicaRouter распределяет чтения по репликам по кругу, пропуская нездоровые; если здоровых
+// нет, чтение идет в primary. Реплика помечается нездоровой по ошибке запроса или проверке
+// здоровья и возвращается в ротацию следующей успешной проверкой.
+//
+// Read-your-writes: в течение окна после записи заказа он читается с primary. Списки и счетчики
+// закрепляются только массовым изменением (очистка заказов), вместе со всеми заказами: иначе
+// непрерывный поток записей держал бы все списки на primary и реплики простаивали бы
+type ReplicaRouter struct {
+	replicas []*replica
+	next     atomic.Uint64
+	logger   domainrepo.Logger
+	window   time.Duration
+	now      func() time.Time
+
+	mu           sync.Mutex
+	pinnedOrders map[string]time.Time
+	bulkPinnedTo time.Time
+}
+
+type replica struct {
+	db      *sqlx.DB
+	index   int
+	healthy atomic.Bool
+}
+
+// PostgresOption настраивает Postgres-репозитории заказов
+type PostgresOption func(*postgresOptions)
+
+type postgresOptions struct {
+	replicas *ReplicaRouter
+}
+
+// WithReplicas направляет чтения репозитория на реплики роутера
+func WithReplicas(router *ReplicaRouter) PostgresOption {
+	return func(o *postgresOptions) {
+		o.replicas = router
+	}
+}
+
+func newPostgresOptions(opts []PostgresOption) postgresOptions {
+	var o postgresOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewReplicaRouter: readYourWrites - окно чтения с primary после записи, 0 - без закрепления
+func NewReplicaRouter(replicas []*sqlx.DB, l domainrepo.Logger, readYourWrites time.Duration) *ReplicaRouter {
+	r := &ReplicaRouter{
+		logger:       l,
+		window:       readYourWrites,
+		now:          time.Now,
+		pinnedOrders: make(map[string]time.Time),
+	}
+	for i, db := range replicas {
+		rep := &replica{db: db, index: i}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return r
+}
+
+// reader возвращает соединение для чтения заказа id; пустой id - списки и счетчики.
+// Контекст с domainrepo.WithPrimaryRead всегда читает из primary.
+// reader, written и failed работают и на nil-роутере: тогда все идет в primary
+func (r *ReplicaRouter) reader(ctx context.Context, primary *sqlx.DB, id string) *sqlx.DB {
+	if r == nil || len(r.replicas) == 0 || domainrepo.PrimaryReadRequested(ctx) || r.pinned(id) {
+		return primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		rep := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return primary
+}
+
+// written закрепляет чтения заказа id за primary; пустой id - массовое изменение,
+// после него за primary закрепляются все чтения
+func (r *ReplicaRouter) written(id string) {
+	if r == nil || r.window <= 0 {
+		return
+	}
+
+	until := r.now().Add(r.window)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id == "" {
+		r.bulkPinnedTo = until
+		return
+	}
+	if len(r.pinnedOrders) >= pinSweepThreshold {
+		now := r.now()
+		for k, t := range r.pinnedOrders {
+			if !now.Before(t) {
+				delete(r.pinnedOrders, k)
+			}
+		}
+	}
+	r.pinnedOrders[id] = until
+}
+
+// failed выводит реплику из ротации, если чтение с нее закончилось ошибкой соединения;
+// повтор RetryingOrderRepository уйдет на другую реплику или primary
+func (r *ReplicaRouter) failed(ctx context.Context, db *sqlx.DB, err error) {
+	// истекший контекст вызывающего - не повод выводить реплику
+	if r == nil || err == nil || ctx.Err() != nil || errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	for _, rep := range r.replicas {
+		if rep.db == db {
+			r.markUnhealthy(rep, err)
+			return
+		}
+	}
+}
+
+func (r *ReplicaRouter) pinned(id string) bool {
+	if r.window <= 0 {
+		return false
+	}
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Before(r.bulkPinnedTo) {
+		return true
+	}
+	until, ok := r.pinnedOrders[id]
+	return ok && now.Before(until)
+}
+
+// CheckHealth пингует реплики и обновляет их состояние
+func (r *ReplicaRouter) CheckHealth(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, defaultReplicaPingTimeout)
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+
+		if err != nil {
+			r.markUnhealthy(rep, err)
+			continue
+		}
+		if !rep.healthy.Swap(true) {
+			r.logger.Info("read replica is back in rotation", "replica", rep.index)
+		}
+	}
+}
+
+// Run проверяет здоровье реплик с периодом interval до отмены ctx
+func (r *ReplicaRouter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckHealth(ctx)
+		}
+	}
+}
+
+// Healthy - число реплик в ротации
+func (r *ReplicaRouter) Healthy() int {
+	healthy := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// Shutdown закрывает соединения с репликами; primary закрывает его владелец
+func (r *ReplicaRouter) Shutdown(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, rep := range r.replicas {
+		if err := rep.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("replica %d: %w", rep.index, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		r.logger.Error("failed to close read replicas", "error", err)
+		return fmt.Errorf("%w: %v", ErrDatabaseConnectionFailed, err)
+	}
+	return nil
+}
+
+func (r *ReplicaRouter) markUnhealthy(rep *replica, err error) {
+	if rep.healthy.Swap(false) {
+		r.logger.Warn("read replica removed from rotation, reads fall back to other replicas or primary",
+			"replica", rep.index,
+			"error", err,
+		)
+	}
+}
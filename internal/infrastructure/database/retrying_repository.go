@@ -13,12 +13,16 @@ import (
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 )
 
+// RetryingOrderRepository повторяет вызовы по политике reads (GetOrder, списки, счетчик)
+// или writes (SaveOrder, DeleteOrder, ClearOrders); повторы не выходят за дедлайн контекста вызова
 type RetryingOrderRepository struct {
 	repo   domainrepo.OrderRepository
 	logger domainrepo.Logger
-	config *RetryConfig
+	reads  *RetryConfig
+	writes *RetryConfig
 }
 
+// RetryConfig: нулевые поля берутся из значений backoff по умолчанию
 type RetryConfig struct {
 	MaxElapsedTime      time.Duration
 	InitialInterval     time.Duration
@@ -27,22 +31,67 @@ type RetryConfig struct {
 	MaxInterval         time.Duration
 }
 
-func NewRetryingOrderRepository(repo domainrepo.OrderRepository, logger domainrepo.Logger, config *RetryConfig) *RetryingOrderRepository {
-	return &RetryingOrderRepository{
+type RetryOption func(*RetryingOrderRepository)
+
+// WithReadRetry задает отдельную политику для чтений
+func WithReadRetry(config *RetryConfig) RetryOption {
+	return func(r *RetryingOrderRepository) {
+		r.reads = config
+	}
+}
+
+// WithWriteRetry задает отдельную политику для записи и удаления
+func WithWriteRetry(config *RetryConfig) RetryOption {
+	return func(r *RetryingOrderRepository) {
+		r.writes = config
+	}
+}
+
+func NewRetryingOrderRepository(repo domainrepo.OrderRepository, logger domainrepo.Logger, config *RetryConfig, opts ...RetryOption) *RetryingOrderRepository {
+	r := &RetryingOrderRepository{
 		repo:   repo,
 		logger: logger,
-		config: config,
+		reads:  config,
+		writes: config,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
-func (r *RetryingOrderRepository) withRetry(ctx context.Context, operation func() error) error {
+// NewBackOff: бюджет повторов - меньшее из MaxElapsedTime и времени до дедлайна ctx,
+// так что ожидание перед попыткой не переживает HTTP-запрос или обработку сообщения
+func NewBackOff(ctx context.Context, config *RetryConfig) backoff.BackOff {
 	expBackoff := backoff.NewExponentialBackOff()
-	expBackoff.MaxElapsedTime = r.config.MaxElapsedTime
-	expBackoff.InitialInterval = r.config.InitialInterval
-	expBackoff.RandomizationFactor = r.config.RandomizationFactor
-	expBackoff.Multiplier = r.config.Multiplier
-	expBackoff.MaxInterval = r.config.MaxInterval
+	if config.MaxElapsedTime > 0 {
+		expBackoff.MaxElapsedTime = config.MaxElapsedTime
+	}
+	if config.InitialInterval > 0 {
+		expBackoff.InitialInterval = config.InitialInterval
+	}
+	if config.RandomizationFactor > 0 {
+		expBackoff.RandomizationFactor = config.RandomizationFactor
+	}
+	if config.Multiplier > 0 {
+		expBackoff.Multiplier = config.Multiplier
+	}
+	if config.MaxInterval > 0 {
+		expBackoff.MaxInterval = config.MaxInterval
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		budget := time.Until(deadline)
+		if budget <= 0 {
+			return backoff.WithContext(&backoff.StopBackOff{}, ctx)
+		}
+		expBackoff.MaxElapsedTime = min(expBackoff.MaxElapsedTime, budget)
+	}
+	expBackoff.Reset()
+	return backoff.WithContext(expBackoff, ctx)
+}
 
+func (r *RetryingOrderRepository) withRetry(ctx context.Context, config *RetryConfig, operation func() error) error {
 	return backoff.Retry(func() error {
 		select {
 		case <-ctx.Done():
@@ -50,16 +99,19 @@ func (r *RetryingOrderRepository) withRetry(ctx context.Context, operation func(
 		default:
 		}
 
+		// при разомкнутом предохранителе повторы только продлевают ожидание вызывающего,
+		// а конфликт данных (занятый track_number) повтор не исправит
 		err := operation()
-		if err != nil && errors.Is(err, domain.ErrOrderNotFound) {
+		if err != nil && (errors.Is(err, domain.ErrOrderNotFound) || errors.Is(err, domain.ErrCircuitOpen) ||
+			errors.Is(err, domain.ErrInvalidOrder)) {
 			return backoff.Permanent(err)
 		}
 		return err
-	}, expBackoff)
+	}, NewBackOff(ctx, config))
 }
 
 func (r *RetryingOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
-	return r.withRetry(ctx, func() error {
+	return r.withRetry(ctx, r.writes, func() error {
 		err := r.repo.SaveOrder(ctx, order)
 		if err != nil {
 			r.logger.Warn("failed to save order, retrying",
@@ -86,7 +138,7 @@ func (r *RetryingOrderRepository) GetOrder(ctx context.Context, id string) (enti
 		return err
 	}
 
-	err = r.withRetry(ctx, operation)
+	err = r.withRetry(ctx, r.reads, operation)
 	return order, err
 }
 
@@ -102,7 +154,7 @@ func (r *RetryingOrderRepository) GetAllOrders(ctx context.Context, limit, offse
 		return err
 	}
 
-	err = r.withRetry(ctx, operation)
+	err = r.withRetry(ctx, r.reads, operation)
 	return orders, err
 }
 
@@ -118,12 +170,44 @@ func (r *RetryingOrderRepository) GetOrdersCount(ctx context.Context) (int, erro
 		return err
 	}
 
-	err = r.withRetry(ctx, operation)
+	err = r.withRetry(ctx, r.reads, operation)
 	return count, err
 }
 
+func (r *RetryingOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	var cursor entities.OrderCursor
+	var err error
+
+	operation := func() error {
+		cursor, err = r.repo.GetRecentCursor(ctx, n)
+		if err != nil {
+			r.logger.Warn("failed to get recent orders cursor, retrying", "error", err)
+		}
+		return err
+	}
+
+	err = r.withRetry(ctx, r.reads, operation)
+	return cursor, err
+}
+
+func (r *RetryingOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	var orders []entities.Order
+	var err error
+
+	operation := func() error {
+		orders, err = r.repo.GetOrdersAfter(ctx, after, limit)
+		if err != nil {
+			r.logger.Warn("failed to get orders page, retrying", "error", err)
+		}
+		return err
+	}
+
+	err = r.withRetry(ctx, r.reads, operation)
+	return orders, err
+}
+
 func (r *RetryingOrderRepository) DeleteOrder(ctx context.Context, id string) error {
-	return r.withRetry(ctx, func() error {
+	return r.withRetry(ctx, r.writes, func() error {
 		err := r.repo.DeleteOrder(ctx, id)
 		if err != nil {
 			r.logger.Warn("failed to delete order, retrying",
@@ -136,7 +220,7 @@ func (r *RetryingOrderRepository) DeleteOrder(ctx context.Context, id string) er
 }
 
 func (r *RetryingOrderRepository) ClearOrders(ctx context.Context) error {
-	return r.withRetry(ctx, func() error {
+	return r.withRetry(ctx, r.writes, func() error {
 		err := r.repo.ClearOrders(ctx)
 		if err != nil {
 			r.logger.Warn("failed to clear orders, retrying", "error", err)
@@ -10,8 +10,12 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger"
 )
 
@@ -39,6 +43,16 @@ func (m *MockOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(entities.OrderCursor), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]entities.Order), args.Error(1)
+}
+
 func (m *MockOrderRepository) DeleteOrder(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -187,3 +201,90 @@ func TestRetryingOrderRepository_PermanentError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "SaveOrder", 1)
 }
+
+func TestRetryingOrderRepository_ConflictNotRetried(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	logger, _ := logger.NewLogger(logger.DEV)
+	repo := NewRetryingOrderRepository(mockRepo, logger, &RetryConfig{
+		MaxElapsedTime:  time.Minute,
+		InitialInterval: 10 * time.Millisecond,
+	})
+
+	testOrder := entities.Order{OrderUID: "test123", TrackNumber: "TRACK"}
+	mockRepo.On("SaveOrder", mock.Anything, testOrder).Return(orderConflict("track_number", entities.ErrTrackNumberTaken)).Once()
+
+	err := repo.SaveOrder(context.Background(), testOrder)
+	assert.ErrorIs(t, err, domain.ErrInvalidOrder)
+	assert.ErrorIs(t, err, entities.ErrTrackNumberTaken)
+	mockRepo.AssertNumberOfCalls(t, "SaveOrder", 1)
+}
+
+// декоратор не должен менять семантику хранилища, в том числе ErrOrderNotFound без повторов
+func TestRetryingOrderRepository_Contract(t *testing.T) {
+	repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+		l, err := logger.NewLogger(logger.DEV)
+		require.NoError(t, err)
+
+		return NewRetryingOrderRepository(NewMemoryOrderRepository(l), l, &RetryConfig{
+			MaxElapsedTime:      1 * time.Second,
+			InitialInterval:     10 * time.Millisecond,
+			RandomizationFactor: 0.5,
+			Multiplier:          1.5,
+			MaxInterval:         100 * time.Millisecond,
+		})
+	})
+}
+
+func TestRetryingOrderRepository_ReadWritePolicies(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	logger, _ := logger.NewLogger(logger.DEV)
+
+	fast := &RetryConfig{InitialInterval: time.Millisecond, Multiplier: 1, MaxInterval: time.Millisecond}
+	reads := *fast
+	reads.MaxElapsedTime = 20 * time.Millisecond
+	writes := *fast
+	writes.MaxElapsedTime = time.Second
+
+	repo := NewRetryingOrderRepository(mockRepo, logger, fast, WithReadRetry(&reads), WithWriteRetry(&writes))
+
+	mockRepo.On("GetOrdersCount", mock.Anything).Return(0, errors.New("temporary error"))
+	start := time.Now()
+	_, err := repo.GetOrdersCount(context.Background())
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "reads must stop within their own budget")
+
+	testOrder := entities.Order{OrderUID: "test123"}
+	mockRepo.On("SaveOrder", mock.Anything, testOrder).Return(errors.New("temporary error")).Times(50)
+	mockRepo.On("SaveOrder", mock.Anything, testOrder).Return(nil).Once()
+	assert.NoError(t, repo.SaveOrder(context.Background(), testOrder), "writes keep retrying past the read budget")
+}
+
+func TestRetryingOrderRepository_DeadlineBudget(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	logger, _ := logger.NewLogger(logger.DEV)
+
+	repo := NewRetryingOrderRepository(mockRepo, logger, &RetryConfig{
+		MaxElapsedTime:  time.Minute,
+		InitialInterval: 10 * time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     10 * time.Millisecond,
+	})
+
+	mockRepo.On("GetOrder", mock.Anything, "test123").Return(entities.Order{}, errors.New("temporary error"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := repo.GetOrder(ctx, "test123")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second, "retries must end with the caller's deadline")
+}
+
+func TestNewBackOff_ExpiredDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	b := NewBackOff(ctx, &RetryConfig{MaxElapsedTime: time.Minute})
+	assert.Equal(t, backoff.Stop, b.NextBackOff())
+}
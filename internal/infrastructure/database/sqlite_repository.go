@@ -0,0 +1,381 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/sqlite_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// sqliteTimeLayout - текст фиксированной ширины в UTC: строки сравниваются так же, как моменты
+// времени, и keyset-условие по date_created работает без функций над колонкой
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000"
+
+// OpenSQLite открывает файл БД с внешними ключами (каскадное удаление товаров), WAL
+// и ожиданием блокировки вместо ошибки SQLITE_BUSY; транзакции сразу берут блокировку записи.
+// Драйвер modernc.org/sqlite написан на Go и собирается с CGO_ENABLED=0, как образ сервиса
+func OpenSQLite(path string, busyTimeout time.Duration) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)&_txlock=immediate",
+		path, busyTimeout.Milliseconds())
+
+	db, err := sqlx.Connect("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseConnectionFailed, err)
+	}
+	return db, nil
+}
+
+// SQLiteOrderRepository - встраиваемое хранилище для edge-установок и локальной разработки
+// на той же нормализованной схеме, что и Postgres
+type SQLiteOrderRepository struct {
+	db     *sqlx.DB
+	logger domainrepo.Logger
+}
+
+func NewSQLiteOrderRepository(db *sqlx.DB, logger domainrepo.Logger) (*SQLiteOrderRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &SQLiteOrderRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func (r *SQLiteOrderRepository) SaveOrder(ctx context.Context, order entities.Order) error {
+	if err := checkItemKeys(order); err != nil {
+		r.logger.Warn("order items repeat chrt_id", "order_uid", order.OrderUID)
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	defer tx.Rollback()
+
+	if err := r.checkTrackNumberTx(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := r.saveOrderTx(ctx, tx, order); err != nil {
+		r.logger.Error("failed to save order", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrTransactionFailed, err)
+	}
+	return nil
+}
+
+// checkTrackNumberTx проверяет UNIQUE (track_number) до вставки, чтобы занятый номер был
+// конфликтом данных, а не ошибкой драйвера. Транзакция уже держит блокировку записи (_txlock=immediate)
+func (r *SQLiteOrderRepository) checkTrackNumberTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
+	var taken bool
+	query := "SELECT EXISTS (SELECT 1 FROM orders WHERE track_number = ? AND order_uid <> ?)"
+	if err := tx.QueryRowContext(ctx, query, order.TrackNumber, order.OrderUID).Scan(&taken); err != nil {
+		r.logger.Error("failed to check track number", "error", err, "order_uid", order.OrderUID)
+		return fmt.Errorf("%w: %v", ErrOrderSaveFailed, err)
+	}
+	if taken {
+		r.logger.Warn("track number is already used", "order_uid", order.OrderUID, "track_number", order.TrackNumber)
+		return orderConflict("track_number", entities.ErrTrackNumberTaken)
+	}
+	return nil
+}
+
+func (r *SQLiteOrderRepository) saveOrderTx(ctx context.Context, tx *sqlx.Tx, order entities.Order) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO orders (
+				order_uid, track_number, entry, locale, internal_signature,
+				customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (order_uid) DO UPDATE SET
+				track_number = excluded.track_number,
+				entry = excluded.entry,
+				locale = excluded.locale,
+				internal_signature = excluded.internal_signature,
+				customer_id = excluded.customer_id,
+				delivery_service = excluded.delivery_service,
+				shardkey = excluded.shardkey,
+				sm_id = excluded.sm_id,
+				date_created = excluded.date_created,
+				oof_shard = excluded.oof_shard,
+				updated_at = CURRENT_TIMESTAMP
+	`,
+		order.OrderUID, order.TrackNumber, order.Entry, order.Locale, order.InternalSig,
+		order.CustomerID, order.DeliveryService, order.ShardKey, order.SMID,
+		sqliteTime(order.DateCreated.Time), order.OOFShard,
+	)
+	if err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	d := order.Delivery
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO delivery (order_uid, name, phone, zip, city, address, region, email)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (order_uid) DO UPDATE SET
+				name = excluded.name,
+				phone = excluded.phone,
+				zip = excluded.zip,
+				city = excluded.city,
+				address = excluded.address,
+				region = excluded.region,
+				email = excluded.email
+	`, order.OrderUID, d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email)
+	if err != nil {
+		return fmt.Errorf("delivery: %w", err)
+	}
+
+	p := order.Payment
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO payment (
+				order_uid, "transaction", request_id, currency, provider,
+				amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (order_uid) DO UPDATE SET
+				"transaction" = excluded."transaction",
+				request_id = excluded.request_id,
+				currency = excluded.currency,
+				provider = excluded.provider,
+				amount = excluded.amount,
+				payment_dt = excluded.payment_dt,
+				bank = excluded.bank,
+				delivery_cost = excluded.delivery_cost,
+				goods_total = excluded.goods_total,
+				custom_fee = excluded.custom_fee
+	`,
+		order.OrderUID, p.Transaction, p.RequestID, p.Currency, p.Provider,
+		p.Amount, p.PaymentDT, p.Bank, p.DeliveryCost, p.GoodsTotal, p.CustomFee,
+	)
+	if err != nil {
+		return fmt.Errorf("payment: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM items WHERE order_uid = ?", order.OrderUID); err != nil {
+		return fmt.Errorf("items: %w", err)
+	}
+	for _, item := range order.Items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO items (
+					chrt_id, order_uid, track_number, price, rid, name,
+					sale, size, total_price, nm_id, brand, status
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			item.ChrtID, order.OrderUID, item.TrackNumber, item.Price, item.RID, item.Name,
+			item.Sale, item.Size, item.TotalPrice, item.NmID, item.Brand, item.Status,
+		)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", item.ChrtID, err)
+		}
+	}
+	return nil
+}
+
+// sqliteOrdersSelect - аналог ordersSelect; COALESCE нужен для заказов без строк доставки или оплаты,
+// transaction в SQLite - ключевое слово и берется в кавычки
+const sqliteOrdersSelect = `
+		SELECT
+				o.order_uid, o.track_number, o.entry, o.locale, COALESCE(o.internal_signature, ''),
+				o.customer_id, o.delivery_service, COALESCE(o.shardkey, ''), o.sm_id, o.date_created, COALESCE(o.oof_shard, ''),
+				COALESCE(d.name, ''), COALESCE(d.phone, ''), COALESCE(d.zip, ''), COALESCE(d.city, ''),
+				COALESCE(d.address, ''), COALESCE(d.region, ''), COALESCE(d.email, ''),
+				COALESCE(p."transaction", ''), COALESCE(p.request_id, ''), COALESCE(p.currency, ''),
+				COALESCE(p.provider, ''), COALESCE(p.amount, 0), COALESCE(p.payment_dt, 0), COALESCE(p.bank, ''),
+				COALESCE(p.delivery_cost, 0), COALESCE(p.goods_total, 0), COALESCE(p.custom_fee, 0)
+		FROM orders o
+		LEFT JOIN delivery d ON o.order_uid = d.order_uid
+		LEFT JOIN payment p ON o.order_uid = p.order_uid
+`
+
+func (r *SQLiteOrderRepository) GetOrder(ctx context.Context, id string) (entities.Order, error) {
+	orders, err := r.queryOrders(ctx, sqliteOrdersSelect+" WHERE o.order_uid = ?", id)
+	if err != nil {
+		r.logger.Error("failed to get order", "error", err, "order_uid", id)
+		return entities.Order{}, err
+	}
+	if len(orders) == 0 {
+		return entities.Order{}, domain.ErrOrderNotFound
+	}
+	return orders[0], nil
+}
+
+func (r *SQLiteOrderRepository) GetAllOrders(ctx context.Context, limit, offset int) ([]entities.Order, error) {
+	return r.queryOrders(ctx, sqliteOrdersSelect+`
+		ORDER BY o.order_uid
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+}
+
+func (r *SQLiteOrderRepository) GetRecentCursor(ctx context.Context, n int) (entities.OrderCursor, error) {
+	var cursor entities.OrderCursor
+	var created entities.Timestamp
+	err := r.db.QueryRowContext(ctx, `
+		SELECT date_created, order_uid
+		FROM orders
+		ORDER BY date_created DESC, order_uid DESC
+		LIMIT 1 OFFSET ?
+	`, n).Scan(&created, &cursor.OrderUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.OrderCursor{}, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get recent orders cursor", "error", err, "n", n)
+		return entities.OrderCursor{}, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	cursor.DateCreated = created.Time
+	return cursor, nil
+}
+
+func (r *SQLiteOrderRepository) GetOrdersAfter(ctx context.Context, after entities.OrderCursor, limit int) ([]entities.Order, error) {
+	if after.IsZero() {
+		return r.queryOrders(ctx, sqliteOrdersSelect+`
+		ORDER BY o.date_created, o.order_uid
+		LIMIT ?
+	`, limit)
+	}
+
+	return r.queryOrders(ctx, sqliteOrdersSelect+`
+		WHERE (o.date_created, o.order_uid) > (?, ?)
+		ORDER BY o.date_created, o.order_uid
+		LIMIT ?
+	`, sqliteTime(after.DateCreated), after.OrderUID, limit)
+}
+
+// queryOrders дочитывает и закрывает выборку заказов до запроса товаров, чтобы не держать
+// два соединения с файлом БД. Товары идут в порядке вставки (rowid),
+// как их прислал продюсер, чтобы заказ после чтения оставался Equal исходному
+func (r *SQLiteOrderRepository) queryOrders(ctx context.Context, query string, args ...interface{}) ([]entities.Order, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get orders", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	var orders []entities.Order
+	ordersIdx := make(map[string]int)
+	for rows.Next() {
+		var o entities.Order
+		d, p := &o.Delivery, &o.Payment
+		if err := rows.Scan(
+			&o.OrderUID, &o.TrackNumber, &o.Entry, &o.Locale, &o.InternalSig,
+			&o.CustomerID, &o.DeliveryService, &o.ShardKey, &o.SMID, &o.DateCreated, &o.OOFShard,
+			&d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email,
+			&p.Transaction, &p.RequestID, &p.Currency, &p.Provider, &p.Amount,
+			&p.PaymentDT, &p.Bank, &p.DeliveryCost, &p.GoodsTotal, &p.CustomFee,
+		); err != nil {
+			rows.Close()
+			r.logger.Error("failed to scan order", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+		orders = append(orders, o)
+		ordersIdx[o.OrderUID] = len(orders) - 1
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	uids := make([]string, len(orders))
+	for i, o := range orders {
+		uids[i] = o.OrderUID
+	}
+	itemsQuery, itemsArgs, err := sqlx.In(`
+		SELECT
+				chrt_id, order_uid, track_number, price, rid, name,
+				sale, size, total_price, nm_id, brand, status
+		FROM items
+		WHERE order_uid IN (?)
+		ORDER BY order_uid, rowid
+	`, uids)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+
+	itemRows, err := r.db.QueryContext(ctx, itemsQuery, itemsArgs...)
+	if err != nil {
+		r.logger.Error("failed to get items for orders", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item entities.Item
+		var orderUID string
+		if err := itemRows.Scan(
+			&item.ChrtID, &orderUID, &item.TrackNumber, &item.Price, &item.RID, &item.Name,
+			&item.Sale, &item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
+		); err != nil {
+			r.logger.Error("failed to scan item", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+		}
+		if i, ok := ordersIdx[orderUID]; ok {
+			orders[i].Items = append(orders[i].Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return orders, nil
+}
+
+func (r *SQLiteOrderRepository) GetOrdersCount(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM orders").Scan(&count); err != nil {
+		r.logger.Error("failed to get orders count", "error", err)
+		return 0, fmt.Errorf("%w: %v", ErrQueryFailed, err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteOrderRepository) DeleteOrder(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM orders WHERE order_uid = ?", id)
+	if err != nil {
+		r.logger.Error("failed to delete order", "error", err, "order_uid", id)
+		return fmt.Errorf("%w: %v", ErrOrderDeleteFailed, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOrderDeleteFailed, err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteOrderRepository) ClearOrders(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM orders"); err != nil {
+		r.logger.Error("failed to clear orders", "error", err)
+		return fmt.Errorf("%w: %v", ErrOrderClearFailed, err)
+	}
+	return nil
+}
+
+func (r *SQLiteOrderRepository) Shutdown(ctx context.Context) error {
+	if err := r.db.Close(); err != nil {
+		r.logger.Error("failed to close sqlite database", "error", err)
+		return fmt.Errorf("%w: %v", ErrDatabaseConnectionFailed, err)
+	}
+	return nil
+}
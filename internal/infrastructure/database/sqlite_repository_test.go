@@ -0,0 +1,69 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/database/sqlite_repository_test.go
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository/repositorytest"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/migrations"
+)
+
+func newSQLiteRepo(t *testing.T) *SQLiteOrderRepository {
+	l, err := logger.NewLogger(logger.DEV)
+	require.NoError(t, err)
+
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "orders.db"), 5*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, migrations.RunSQLiteMigrations(context.Background(), db.DB, l))
+
+	repo, err := NewSQLiteOrderRepository(db, l)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Shutdown(context.Background()) })
+	return repo
+}
+
+func TestSQLiteOrderRepository_Contract(t *testing.T) {
+	repositorytest.RunOrderRepository(t, func(t *testing.T) domainrepo.OrderRepository {
+		return newSQLiteRepo(t)
+	})
+}
+
+func TestSQLiteOrderRepository_DeleteCascades(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveOrder(ctx, repositorytest.NewOrder("a", time.Now())))
+	require.NoError(t, repo.DeleteOrder(ctx, "a"))
+
+	var items int
+	require.NoError(t, repo.db.Get(&items, "SELECT COUNT(*) FROM items"))
+	assert.Zero(t, items)
+
+	_, err := repo.GetOrder(ctx, "a")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.ErrorIs(t, repo.DeleteOrder(ctx, "a"), domain.ErrOrderNotFound)
+}
+
+func TestOpenSQLite_AppliesPragmas(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "orders.db"), 5*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	var journal string
+	var foreignKeys, busyTimeout int
+	require.NoError(t, db.Get(&journal, "PRAGMA journal_mode"))
+	require.NoError(t, db.Get(&foreignKeys, "PRAGMA foreign_keys"))
+	require.NoError(t, db.Get(&busyTimeout, "PRAGMA busy_timeout"))
+	assert.Equal(t, "wal", journal)
+	assert.Equal(t, 1, foreignKeys)
+	assert.Equal(t, 5000, busyTimeout)
+}
@@ -0,0 +1,103 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/kafka/cache_bus.go
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+)
+
+// CacheBus рассылает события инвалидации через топик Kafka. Каждый экземпляр
+// читает топик без consumer group с последнего offset, поэтому получает все
+// события, опубликованные после старта. Топик должен иметь одну партицию
+type CacheBus struct {
+	writer *kafka.Writer
+	reader *kafka.Reader
+	logger domainrepo.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewCacheBus(brokers []string, topic string, l domainrepo.Logger) *CacheBus {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &CacheBus{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			BatchTimeout:           10 * time.Millisecond,
+			RequiredAcks:           kafka.RequireOne,
+			AllowAutoTopicCreation: true,
+		},
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			Topic:       topic,
+			Partition:   0,
+			StartOffset: kafka.LastOffset,
+			MaxWait:     500 * time.Millisecond,
+		}),
+		logger: l,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (b *CacheBus) Publish(ctx context.Context, event entities.CacheEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKafkaMessageSend, err)
+	}
+	msg := kafka.Message{Key: []byte(event.OrderUID), Value: payload}
+	if err := b.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrKafkaMessageSend, err)
+	}
+	return nil
+}
+
+func (b *CacheBus) Start(handler func(entities.CacheEvent)) error {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			msg, err := b.reader.ReadMessage(b.ctx)
+			if err != nil {
+				if b.ctx.Err() != nil {
+					return
+				}
+				b.logger.Error("failed to read cache invalidation event", "error", err)
+				time.Sleep(time.Second)
+				continue
+			}
+
+			var event entities.CacheEvent
+			if err := json.Unmarshal(msg.Value, &event); err != nil {
+				b.logger.Warn("invalid cache invalidation payload", "error", err, "offset", msg.Offset)
+				continue
+			}
+			handler(event)
+		}
+	}()
+
+	b.logger.Info("cache invalidation listener started", "backend", "kafka", "topic", b.reader.Config().Topic)
+	return nil
+}
+
+func (b *CacheBus) Shutdown(ctx context.Context) error {
+	b.cancel()
+	b.wg.Wait()
+
+	if err := b.reader.Close(); err != nil {
+		return err
+	}
+	return b.writer.Close()
+}
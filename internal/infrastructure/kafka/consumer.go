@@ -4,6 +4,7 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -15,8 +16,12 @@ import (
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/breaker"
 )
 
+// breakerPollInterval - пауза перед повтором, когда предохранитель полуоткрыт и пробные вызовы заняты
+const breakerPollInterval = time.Second
+
 type Consumer struct {
 	reader         *kafka.Reader
 	dlqWriter      *kafka.Writer
@@ -28,6 +33,9 @@ type Consumer struct {
 	retryConfig    *RetryConfig
 	maxRetries     int
 	processingTime time.Duration
+	// storageBreaker и dlqBreaker могут быть nil; пока любой разомкнут, сообщения не читаются
+	storageBreaker *breaker.Breaker
+	dlqBreaker     *breaker.Breaker
 }
 
 type RetryConfig struct {
@@ -54,6 +62,8 @@ func NewConsumer(
 	commitInterval time.Duration,
 	batchTimeout time.Duration,
 	batchSize int,
+	storageBreaker *breaker.Breaker,
+	dlqBreaker *breaker.Breaker,
 ) domainrepo.EventConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        brokers,
@@ -87,6 +97,8 @@ func NewConsumer(
 		retryConfig:    retryConfig,
 		maxRetries:     maxRetries,
 		processingTime: processingTime,
+		storageBreaker: storageBreaker,
+		dlqBreaker:     dlqBreaker,
 	}
 }
 
@@ -110,11 +122,31 @@ func (c *Consumer) consumeLoop() {
 			c.logger.Info("Kafka consumer loop stopped")
 			return
 		default:
+			if wait := c.breakerWait(); wait > 0 {
+				c.logger.Warn("circuit breaker is open, Kafka consumer paused", "retry_after", wait)
+				c.pause(wait)
+				continue
+			}
 			c.processMessage()
 		}
 	}
 }
 
+// breakerWait - сколько осталось до пробных вызовов у разомкнутого предохранителя
+func (c *Consumer) breakerWait() time.Duration {
+	return max(c.storageBreaker.RetryAfter(), c.dlqBreaker.RetryAfter())
+}
+
+// pause ждет d или остановки консьюмера
+func (c *Consumer) pause(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-c.ctx.Done():
+	case <-timer.C:
+	}
+}
+
 func (c *Consumer) processMessage() {
 	msg, err := c.reader.FetchMessage(c.ctx)
 	if err != nil {
@@ -135,6 +167,18 @@ func (c *Consumer) processMessage() {
 
 	startTime := time.Now()
 	err = c.processWithRetry(msg)
+	// при разомкнутом предохранителе сообщение не уходит в DLQ, а ждет восстановления хранилища
+	for errors.Is(err, domain.ErrCircuitOpen) {
+		if c.ctx.Err() != nil {
+			return
+		}
+		c.logger.Warn("storage circuit breaker is open, message processing postponed",
+			"key", string(msg.Key),
+			"offset", msg.Offset,
+		)
+		c.pause(max(c.breakerWait(), breakerPollInterval))
+		err = c.processWithRetry(msg)
+	}
 	processingTime := time.Since(startTime)
 
 	if err != nil {
@@ -179,6 +223,13 @@ func (c *Consumer) processWithRetry(msg kafka.Message) error {
 		}
 
 		_, err = c.svc.SaveOrder(ctx, order)
+		if errors.Is(err, domain.ErrInvalidOrder) {
+			return backoff.Permanent(err)
+		}
+		if errors.Is(err, domain.ErrCircuitOpen) {
+			lastErr = err
+			return backoff.Permanent(err)
+		}
 		if err != nil {
 			lastErr = err
 			c.logger.Warn("failed to process message, retrying",
@@ -207,10 +258,16 @@ func (c *Consumer) handleProcessingError(msg kafka.Message, err error, processin
 		"processing_time", processingTime,
 	)
 
-	if err := c.sendToDLQ(msg); err != nil {
+	dlqErr := c.sendToDLQ(msg)
+	// разомкнутый предохранитель DLQ держит сообщение, пока запись не станет возможной
+	for errors.Is(dlqErr, domain.ErrCircuitOpen) && c.ctx.Err() == nil {
+		c.pause(max(c.breakerWait(), breakerPollInterval))
+		dlqErr = c.sendToDLQ(msg)
+	}
+	if dlqErr != nil {
 		c.logger.Error("failed to send message to DLQ",
 			"key", string(msg.Key),
-			"error", err,
+			"error", dlqErr,
 		)
 	}
 }
@@ -222,7 +279,7 @@ func (c *Consumer) decodeOrder(data []byte) (entities.Order, error) {
 	}
 
 	if err := order.Validate(); err != nil {
-		return entities.Order{}, fmt.Errorf("%w: %v", domain.ErrInvalidOrder, err)
+		return entities.Order{}, fmt.Errorf("%w: %w", domain.ErrInvalidOrder, err)
 	}
 
 	return order, nil
@@ -242,7 +299,13 @@ func (c *Consumer) sendToDLQ(msg kafka.Message) error {
 		}),
 	}
 
-	if err := c.dlqWriter.WriteMessages(ctx, dlqMsg); err != nil {
+	err := c.dlqBreaker.Execute(func() error {
+		return c.dlqWriter.WriteMessages(ctx, dlqMsg)
+	})
+	if errors.Is(err, domain.ErrCircuitOpen) {
+		return err
+	}
+	if err != nil {
 		return fmt.Errorf("%w: %v", ErrKafkaMessageSend, err)
 	}
 
@@ -46,19 +46,19 @@ func NewLogger(m mode) (
 }
 
 func (l *Logger) Debug(msg string, fields ...interface{}) {
-	l.zap.Debug(msg, l.convertFields(fields)...)
+	l.zap.Debug(redactString(msg), l.convertFields(fields)...)
 }
 
 func (l *Logger) Info(msg string, fields ...interface{}) {
-	l.zap.Info(msg, l.convertFields(fields)...)
+	l.zap.Info(redactString(msg), l.convertFields(fields)...)
 }
 
 func (l *Logger) Warn(msg string, fields ...interface{}) {
-	l.zap.Warn(msg, l.convertFields(fields)...)
+	l.zap.Warn(redactString(msg), l.convertFields(fields)...)
 }
 
 func (l *Logger) Error(msg string, fields ...interface{}) {
-	l.zap.Error(msg, l.convertFields(fields)...)
+	l.zap.Error(redactString(msg), l.convertFields(fields)...)
 }
 
 func (l *Logger) Sync() error {
@@ -88,7 +88,7 @@ func (l *Logger) convertFields(fields []interface{}) []zap.Field {
 			continue
 		}
 
-		value := fields[i+1]
+		value := redactValue(key, fields[i+1])
 
 		switch v := value.(type) {
 		case string:
@@ -0,0 +1,79 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger/redact.go
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+// ключи полей лога, значения которых всегда маскируются
+var sensitiveKeys = map[string]string{
+	"name":     entities.PIIName,
+	"phone":    entities.PIIPhone,
+	"address":  entities.PIIAddress,
+	"email":    entities.PIIEmail,
+	"delivery": "",
+}
+
+var (
+	emailRe      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phoneRe      = regexp.MustCompile(`\+\d{6,15}`)
+	failingRowRe = regexp.MustCompile(`(?s)Failing row contains \(.*\)`)
+)
+
+// redactString вычищает email и телефоны из произвольного текста. Имена в свободном
+// тексте не распознать, поэтому строку целиком из ошибок Postgres
+// "Failing row contains (...)" выбрасываем
+func redactString(s string) string {
+	s = failingRowRe.ReplaceAllString(s, "Failing row contains (redacted)")
+	s = emailRe.ReplaceAllStringFunc(s, entities.MaskEmail)
+	return phoneRe.ReplaceAllStringFunc(s, entities.MaskPhone)
+}
+
+// redactValue возвращает значение, безопасное для записи в лог
+func redactValue(key string, value interface{}) interface{} {
+	if field, ok := sensitiveKeys[strings.ToLower(key)]; ok {
+		if s, isString := value.(string); isString && field != "" {
+			return entities.MaskPII(field, s)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return redactString(v)
+	case error:
+		return redactedError{msg: redactString(v.Error())}
+	case entities.Delivery:
+		return v.Masked()
+	case *entities.Delivery:
+		if v == nil {
+			return v
+		}
+		return v.Masked()
+	case entities.Order:
+		v.Delivery = v.Delivery.Masked()
+		return v
+	case *entities.Order:
+		if v == nil {
+			return v
+		}
+		order := *v
+		order.Delivery = order.Delivery.Masked()
+		return order
+	case fmt.Stringer:
+		return redactString(v.String())
+	default:
+		return value
+	}
+}
+
+type redactedError struct {
+	msg string
+}
+
+func (e redactedError) Error() string {
+	return e.msg
+}
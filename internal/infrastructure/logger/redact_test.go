@@ -0,0 +1,57 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/logger/redact_test.go
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+func TestLogger_RedactsPII(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	l := &Logger{zap: zap.New(core)}
+
+	pgErr := errors.New(`new row violates check constraint: Failing row contains (abc, Test Testov, +9720000000, test@gmail.com)`)
+	l.Error("failed to save delivery",
+		"error", pgErr,
+		"phone", "+79991231234",
+		"email", "john.doe@gmail.com",
+		"delivery", entities.Delivery{Name: "Test Testov", Phone: "+9720000000", City: "Kiryat Mozkin"},
+		"order_id", "b563feb7b2b84b6test",
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+
+	var dump strings.Builder
+	for k, v := range entries[0].ContextMap() {
+		dump.WriteString(k)
+		dump.WriteString("=")
+		dump.WriteString(stringify(v))
+		dump.WriteString("\n")
+	}
+	out := dump.String()
+
+	for _, secret := range []string{"+9720000000", "+79991231234", "test@gmail.com", "john.doe@gmail.com", "Testov"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains PII %q:\n%s", secret, out)
+		}
+	}
+	for _, kept := range []string{"Failing row contains (redacted)", "+7******1234", "j*******@gmail.com", "Kiryat Mozkin", "b563feb7b2b84b6test"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("log output is missing %q:\n%s", kept, out)
+		}
+	}
+}
+
+func stringify(v interface{}) string {
+	return fmt.Sprintf("%v", v)
+}
@@ -4,15 +4,23 @@ package migrations
 import (
 	"context"
 	"database/sql"
+	"embed"
 	"fmt"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 )
 
+// миграции SQLite встроены в бинарник: для него нет отдельного образа с файлами миграций
+//
+//go:embed sqlite/*.sql
+var sqliteMigrations embed.FS
+
 // контекст не участвует, оставлен для консистентности
 func RunMigrations(ctx context.Context, db *sql.DB, migrationsPath string, l domainrepo.Logger) error {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
@@ -29,6 +37,33 @@ func RunMigrations(ctx context.Context, db *sql.DB, migrationsPath string, l dom
 		return fmt.Errorf("failed to create migration instance: %w", err)
 	}
 
+	return apply(m, l)
+}
+
+// RunSQLiteMigrations применяет собственный набор миграций SQLite
+func RunSQLiteMigrations(ctx context.Context, db *sql.DB, l domainrepo.Logger) error {
+	source, err := iofs.New(sqliteMigrations, "sqlite")
+	if err != nil {
+		l.Error("failed to open sqlite migrations", "error", err)
+		return fmt.Errorf("failed to open sqlite migrations: %w", err)
+	}
+
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		l.Error("failed to create migration driver", "error", err)
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		l.Error("failed to create migration instance", "error", err)
+		return fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	return apply(m, l)
+}
+
+func apply(m *migrate.Migrate, l domainrepo.Logger) error {
 	oldVersion, _, _ := m.Version()
 
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
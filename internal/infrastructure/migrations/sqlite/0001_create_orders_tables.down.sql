@@ -0,0 +1,5 @@
+-- // github.com/Dmitrii-Khramtsov/orderservice/internal/infrastructure/migrations/sqlite/0001_create_orders_tables.down.sql
+DROP TABLE IF EXISTS items;
+DROP TABLE IF EXISTS payment;
+DROP TABLE IF EXISTS delivery;
+DROP TABLE IF EXISTS orders;
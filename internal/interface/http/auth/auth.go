@@ -0,0 +1,150 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/auth/auth.go
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var roleLevel = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleLevel[r]; !ok {
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+	return r, nil
+}
+
+// AtLeast - роль не ниже min
+func (r Role) AtLeast(min Role) bool {
+	return roleLevel[r] >= roleLevel[min]
+}
+
+// CanViewPII - видит ли роль данные доставки без маскирования
+func (r Role) CanViewPII() bool {
+	return r.AtLeast(RoleOperator)
+}
+
+type ctxKey struct{}
+
+type principalKey struct{}
+
+// Anonymous - принципал запросов без токена
+const Anonymous = "anonymous"
+
+func WithRole(ctx context.Context, r Role) context.Context {
+	return context.WithValue(ctx, ctxKey{}, r)
+}
+
+// WithPrincipal сохраняет идентификатор API-ключа, которым подписан запрос
+func WithPrincipal(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, principalKey{}, id)
+}
+
+// PrincipalFromContext - id API-ключа запроса или Anonymous
+func PrincipalFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(principalKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Anonymous
+}
+
+func FromContext(ctx context.Context) Role {
+	if r, ok := ctx.Value(ctxKey{}).(Role); ok {
+		return r
+	}
+	return RoleViewer
+}
+
+// APIKey: ID попадает в журналы (кто удалил данные клиента), сам токен - никогда
+type APIKey struct {
+	ID    string
+	Token string
+	Role  Role
+}
+
+type apiKey struct {
+	id    string
+	token []byte
+	role  Role
+}
+
+// Authenticator определяет роль по заголовку Authorization: Bearer <token>;
+// запросы без токена получают defaultRole, с неизвестным токеном - 401
+type Authenticator struct {
+	keys        []apiKey
+	defaultRole Role
+}
+
+func NewAuthenticator(keys []APIKey, defaultRole Role) *Authenticator {
+	a := &Authenticator{defaultRole: defaultRole}
+	for _, k := range keys {
+		a.keys = append(a.keys, apiKey{id: k.ID, token: []byte(k.Token), role: k.Role})
+	}
+	return a
+}
+
+func (a *Authenticator) lookup(token string) (apiKey, bool) {
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(k.token, []byte(token)) == 1 {
+			return k, true
+		}
+	}
+	return apiKey{}, false
+}
+
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r.WithContext(WithRole(r.Context(), a.defaultRole)))
+			return
+		}
+
+		token, found := strings.CutPrefix(header, "Bearer ")
+		key, ok := a.lookup(token)
+		if !found || !ok {
+			writeError(w, http.StatusUnauthorized, "Invalid API token")
+			return
+		}
+
+		ctx := WithPrincipal(WithRole(r.Context(), key.role), key.id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole пропускает запрос, только если роль не ниже min
+func RequireRole(min Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !FromContext(r.Context()).AtLeast(min) {
+				writeError(w, http.StatusForbidden, "Insufficient permissions")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -14,6 +14,13 @@ const (
 	ErrCodeInvalidRequest   ErrorCode = "invalid_request"
 	ErrCodeOrderNotFound    ErrorCode = "order_not_found"
 	ErrCodeInternalError    ErrorCode = "internal_error"
+	ErrCodeValidationFailed ErrorCode = "validation_failed"
+	ErrCodeOrderNotCached   ErrorCode = "order_not_cached"
+
+	ErrCodeCacheWarmInProgress   ErrorCode = "cache_warm_in_progress"
+	ErrCodeCacheVerifyInProgress ErrorCode = "cache_verify_in_progress"
+	ErrCodeStorageUnavailable    ErrorCode = "storage_unavailable"
+	ErrCodeRequestTimeout        ErrorCode = "request_timeout"
 )
 
 type HTTPError struct {
@@ -0,0 +1,86 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/handler/cache_handler.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/application"
+	httperrors "github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http"
+)
+
+// GetCacheStats отдает статистику кэша этого экземпляра
+func (h *OrderHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.svc.CacheStats())
+}
+
+// GetCacheRestoreProgress отдает состояние прогрева кэша из БД
+func (h *OrderHandler) GetCacheRestoreProgress(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.restorer.Progress())
+}
+
+// WarmCache запускает прогрев в фоне; ход прогрева - GET /admin/cache/restore.
+// Прогрев, упавший раньше, продолжается с сохраненного курсора
+func (h *OrderHandler) WarmCache(w http.ResponseWriter, r *http.Request) {
+	if !h.restorer.TryStart(context.Background()) {
+		h.writeError(w, http.StatusConflict, httperrors.NewHTTPError(
+			httperrors.ErrCodeCacheWarmInProgress,
+			"Cache warm-up already in progress",
+			"",
+		))
+		return
+	}
+
+	h.logger.Info("cache warm-up started")
+	h.writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status": "started",
+	})
+}
+
+// VerifyCache сверяет выборку кэша с БД и отдает отчет; чинит ли сверка расхождения,
+// задает cache.consistency.repair
+func (h *OrderHandler) VerifyCache(w http.ResponseWriter, r *http.Request) {
+	report, err := h.svc.VerifyCache(r.Context())
+	if errors.Is(err, application.ErrCacheVerificationInProgress) {
+		h.writeError(w, http.StatusConflict, httperrors.NewHTTPError(
+			httperrors.ErrCodeCacheVerifyInProgress,
+			"Cache verification already in progress",
+			"",
+		))
+		return
+	}
+	if err != nil {
+		h.logger.Warn("cache verification interrupted", "error", err, "checked", report.Checked)
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// ClearCache очищает только кэш; DELETE /orders дополнительно удаляет заказы из БД
+func (h *OrderHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
+	h.svc.ClearCache()
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "cleared",
+	})
+}
+
+func (h *OrderHandler) EvictCachedOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if !h.svc.EvictCachedOrder(id) {
+		h.writeError(w, http.StatusNotFound, httperrors.NewHTTPError(
+			httperrors.ErrCodeOrderNotCached,
+			"Order not in cache",
+			"",
+		))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "evicted",
+		"order_id": id,
+	})
+}
@@ -0,0 +1,138 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/handler/customer_handler.go
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+	httperrors "github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/auth"
+)
+
+// exportManifest - export.json в архиве; сами заказы лежат в orders/<order_uid>.json,
+// где order_uid экранирован, чтобы "/" и ".." в нем не выводили запись из каталога
+type exportManifest struct {
+	CustomerID string             `json:"customer_id"`
+	ExportedAt time.Time          `json:"exported_at"`
+	OrderUIDs  []string           `json:"order_uids"`
+	Erasures   []entities.Erasure `json:"erasures"`
+}
+
+func buildExportArchive(export entities.CustomerExport) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	writeFile := func(name string, v interface{}) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	manifest := exportManifest{
+		CustomerID: export.CustomerID,
+		ExportedAt: export.ExportedAt,
+		OrderUIDs:  make([]string, 0, len(export.Orders)),
+		Erasures:   export.Erasures,
+	}
+	for _, order := range export.Orders {
+		manifest.OrderUIDs = append(manifest.OrderUIDs, order.OrderUID)
+		if err := writeFile("orders/"+url.PathEscape(order.OrderUID)+".json", order); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeFile("export.json", manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (h *OrderHandler) customerID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeError(w, http.StatusBadRequest, httperrors.NewHTTPError(
+			httperrors.ErrCodeInvalidRequest,
+			"Customer ID is required",
+			"",
+		))
+		return "", false
+	}
+	return id, true
+}
+
+func (h *OrderHandler) ExportCustomerData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := h.customerID(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := h.svc.ExportCustomerData(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "failed to export customer data")
+		return
+	}
+
+	archive, err := buildExportArchive(export)
+	if err != nil {
+		h.logger.Error("failed to build customer export archive", "customer_id", id, "error", err)
+		h.writeError(w, http.StatusInternalServerError, httperrors.NewHTTPError(
+			httperrors.ErrCodeInternalError,
+			"Internal server error",
+			"",
+		))
+		return
+	}
+
+	h.logger.Info("customer data export sent",
+		"customer_id", id,
+		"orders", len(export.Orders),
+	)
+
+	filename := fmt.Sprintf("customer-%s-%s.zip", id, export.ExportedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(archive); err != nil {
+		h.logger.Error("Failed to write response", "error", err)
+	}
+}
+
+func (h *OrderHandler) EraseCustomerData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := h.customerID(w, r)
+	if !ok {
+		return
+	}
+
+	erasure, err := h.svc.EraseCustomerData(ctx, id, auth.PrincipalFromContext(ctx))
+	if err != nil {
+		h.handleServiceError(w, err, "failed to erase customer data")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "erased",
+		"erasure": erasure,
+		"count":   len(erasure.OrderUIDs),
+	})
+}
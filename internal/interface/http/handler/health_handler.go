@@ -0,0 +1,92 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/handler/health_handler.go
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
+)
+
+const (
+	healthOK       = "ok"
+	healthDegraded = "degraded"
+)
+
+// Health отдает 503, пока разомкнут хотя бы один предохранитель: экземпляр не может писать заказы
+func (h *OrderHandler) Health(w http.ResponseWriter, r *http.Request) {
+	stats := h.breakerStats()
+	status, code := healthOK, http.StatusOK
+	for _, s := range stats {
+		if s.State == entities.CircuitOpen {
+			status, code = healthDegraded, http.StatusServiceUnavailable
+		}
+	}
+
+	h.writeJSON(w, code, map[string]interface{}{
+		"status":           status,
+		"circuit_breakers": stats,
+	})
+}
+
+// Metrics отдает состояние предохранителей в текстовом формате Prometheus
+func (h *OrderHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	stats := h.breakerStats()
+
+	var buf bytes.Buffer
+	writeMetric(&buf, "orderservice_circuit_breaker_state", "gauge",
+		"Circuit breaker state: 0 closed, 1 half-open, 2 open", stats,
+		func(s entities.CircuitBreakerStats) float64 { return circuitStateValue(s.State) })
+	writeMetric(&buf, "orderservice_circuit_breaker_failures", "gauge",
+		"Consecutive failures counted by the circuit breaker", stats,
+		func(s entities.CircuitBreakerStats) float64 { return float64(s.Failures) })
+	writeMetric(&buf, "orderservice_circuit_breaker_opens_total", "counter",
+		"Times the circuit breaker has opened", stats,
+		func(s entities.CircuitBreakerStats) float64 { return float64(s.Opens) })
+	writeMetric(&buf, "orderservice_circuit_breaker_rejected_total", "counter",
+		"Calls rejected while the circuit breaker was open", stats,
+		func(s entities.CircuitBreakerStats) float64 { return float64(s.Rejected) })
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Error("Failed to write response", "error", err)
+	}
+}
+
+func (h *OrderHandler) breakerStats() []entities.CircuitBreakerStats {
+	stats := make([]entities.CircuitBreakerStats, 0, len(h.breakers))
+	for _, b := range h.breakers {
+		stats = append(stats, b.Stats())
+	}
+	return stats
+}
+
+// retryAfterSeconds - значение Retry-After для 503: до ближайших пробных вызовов, не меньше секунды
+func (h *OrderHandler) retryAfterSeconds() int {
+	seconds := 1
+	for _, b := range h.breakers {
+		seconds = max(seconds, int(math.Ceil(b.RetryAfter().Seconds())))
+	}
+	return seconds
+}
+
+func writeMetric(buf *bytes.Buffer, name, kind, help string, stats []entities.CircuitBreakerStats, value func(entities.CircuitBreakerStats) float64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	for _, s := range stats {
+		fmt.Fprintf(buf, "%s{breaker=%q} %g\n", name, s.Name, value(s))
+	}
+}
+
+func circuitStateValue(state entities.CircuitState) float64 {
+	switch state {
+	case entities.CircuitHalfOpen:
+		return 1
+	case entities.CircuitOpen:
+		return 2
+	default:
+		return 0
+	}
+}
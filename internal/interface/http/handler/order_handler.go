@@ -3,9 +3,12 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 
@@ -14,20 +17,53 @@ import (
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/domain/entities"
 	domainrepo "github.com/Dmitrii-Khramtsov/orderservice/internal/domain/repository"
 	httperrors "github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http"
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/auth"
 )
 
 type OrderHandler struct {
-	svc    application.OrderServiceInterface
-	logger domainrepo.Logger
+	svc      application.OrderServiceInterface
+	restorer domainrepo.CacheRestorer
+	logger   domainrepo.Logger
+	// breakers попадают в /health и /metrics и задают Retry-After для ответов 503
+	breakers []domainrepo.CircuitBreaker
 }
 
-func NewOrderHandler(s application.OrderServiceInterface, l domainrepo.Logger) *OrderHandler {
+func NewOrderHandler(s application.OrderServiceInterface, restorer domainrepo.CacheRestorer, l domainrepo.Logger, breakers ...domainrepo.CircuitBreaker) *OrderHandler {
 	return &OrderHandler{
-		svc:    s,
-		logger: l,
+		svc:      s,
+		restorer: restorer,
+		logger:   l,
+		breakers: breakers,
 	}
 }
 
+// orderView - заказ в ответе API; поля заказа остаются на верхнем уровне,
+// чтобы не ломать существующих клиентов
+type orderView struct {
+	entities.Order
+	ReportingValue *entities.Money `json:"reporting_value,omitempty"`
+}
+
+// toView маскирует PII доставки для ролей без доступа к персональным данным
+func (h *OrderHandler) toView(ctx context.Context, order entities.Order) orderView {
+	view := orderView{Order: order}
+	if !auth.FromContext(ctx).CanViewPII() {
+		view.Delivery = order.Delivery.Masked()
+	}
+	if value, ok := h.svc.ReportingValue(order); ok {
+		view.ReportingValue = &value
+	}
+	return view
+}
+
+func (h *OrderHandler) toViews(ctx context.Context, orders []entities.Order) []orderView {
+	views := make([]orderView, 0, len(orders))
+	for _, order := range orders {
+		views = append(views, h.toView(ctx, order))
+	}
+	return views
+}
+
 func (h *OrderHandler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -51,6 +87,14 @@ func (h *OrderHandler) writeError(w http.ResponseWriter, status int, err *httper
 	})
 }
 
+func (h *OrderHandler) writeValidationError(w http.ResponseWriter, errs entities.ValidationErrors) {
+	h.writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"error":  "Order validation failed",
+		"code":   httperrors.ErrCodeValidationFailed,
+		"fields": errs,
+	})
+}
+
 func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -97,12 +141,43 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *OrderHandler) handleServiceError(w http.ResponseWriter, err error, context string) {
+func (h *OrderHandler) handleServiceError(w http.ResponseWriter, err error, errContext string) {
 	var appErr *application.AppError
+	var validationErrs entities.ValidationErrors
 
 	switch {
+	case errors.As(err, &validationErrs):
+		h.logger.Warn("order validation failed",
+			"error", err,
+			"violations", len(validationErrs),
+		)
+		h.writeValidationError(w, validationErrs)
+
+	case errors.Is(err, domain.ErrCircuitOpen):
+		h.logger.Warn("storage unavailable, request rejected",
+			"error", err,
+			"context", errContext,
+		)
+		w.Header().Set("Retry-After", strconv.Itoa(h.retryAfterSeconds()))
+		h.writeError(w, http.StatusServiceUnavailable, httperrors.NewHTTPError(
+			httperrors.ErrCodeStorageUnavailable,
+			"Storage is temporarily unavailable",
+			"",
+		))
+
+	case errors.Is(err, context.DeadlineExceeded):
+		h.logger.Warn("request deadline exceeded",
+			"error", err,
+			"context", errContext,
+		)
+		h.writeError(w, http.StatusGatewayTimeout, httperrors.NewHTTPError(
+			httperrors.ErrCodeRequestTimeout,
+			"Request timed out",
+			"",
+		))
+
 	case errors.As(err, &appErr):
-		h.logger.Error(context,
+		h.logger.Error(errContext,
 			"error", err,
 			"error_code", appErr.Code,
 			"operation", appErr.Op,
@@ -136,7 +211,7 @@ func (h *OrderHandler) handleServiceError(w http.ResponseWriter, err error, cont
 	default:
 		h.logger.Error("unexpected error",
 			"error", err,
-			"context", context,
+			"context", errContext,
 		)
 		h.writeError(w, http.StatusInternalServerError, httperrors.NewHTTPError(
 			httperrors.ErrCodeInternalError,
@@ -168,7 +243,7 @@ func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("order retrieved successfully",
 		"order_id", id,
 	)
-	h.writeJSON(w, http.StatusOK, order)
+	h.writeJSON(w, http.StatusOK, h.toView(ctx, order))
 }
 
 func (h *OrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
@@ -184,7 +259,7 @@ func (h *OrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		"count", len(orders),
 	)
 	h.writeJSON(w, http.StatusOK, map[string]interface{}{
-		"orders": orders,
+		"orders": h.toViews(ctx, orders),
 		"count":  len(orders),
 	})
 }
@@ -232,3 +307,60 @@ func (h *OrderHandler) Clear(w http.ResponseWriter, r *http.Request) {
 		"count":  0,
 	})
 }
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+func parsePagination(r *http.Request) (int, int, error) {
+	limit, offset := defaultPageLimit, 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		limit = n
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
+
+func (h *OrderHandler) GetFlagged(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, httperrors.NewHTTPError(
+			httperrors.ErrCodeInvalidRequest,
+			err.Error(),
+			"",
+		))
+		return
+	}
+
+	flags, err := h.svc.GetFlaggedOrders(ctx, limit, offset)
+	if err != nil {
+		h.handleServiceError(w, err, "failed to get flagged orders")
+		return
+	}
+
+	h.logger.Info("flagged orders retrieved successfully",
+		"count", len(flags),
+	)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"flags":  flags,
+		"count":  len(flags),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
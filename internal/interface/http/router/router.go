@@ -2,18 +2,36 @@
 package router
 
 import (
+	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/auth"
 	"github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/handler"
 	"github.com/go-chi/chi/v5"
 	"net/http"
 )
 
-func New(h *handler.OrderHandler) *chi.Mux {
+func New(h *handler.OrderHandler, middlewares ...func(http.Handler) http.Handler) *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(middlewares...)
+	r.Get("/health", h.Health)
+	r.Get("/metrics", h.Metrics)
 	r.Post("/orders", h.Create)
+	r.Get("/orders/flagged", h.GetFlagged)
 	r.Get("/orders/{id}", h.GetByID)
 	r.Get("/orders", h.GetAll)
-	r.Delete("/orders/{id}", h.Delete)
-	r.Delete("/orders", h.Clear)
+	r.With(auth.RequireRole(auth.RoleAdmin)).Delete("/orders/{id}", h.Delete)
+	r.With(auth.RequireRole(auth.RoleAdmin)).Delete("/orders", h.Clear)
+
+	r.With(auth.RequireRole(auth.RoleOperator)).Get("/customers/{id}/data-export", h.ExportCustomerData)
+	r.With(auth.RequireRole(auth.RoleAdmin)).Post("/customers/{id}/erase", h.EraseCustomerData)
+
+	r.Route("/admin/cache", func(r chi.Router) {
+		r.Use(auth.RequireRole(auth.RoleAdmin))
+		r.Get("/", h.GetCacheStats)
+		r.Delete("/", h.ClearCache)
+		r.Post("/warm", h.WarmCache)
+		r.Post("/verify", h.VerifyCache)
+		r.Get("/restore", h.GetCacheRestoreProgress)
+		r.Delete("/{id}", h.EvictCachedOrder)
+	})
 
 	fs := http.FileServer(http.Dir("./web"))
 	r.Handle("/*", fs)
@@ -0,0 +1,20 @@
+// github.com/Dmitrii-Khramtsov/orderservice/internal/interface/http/router/timeout.go
+package router
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestTimeout задает дедлайн контекста запроса: по нему обрываются запросы к БД и их повторы,
+// а ответ 504 пишет обработчик
+func RequestTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
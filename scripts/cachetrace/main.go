@@ -0,0 +1,89 @@
+// github.com/Dmitrii-Khramtsov/orderservice/scripts/cachetrace/main.go
+//
+// Генерирует синтетическую трассу обращений к кэшу для бенчмарков политик вытеснения
+// (internal/infrastructure/cache/testdata/synthetic_access_trace.txt.gz).
+// Формат: строка на операцию, "G <order_uid>" - чтение заказа (при промахе
+// сервис кладет заказ в кэш), "S <order_uid>" - запись без чтения
+// (новый заказ из Kafka, GetAllOrders или CacheRestorer).
+//
+// Модель нагрузки: чтения по Zipf среди заказов, поток новых заказов
+// и периодические массовые выгрузки, которые затрагивают каждый заказ один раз.
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+)
+
+func main() {
+	out := flag.String("out", "internal/infrastructure/cache/testdata/synthetic_access_trace.txt.gz", "output file")
+	ops := flag.Int("ops", 60000, "number of operations")
+	orders := flag.Int("orders", 20000, "number of existing orders")
+	scanEvery := flag.Int("scan-every", 12000, "bulk scan period in operations")
+	scanSize := flag.Int("scan-size", 2000, "orders touched by one bulk scan")
+	seed := flag.Int64("seed", 42, "random seed")
+	flag.Parse()
+
+	if err := generate(*out, *ops, *orders, *scanEvery, *scanSize, *seed); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate пишет трассу и возвращает первую ошибку записи, сжатия или закрытия файла:
+// иначе обрезанная трасса молча попала бы в бенчмарки
+func generate(out string, ops, orders, scanEvery, scanSize int, seed int64) error {
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(gz)
+
+	writeTrace(w, ops, orders, scanEvery, scanSize, seed)
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeTrace не проверяет ошибки Fprintf: bufio.Writer запоминает первую и вернет ее из Flush
+func writeTrace(w *bufio.Writer, ops, orders, scanEvery, scanSize int, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(orders-1))
+	next := orders
+	scanPos := 0
+
+	for i := 0; i < ops; i++ {
+		if i > 0 && i%scanEvery == 0 {
+			// массовая выгрузка: последовательно по "холодному" диапазону
+			for j := 0; j < scanSize; j++ {
+				fmt.Fprintf(w, "S %x\n", (orders/2+scanPos)%next)
+				scanPos++
+			}
+		}
+
+		switch n := r.Intn(100); {
+		case n < 5:
+			fmt.Fprintf(w, "S %x\n", next)
+			next++
+		default:
+			// горячие заказы разбросаны по диапазону, а не лежат в его начале
+			id := (int(zipf.Uint64()) * 7919) % orders
+			fmt.Fprintf(w, "G %x\n", id)
+		}
+	}
+}
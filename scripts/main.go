@@ -63,7 +63,7 @@ func generateRandomOrder() entities.Order {
 		DeliveryService: "delivery_service_" + generateRandomString(5),
 		ShardKey:        generateRandomString(5),
 		SMID:            rand.Intn(100),
-		DateCreated:     time.Now().Format(time.RFC3339),
+		DateCreated:     entities.NewTimestamp(time.Now()),
 		OOFShard:        generateRandomString(5),
 		Delivery: entities.Delivery{
 			Name:    "Customer " + generateRandomString(5),
@@ -80,7 +80,7 @@ func generateRandomOrder() entities.Order {
 			Currency:     "USD",
 			Provider:     "payment_provider_" + generateRandomString(5),
 			Amount:       rand.Intn(1000) + 100,
-			PaymentDT:    time.Now().Unix(),
+			PaymentDT:    entities.NewUnixTime(time.Now()),
 			Bank:         "Bank_" + generateRandomString(5),
 			DeliveryCost: rand.Intn(100),
 			GoodsTotal:   rand.Intn(1000) + 100,